@@ -5,96 +5,78 @@ import (
 	models_user "dine-server/src/models/users"
 	"dine-server/src/utils"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Authenticate is a middleware function for verifying JWT tokens and user identity.
-func Authenticate(c *gin.Context) {
-	// Get token age from environment variables
-	accessTokenAge := utils.ParseDuration(os.Getenv("ACCESS_TOKEN_AGE"), 3600)    // Default 3600 seconds (1 hour)
-	refreshTokenAge := utils.ParseDuration(os.Getenv("REFRESH_TOKEN_AGE"), 86400) // Default 86400 seconds (24 hours)
-
-	// Get the Authorization header or cookie
-	var accessToken string
-	accessToken, err := c.Cookie("access_token")
-	if err != nil || accessToken == "" {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			// Ensure the header contains "Bearer" token
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) == 2 && strings.ToLower(tokenParts[0]) == "bearer" {
-				accessToken = tokenParts[1]
-			}
-		}
-	}
-
-	// If access token is missing or invalid, check the refresh token
+// AuthenticateRequest verifies the access token and the identity of the user of the request and attaches them to the context.
+// It responds itself and returns false when the request is not authenticated. Expired access tokens are renewed
+// by the client at /auth/refresh, which rotates the refresh token of the session.
+func AuthenticateRequest(c *gin.Context) bool {
+	accessToken := requestAccessToken(c)
 	if accessToken == "" {
-		refreshToken, err := c.Cookie("refresh_token")
-		if err != nil || refreshToken == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "No valid tokens provided"})
-			c.Abort()
-			return
-		}
-
-		// Validate the refresh token
-		userID, role, err := utils.ValidateAndExtractToken(refreshToken, "REFRESH")
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
-			c.Abort()
-			return
-		}
-
-		// Generate new access and refresh tokens
-		newAccessToken, err := utils.GenerateToken(models_user.UserJwt{ID: userID, Role: role}, "ACCESS")
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate new access tokens"})
-			c.Abort()
-			return
-		}
-		newRefreshToken, err := utils.GenerateToken(models_user.UserJwt{ID: userID, Role: role}, "REFRESH")
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate new refresh tokens"})
-			c.Abort()
-			return
-		}
-
-		// Set the new tokens in cookies
-		c.SetCookie("access_token", newAccessToken, int(accessTokenAge), "/", "", false, true)
-		c.SetCookie("refresh_token", newRefreshToken, int(refreshTokenAge), "/", "", false, true)
-
-		// Attach user information to the request context
-		c.Set("userID", userID)
-		c.Set("role", role)
-
-		// Continue processing the request
-		c.Next()
-		return
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "No valid tokens provided"})
+		return false
 	}
 
 	// Validate and extract user information from the access token
-	userID, role, err := utils.ValidateAndExtractToken(accessToken, "ACCESS")
-	if err != nil {
+	claims, err := utils.ValidateToken(accessToken, "ACCESS")
+	if err != nil || !sessionActive(claims) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired access token"})
-		c.Abort()
-		return
+		return false
 	}
 
 	// Verify the user exists in the database
 	var user models_user.User
-	if err := postgres.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+	if err := postgres.DB.Where("id = ?", claims.ID).First(&user).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-		c.Abort()
-		return
+		return false
 	}
 
 	// Attach user information to the request context
-	c.Set("userID", userID)
-	c.Set("role", role)
+	c.Set("userID", claims.ID)
+	c.Set("role", claims.Role)
+	c.Set("sessionID", claims.SessionID)
 
-	// Continue processing the request
-	c.Next()
+	return true
+}
+
+// IdentifyRequest attaches the user to the context when a valid access token is sent,
+// anonymous requests are left as they are. Used by public routes that show more to staff.
+func IdentifyRequest(c *gin.Context) {
+	if accessToken := requestAccessToken(c); accessToken != "" {
+		if claims, err := utils.ValidateToken(accessToken, "ACCESS"); err == nil && sessionActive(claims) {
+			c.Set("userID", claims.ID)
+			c.Set("role", claims.Role)
+			c.Set("sessionID", claims.SessionID)
+		}
+	}
+}
+
+// sessionActive reports whether the session of a token is still signed in.
+// Tokens of a session that was logged out, revoked or expired are refused.
+func sessionActive(claims models_user.UserJwt) bool {
+	var active int64
+	if err := postgres.DB.Model(&models_user.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", claims.SessionID, claims.ID, time.Now()).
+		Count(&active).Error; err != nil {
+		return false
+	}
+	return active > 0
+}
+
+// requestAccessToken reads the access token from its cookie or the Authorization header
+func requestAccessToken(c *gin.Context) string {
+	if accessToken, err := c.Cookie("access_token"); err == nil && accessToken != "" {
+		return accessToken
+	}
+
+	// Ensure the header contains "Bearer" token
+	tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(tokenParts) == 2 && strings.ToLower(tokenParts[0]) == "bearer" {
+		return tokenParts[1]
+	}
+	return ""
 }
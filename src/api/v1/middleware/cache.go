@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"dine-server/src/config/cache"
+	postgres "dine-server/src/config/database"
+	models_restaurant "dine-server/src/models/restaurants"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateMenuTree drops the cached menu tree of the restaurant after a successful write,
+// and of the other outlets of its brand, which serve the menus it shares
+func InvalidateMenuTree(c *gin.Context) {
+	c.Next()
+
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return
+	}
+	if c.Writer.Status() < http.StatusBadRequest {
+		restaurantID := c.Param("restaurant_id")
+		cache.MenuTrees.Delete(restaurantID)
+
+		var outlets []string
+		postgres.DB.Model(&models_restaurant.Restaurant{}).
+			Where("brand_id = (SELECT brand_id FROM restaurants WHERE id = ?)", restaurantID).
+			Pluck("id", &outlets)
+		for _, outlet := range outlets {
+			cache.MenuTrees.Delete(outlet)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/env"
+	models_user "dine-server/src/models/users"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedUser refuses users who have not verified the email or phone required with
+// REQUIRE_VERIFIED_EMAIL and REQUIRE_VERIFIED_PHONE. Used on the routes creating restaurants and buying plans.
+func RequireVerifiedUser(c *gin.Context) {
+	requireEmail := env.AuthVar["REQUIRE_VERIFIED_EMAIL"] == "true"
+	requirePhone := env.AuthVar["REQUIRE_VERIFIED_PHONE"] == "true"
+	if role, _ := c.Get("role"); role == "admin" || (!requireEmail && !requirePhone) {
+		c.Next()
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	var user models_user.User
+	if err := postgres.DB.Select("id", "verified_email", "verified_phone").First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		c.Abort()
+		return
+	}
+
+	if requireEmail && !user.VerifiedEmail {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email first", "code": "email_not_verified"})
+		c.Abort()
+		return
+	}
+	if requirePhone && !user.VerifiedPhone {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your phone first", "code": "phone_not_verified"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
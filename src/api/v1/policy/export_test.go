@@ -0,0 +1,16 @@
+package policy
+
+import (
+	models_restaurant "dine-server/src/models/restaurants"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stub replaces how requests are authenticated and permissions are checked, the returned function restores them
+func Stub(auth func(c *gin.Context) bool, permission func(c *gin.Context, restaurantID string, permission models_restaurant.Permission) (bool, error)) func() {
+	previousAuth, previousPermission := authenticate, hasPermission
+	authenticate, hasPermission = auth, permission
+	return func() {
+		authenticate, hasPermission = previousAuth, previousPermission
+	}
+}
@@ -0,0 +1,227 @@
+package policy
+
+import (
+	"dine-server/src/api/v1/middleware"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Access is who may call a route, before any permission is checked
+type Access int
+
+const (
+	AccessPublic   Access = iota // Anyone, no user is attached
+	AccessGuest                  // Anyone, the user is attached when a valid token is sent
+	AccessUser                   // Any signed in user, the handler checks what the user owns
+	AccessPlatform               // Platform admins holding the permission
+	AccessStaff                  // The owner and staff of the restaurant whose role holds the permission
+)
+
+// Rule is the policy of a route
+type Rule struct {
+	Access     Access
+	Permission models_restaurant.Permission
+	// Restaurant finds the restaurant the request acts on, the restaurant_id parameter when nil
+	Restaurant func(c *gin.Context) (string, error)
+}
+
+// Public lets anyone call the route
+func Public() Rule {
+	return Rule{Access: AccessPublic}
+}
+
+// Guest lets anyone call the route and attaches the user when signed in
+func Guest() Rule {
+	return Rule{Access: AccessGuest}
+}
+
+// User lets any signed in user call the route
+func User() Rule {
+	return Rule{Access: AccessUser}
+}
+
+// Platform lets platform admins call the route
+func Platform(permission models_restaurant.Permission) Rule {
+	return Rule{Access: AccessPlatform, Permission: permission}
+}
+
+// Staff lets the staff of the restaurant of the URL call the route when their role holds the permission
+func Staff(permission models_restaurant.Permission) Rule {
+	return Rule{Access: AccessStaff, Permission: permission}
+}
+
+// StaffOf is Staff for routes naming the restaurant another way than the restaurant_id parameter
+func StaffOf(permission models_restaurant.Permission, restaurant func(c *gin.Context) (string, error)) Rule {
+	return Rule{Access: AccessStaff, Permission: permission, Restaurant: restaurant}
+}
+
+// authenticate and hasPermission are variables so the tests can check the rules without a database
+var (
+	authenticate  = middleware.AuthenticateRequest
+	hasPermission = utils.HasPermission
+)
+
+// Enforce applies the policy of the route: it authenticates the user, checks the permission
+// and that the resources of the URL belong to the restaurant. Routes without a rule are denied.
+func Enforce(c *gin.Context) {
+	rule, ok := rules[routeKey(c.Request.Method, c.FullPath())]
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "No policy is declared for this route"})
+		c.Abort()
+		return
+	}
+
+	switch rule.Access {
+	case AccessGuest:
+		middleware.IdentifyRequest(c)
+	case AccessUser, AccessPlatform, AccessStaff:
+		if !authenticate(c) {
+			c.Abort()
+			return
+		}
+	}
+
+	restaurantID := c.Param("restaurant_id")
+	if rule.Access == AccessStaff && rule.Restaurant != nil {
+		var err error
+		if restaurantID, err = rule.Restaurant(c); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	if rule.Access == AccessPlatform || rule.Access == AccessStaff {
+		allowed, err := hasPermission(c, restaurantID, rule.Permission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check your permissions"})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to do this, it needs the " + string(rule.Permission) + " permission"})
+			c.Abort()
+			return
+		}
+	}
+
+	if rule.Access == AccessStaff || c.Param("restaurant_id") != "" {
+		if err := checkResources(c, restaurantID); err != nil {
+			abortWithError(c, err)
+			return
+		}
+	}
+
+	c.Next()
+}
+
+// Verify reports the routes of the API without a rule, the rules without a route, and the restaurant routes
+// with parameters whose owner cannot be checked. It runs at startup so a route cannot ship without a policy.
+func Verify(routes gin.RoutesInfo) error {
+	var problems []string
+	declared := make(map[string]bool)
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		key := routeKey(route.Method, route.Path)
+		declared[key] = true
+
+		rule, ok := rules[key]
+		if !ok {
+			problems = append(problems, key+" has no policy")
+			continue
+		}
+
+		switch rule.Access {
+		case AccessPlatform:
+			if !strings.HasPrefix(string(rule.Permission), "platform.") {
+				problems = append(problems, key+" needs a platform.* permission")
+			}
+		case AccessStaff:
+			if !models_restaurant.StaffRoleOwner.Can(rule.Permission) {
+				problems = append(problems, key+" needs "+string(rule.Permission)+" which no role is granted")
+			}
+			if rule.Restaurant == nil && !hasParam(route.Path, "restaurant_id") {
+				problems = append(problems, key+" has no restaurant to check the permission in")
+			}
+		}
+
+		if rule.Access == AccessStaff || hasParam(route.Path, "restaurant_id") {
+			problems = append(problems, verifyParams(key, route.Path)...)
+		}
+	}
+
+	for key := range rules {
+		if !declared[key] {
+			problems = append(problems, key+" has a policy but no route")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid route policies:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// verifyParams checks that every parameter of a restaurant route is a resource whose owner can be checked, or a plain value
+func verifyParams(key, path string) []string {
+	var problems []string
+	for _, param := range params(path) {
+		if plainParams[param] {
+			continue
+		}
+		resource, ok := resources[param]
+		if !ok {
+			problems = append(problems, key+" has the parameter "+param+" which is neither a resource nor a plain value")
+			continue
+		}
+		if resource.scope == "" && !hasParam(path, resource.parent) {
+			problems = append(problems, key+" has "+param+" without its parent "+resource.parent)
+		}
+	}
+	return problems
+}
+
+// abortWithError responds with a not found error, or with a server error for anything else
+func abortWithError(c *gin.Context, err error) {
+	var missing notFoundError
+	if errors.As(err, &missing) {
+		c.JSON(http.StatusNotFound, gin.H{"error": missing.Error()})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check your permissions"})
+	}
+	c.Abort()
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// params returns the names of the parameters of a route path in order
+func params(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+func hasParam(path, name string) bool {
+	for _, param := range params(path) {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
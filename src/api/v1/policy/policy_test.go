@@ -0,0 +1,163 @@
+package policy_test
+
+import (
+	"dine-server/src/api/v1/policy"
+	models_restaurant "dine-server/src/models/restaurants"
+	routes "dine-server/src/routes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	restaurantID = "6f1c2a9e-3b1d-4c55-9a0e-2d7f1b8c4e10"
+	ownerID      = "0b8e5d2c-7a41-4f3e-8c19-5e6d7a8b9c01"
+	waiterID     = "1c9f6e3d-8b52-4a4f-9d2a-6f7e8b9cad02"
+	strangerID   = "2dae7f4e-9c63-4b5a-ae3b-7a8f9cadbe03"
+	managerID    = "3ebf8a5f-ad74-4c6b-bf4c-8b9aadbecf04"
+	cashierID    = "4fc09b6a-be85-4d7c-8a5d-9cabbecfda05"
+	kitchenID    = "5ad1ac7b-cf96-4e8d-9b6e-adbccfdaeb06"
+)
+
+// members are the staff of the test restaurant, in place of the database
+var members = map[string]models_restaurant.StaffRole{
+	ownerID:   models_restaurant.StaffRoleOwner,
+	managerID: models_restaurant.StaffRoleManager,
+	cashierID: models_restaurant.StaffRoleCashier,
+	waiterID:  models_restaurant.StaffRoleWaiter,
+	kitchenID: models_restaurant.StaffRoleKitchen,
+}
+
+func newEngine(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	routes.V1Routes(r)
+	stubAccess(t)
+	return r
+}
+
+// stubAccess signs requests in with test headers, and takes permissions from the roles of members
+func stubAccess(t *testing.T) {
+	restore := policy.Stub(func(c *gin.Context) bool {
+		userID := c.GetHeader("X-Test-User")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "No valid tokens provided"})
+			return false
+		}
+		c.Set("userID", userID)
+		c.Set("role", c.GetHeader("X-Test-Role"))
+		return true
+	}, func(c *gin.Context, restaurant string, permission models_restaurant.Permission) (bool, error) {
+		if c.GetString("role") == "admin" {
+			return true, nil
+		}
+		if restaurant != restaurantID {
+			return false, nil
+		}
+		return members[c.GetString("userID")].Can(permission), nil
+	})
+	t.Cleanup(restore)
+}
+
+func TestEveryRouteHasAPolicy(t *testing.T) {
+	r := newEngine(t)
+	if err := policy.Verify(r.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRulesDenyAccess(t *testing.T) {
+	r := newEngine(t)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		userID string
+		role   string
+		status int
+	}{
+		{"staff route without token", http.MethodGet, "/api/v1/" + restaurantID + "/tables/", "", "", http.StatusUnauthorized},
+		{"staff route for a user outside the staff", http.MethodGet, "/api/v1/" + restaurantID + "/tables/", strangerID, "restaurant_admin", http.StatusForbidden},
+		{"staff route for the owner of another restaurant", http.MethodPost, "/api/v1/" + strangerID + "/menus/", ownerID, "restaurant_admin", http.StatusForbidden},
+		{"staff route for a role without the permission", http.MethodPost, "/api/v1/" + restaurantID + "/menus/", waiterID, "restaurant_admin", http.StatusForbidden},
+		{"staff management for a waiter", http.MethodGet, "/api/v1/" + restaurantID + "/staff/", waiterID, "restaurant_admin", http.StatusForbidden},
+		{"staff management for a cashier", http.MethodGet, "/api/v1/" + restaurantID + "/staff/", cashierID, "user", http.StatusForbidden},
+		{"menu creation by the kitchen", http.MethodPost, "/api/v1/" + restaurantID + "/menus/", kitchenID, "user", http.StatusForbidden},
+		{"restaurant deletion by a manager", http.MethodDelete, "/api/v1/restaurants/" + restaurantID, managerID, "user", http.StatusForbidden},
+		{"platform route without token", http.MethodGet, "/api/v1/users/get-all", "", "", http.StatusUnauthorized},
+		{"platform route for a non admin", http.MethodGet, "/api/v1/users/get-all", ownerID, "restaurant_admin", http.StatusForbidden},
+		{"platform route for a restaurant owner", http.MethodPost, "/api/v1/plans/", ownerID, "restaurant_admin", http.StatusForbidden},
+		{"user route without token", http.MethodGet, "/api/v1/auth/sessions", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.userID != "" {
+				req.Header.Set("X-Test-User", tc.userID)
+				req.Header.Set("X-Test-Role", tc.role)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status {
+				t.Fatalf("%s %s: got status %d, want %d: %s", tc.method, tc.path, w.Code, tc.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRulesAllowAccess(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		route  string
+		path   string
+		userID string
+		role   string
+	}{
+		{"staff invitation by the owner", http.MethodPost, "/api/v1/:restaurant_id/staff/invitations", "/api/v1/" + restaurantID + "/staff/invitations", ownerID, "restaurant_admin"},
+		{"restaurant settings by the owner", http.MethodPut, "/api/v1/:restaurant_id/order-rules/", "/api/v1/" + restaurantID + "/order-rules/", ownerID, "restaurant_admin"},
+		{"menu creation by a manager", http.MethodPost, "/api/v1/:restaurant_id/menus/", "/api/v1/" + restaurantID + "/menus/", managerID, "user"},
+		{"staff list for a manager", http.MethodGet, "/api/v1/:restaurant_id/staff/", "/api/v1/" + restaurantID + "/staff/", managerID, "user"},
+		{"order list for a cashier", http.MethodGet, "/api/v1/orders/restaurant/", "/api/v1/orders/restaurant/?restaurant_id=" + restaurantID, cashierID, "user"},
+		{"session opened by a cashier", http.MethodPost, "/api/v1/:restaurant_id/sessions/", "/api/v1/" + restaurantID + "/sessions/", cashierID, "user"},
+		{"table list for a waiter", http.MethodGet, "/api/v1/:restaurant_id/tables/", "/api/v1/" + restaurantID + "/tables/", waiterID, "user"},
+		{"session opened by a waiter", http.MethodPost, "/api/v1/:restaurant_id/sessions/", "/api/v1/" + restaurantID + "/sessions/", waiterID, "user"},
+		{"kitchen tickets for the kitchen", http.MethodGet, "/api/v1/:restaurant_id/kitchen/tickets/", "/api/v1/" + restaurantID + "/kitchen/tickets/", kitchenID, "user"},
+		{"item 86ed by the kitchen", http.MethodPost, "/api/v1/:restaurant_id/inventory/86/", "/api/v1/" + restaurantID + "/inventory/86/", kitchenID, "user"},
+		{"platform route for an admin", http.MethodPost, "/api/v1/plans/", "/api/v1/plans/", strangerID, "admin"},
+		{"user route for any user", http.MethodGet, "/api/v1/auth/sessions", "/api/v1/auth/sessions", strangerID, "user"},
+	}
+
+	// Only the policy runs, the handlers are replaced so allowed requests stop before the database
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	registered := make(map[string]bool)
+	for _, tc := range cases {
+		if key := tc.method + " " + tc.route; !registered[key] {
+			registered[key] = true
+			r.Handle(tc.method, tc.route, policy.Enforce, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		}
+	}
+	stubAccess(t)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("X-Test-User", tc.userID)
+			req.Header.Set("X-Test-Role", tc.role)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("%s %s: got status %d, want the handler to be reached: %s", tc.method, tc.path, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"bytes"
+	"database/sql"
+	postgres "dine-server/src/config/database"
+	"encoding/json"
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// notFoundError is returned when a resource of the URL does not exist or belongs to another restaurant
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e) + " not found"
+}
+
+// resource is a row named by a URL parameter that belongs to a restaurant
+type resource struct {
+	name  string // Used in the not found error
+	table string
+	// parent is the parameter of the resource the row belongs to and column the column holding it.
+	// When the route has the parent, the row must belong to it, the parent itself being checked on its own.
+	parent, column string
+	// scope matches the rows of the restaurant @restaurant, used when the route has no parent
+	scope string
+}
+
+// restaurantRow is a resource with its own restaurant_id column
+func restaurantRow(name, table string) resource {
+	return resource{name: name, table: table, scope: "restaurant_id = @restaurant"}
+}
+
+// resources are the parameters naming rows of a restaurant, checked on every restaurant route
+var resources = map[string]resource{
+	"menu_id":     restaurantRow("Menu", "menus"),
+	"category_id": {name: "Category", table: "menu_categories", parent: "menu_id", column: "menu_id"},
+	"item_id": {name: "Item", table: "menu_items", parent: "category_id", column: "category_id",
+		// Outlets track stock and 86 the items of the menus their brand shares
+		scope: "menu_id IN (SELECT id FROM menus WHERE restaurant_id = @restaurant OR brand_id = (SELECT brand_id FROM restaurants WHERE id = @restaurant))"},
+	"group_id":      {name: "Modifier group", table: "modifier_groups", parent: "item_id", column: "menu_item_id"},
+	"modifier_id":   {name: "Modifier", table: "modifiers", parent: "group_id", column: "group_id"},
+	"combo_id":      {name: "Combo", table: "combos", parent: "menu_id", column: "menu_id"},
+	"override_id":   restaurantRow("Menu override", "menu_overrides"),
+	"version_id":    restaurantRow("Menu version", "menu_versions"),
+	"rule_id":       restaurantRow("Pricing rule", "pricing_rules"),
+	"station_id":    restaurantRow("Station", "stations"),
+	"ticket_id":     restaurantRow("Ticket", "kitchen_tickets"),
+	"stock_id":      restaurantRow("Stock", "item_stocks"),
+	"alert_id":      restaurantRow("Alert", "stock_alerts"),
+	"ingredient_id": restaurantRow("Ingredient", "ingredients"),
+	"supplier_id":   restaurantRow("Supplier", "suppliers"),
+	"receipt_id":    restaurantRow("Receipt", "purchase_receipts"),
+	"table_id":      restaurantRow("Table", "restaurant_tables"),
+	"session_id":    restaurantRow("Session", "table_sessions"),
+	"payment_id":    {name: "Payment", table: "session_payments", parent: "session_id", column: "session_id"},
+	"closure_id":    restaurantRow("Closure", "restaurant_closures"),
+	"member_id":     restaurantRow("Staff member", "staff_members"),
+	"invitation_id": restaurantRow("Invitation", "staff_invitations"),
+}
+
+// plainParams are the parameters of restaurant routes that name no row of the restaurant
+var plainParams = map[string]bool{
+	"restaurant_id": true,
+	"id":            true, // The order or restaurant the rule resolves the restaurant from
+	"locale":        true,
+	"kind":          true,
+	"template_id":   true, // Templates belong to the platform
+	"brand_id":      true, // Brands check their admin in the handlers
+}
+
+// checkResources verifies that every resource of the URL belongs to the restaurant
+func checkResources(c *gin.Context, restaurantID string) error {
+	for _, param := range c.Params {
+		resource, ok := resources[param.Key]
+		if !ok {
+			continue
+		}
+		if _, err := uuid.FromString(param.Value); err != nil {
+			return notFoundError(resource.name)
+		}
+
+		query := postgres.DB.Table(resource.table).Where("id = ?", param.Value)
+		if parent := c.Param(resource.parent); resource.parent != "" && parent != "" {
+			query = query.Where(resource.column+" = ?", parent)
+		} else {
+			if _, err := uuid.FromString(restaurantID); err != nil {
+				return notFoundError(resource.name)
+			}
+			query = query.Where(resource.scope, sql.Named("restaurant", restaurantID))
+		}
+
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return notFoundError(resource.name)
+		}
+	}
+	return nil
+}
+
+// paramRestaurant finds the restaurant in a parameter of the URL
+func paramRestaurant(name string) func(c *gin.Context) (string, error) {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
+}
+
+// queryRestaurant finds the restaurant in the restaurant_id query parameter
+func queryRestaurant(c *gin.Context) (string, error) {
+	return c.Query("restaurant_id"), nil
+}
+
+// bodyRestaurant finds the restaurant in the restaurant_id field of the JSON body, leaving the body for the handler
+func bodyRestaurant(c *gin.Context) (string, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var input struct {
+		RestaurantID string `json:"restaurant_id"`
+	}
+	// An invalid body is left for the handler to reject
+	_ = json.Unmarshal(body, &input)
+	return input.RestaurantID, nil
+}
+
+// orderRestaurant finds the restaurant of the order in the id parameter
+func orderRestaurant(c *gin.Context) (string, error) {
+	return ownerOf("Order", "orders", c.Param("id"))
+}
+
+// ownerOf returns the restaurant_id of a row
+func ownerOf(name, table, id string) (string, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return "", notFoundError(name)
+	}
+
+	var restaurantIDs []string
+	if err := postgres.DB.Table(table).Where("id = ?", id).Pluck("restaurant_id", &restaurantIDs).Error; err != nil {
+		return "", err
+	}
+	if len(restaurantIDs) == 0 {
+		return "", notFoundError(name)
+	}
+	return restaurantIDs[0], nil
+}
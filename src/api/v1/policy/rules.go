@@ -0,0 +1,258 @@
+package policy
+
+// rules declare who may call each route of the API, keyed by method and full path.
+// Staff permissions are named resource.action and granted to roles in models_restaurant.RolePermissions.
+var rules = map[string]Rule{
+	// Auth
+	"POST /api/v1/auth/register":          Public(),
+	"POST /api/v1/auth/login":             Public(),
+	"GET /api/v1/auth/logout":             Guest(),
+	"GET /api/v1/auth/refresh":            Public(),
+	"GET /api/v1/auth/google":             Public(),
+	"GET /api/v1/auth/google/callback":    Public(),
+	"GET /api/v1/auth/sessions":           User(),
+	"DELETE /api/v1/auth/sessions":        User(),
+	"DELETE /api/v1/auth/sessions/:id":    User(),
+	"POST /api/v1/auth/verify/email/send": User(),
+	"POST /api/v1/auth/verify/email":      Public(),
+	"POST /api/v1/auth/verify/phone/send": User(),
+	"POST /api/v1/auth/verify/phone":      User(),
+	"POST /api/v1/auth/password/forgot":   Public(),
+	"POST /api/v1/auth/password/reset":    Public(),
+	"POST /api/v1/auth/password/change":   User(),
+
+	// Users
+	"GET /api/v1/users/get-all": Platform("platform.user.manage"),
+	"GET /api/v1/users/:id":     Platform("platform.user.manage"),
+	"PUT /api/v1/users/:id":     Platform("platform.user.manage"),
+	"DELETE /api/v1/users/:id":  Platform("platform.user.manage"),
+	"GET /api/v1/users/":        User(),
+	"PUT /api/v1/users/":        User(),
+
+	// Subscriptions
+	"GET /api/v1/subscriptions/":    Platform("platform.subscription.view"),
+	"GET /api/v1/subscriptions/:id": Platform("platform.subscription.view"),
+
+	// Restaurants
+	"GET /api/v1/restaurants/get-all":             Public(),
+	"GET /api/v1/restaurants/":                    User(),
+	"POST /api/v1/restaurants/":                   User(),
+	"GET /api/v1/restaurants/:id":                 StaffOf("restaurant.view", paramRestaurant("id")),
+	"PUT /api/v1/restaurants/:id":                 StaffOf("restaurant.update", paramRestaurant("id")),
+	"DELETE /api/v1/restaurants/:id":              StaffOf("restaurant.delete", paramRestaurant("id")),
+	"POST /api/v1/restaurants/:id/images/:kind":   StaffOf("restaurant.update", paramRestaurant("id")),
+	"DELETE /api/v1/restaurants/:id/images/:kind": StaffOf("restaurant.update", paramRestaurant("id")),
+	"POST /api/v1/restaurants/bank-account":       StaffOf("restaurant.bank_account.update", bodyRestaurant),
+
+	// Brands, the handlers check the user is the admin of the brand
+	"POST /api/v1/brands/":                                   User(),
+	"GET /api/v1/brands/":                                    User(),
+	"GET /api/v1/brands/:brand_id":                           User(),
+	"PUT /api/v1/brands/:brand_id":                           User(),
+	"DELETE /api/v1/brands/:brand_id":                        User(),
+	"POST /api/v1/brands/:brand_id/outlets":                  User(),
+	"DELETE /api/v1/brands/:brand_id/outlets/:restaurant_id": User(),
+	"PUT /api/v1/brands/:brand_id/menus/:menu_id":            User(),
+	"DELETE /api/v1/brands/:brand_id/menus/:menu_id":         User(),
+	"GET /api/v1/brands/:brand_id/reports/sales":             User(),
+
+	// Invitations
+	"POST /api/v1/invitations/accept": User(),
+
+	// Plans
+	"GET /api/v1/plans/":               Public(),
+	"GET /api/v1/plans/:id":            Public(),
+	"POST /api/v1/plans/":              Platform("platform.plan.manage"),
+	"GET /api/v1/plans/all":            Platform("platform.plan.manage"),
+	"PUT /api/v1/plans/:id":            Platform("platform.plan.manage"),
+	"DELETE /api/v1/plans/:id":         Platform("platform.plan.manage"),
+	"PUT /api/v1/plans/add-feature":    Platform("platform.plan.manage"),
+	"PUT /api/v1/plans/remove-feature": Platform("platform.plan.manage"),
+	"POST /api/v1/plans/feature":       Platform("platform.plan.manage"),
+	"GET /api/v1/plans/feature":        Platform("platform.plan.manage"),
+	"PUT /api/v1/plans/feature/:id":    Platform("platform.plan.manage"),
+	"DELETE /api/v1/plans/feature/:id": Platform("platform.plan.manage"),
+
+	// Orders
+	"POST /api/v1/orders/restaurant/otp/send":   Public(),
+	"POST /api/v1/orders/restaurant/otp/verify": Public(),
+	"POST /api/v1/orders/restaurant/":           Public(),
+	"GET /api/v1/orders/restaurant/":            StaffOf("order.view", queryRestaurant),
+	"GET /api/v1/orders/restaurant/:id":         StaffOf("order.view", orderRestaurant),
+	"PUT /api/v1/orders/restaurant/:id/status":  StaffOf("order.status.update", orderRestaurant),
+	"POST /api/v1/orders/restaurant/:id/cancel": StaffOf("order.cancel", orderRestaurant),
+	"GET /api/v1/orders/dine/all":               Platform("platform.order.view"),
+	"GET /api/v1/orders/dine/:id":               Platform("platform.order.view"),
+	"GET /api/v1/orders/dine/":                  User(),
+
+	// Menus
+	"GET /api/v1/:restaurant_id/menus/tree":                        Guest(),
+	"GET /api/v1/:restaurant_id/menus/":                            Guest(),
+	"GET /api/v1/:restaurant_id/menus/:menu_id":                    Guest(),
+	"POST /api/v1/:restaurant_id/menus/":                           Staff("menu.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id":                    Staff("menu.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id":                 Staff("menu.delete"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/schedules":          Staff("menu.schedule.update"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/import":            Staff("menu.import"),
+	"GET /api/v1/:restaurant_id/menus/:menu_id/export":             Staff("menu.export"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/clone":             Staff("menu.create"),
+	"POST /api/v1/:restaurant_id/menus/from-template/:template_id": Staff("menu.create"),
+
+	// Combos
+	"GET /api/v1/:restaurant_id/menus/:menu_id/combos/":             Public(),
+	"GET /api/v1/:restaurant_id/menus/:menu_id/combos/:combo_id":    Public(),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/combos/":            Staff("menu.combo.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/combos/:combo_id":    Staff("menu.combo.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/combos/:combo_id": Staff("menu.combo.delete"),
+
+	// Categories
+	"GET /api/v1/:restaurant_id/menus/:menu_id/categories/":                       Public(),
+	"GET /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id":           Public(),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/":                      Staff("menu.category.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/order":                  Staff("menu.category.reorder"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id":           Staff("menu.category.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id":        Staff("menu.category.delete"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/schedules": Staff("menu.schedule.update"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/image":    Staff("menu.category.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/image":  Staff("menu.category.update"),
+
+	// Items
+	"GET /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/":                       Public(),
+	"GET /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id":               Public(),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/":                      Staff("menu.item.create"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/bulk":                  Staff("menu.item.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/order":                  Staff("menu.item.reorder"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id":               Staff("menu.item.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id":            Staff("menu.item.delete"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/options/order": Staff("menu.item.update"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/image":        Staff("menu.item.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/image":      Staff("menu.item.update"),
+
+	// Modifier groups and modifiers
+	"GET /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups":                                     Public(),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups":                                    Staff("menu.modifier.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups/:group_id":                           Staff("menu.modifier.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups/:group_id":                        Staff("menu.modifier.delete"),
+	"POST /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups/:group_id/modifiers":                Staff("menu.modifier.create"),
+	"PUT /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups/:group_id/modifiers/:modifier_id":    Staff("menu.modifier.update"),
+	"DELETE /api/v1/:restaurant_id/menus/:menu_id/categories/:category_id/items/:item_id/modifier-groups/:group_id/modifiers/:modifier_id": Staff("menu.modifier.delete"),
+
+	// Menu templates
+	"GET /api/v1/menu-templates/":                User(),
+	"GET /api/v1/menu-templates/:template_id":    User(),
+	"POST /api/v1/menu-templates/":               Platform("platform.template.manage"),
+	"PUT /api/v1/menu-templates/:template_id":    Platform("platform.template.manage"),
+	"DELETE /api/v1/menu-templates/:template_id": Platform("platform.template.manage"),
+
+	// Menu overrides
+	"GET /api/v1/:restaurant_id/menu-overrides/":                Staff("menu.view"),
+	"PUT /api/v1/:restaurant_id/menu-overrides/":                Staff("menu.override.update"),
+	"DELETE /api/v1/:restaurant_id/menu-overrides/:override_id": Staff("menu.override.delete"),
+
+	// Menu versions
+	"GET /api/v1/:restaurant_id/menu-versions/":                        Staff("menu.view"),
+	"GET /api/v1/:restaurant_id/menu-versions/diff":                    Staff("menu.view"),
+	"GET /api/v1/:restaurant_id/menu-versions/:version_id":             Staff("menu.view"),
+	"POST /api/v1/:restaurant_id/menu-versions/":                       Staff("menu.version.create"),
+	"POST /api/v1/:restaurant_id/menu-versions/:version_id/publish":    Staff("menu.publish"),
+	"DELETE /api/v1/:restaurant_id/menu-versions/:version_id/schedule": Staff("menu.publish"),
+	"POST /api/v1/:restaurant_id/menu-versions/:version_id/rollback":   Staff("menu.publish"),
+
+	// Kitchen
+	"GET /api/v1/:restaurant_id/kitchen/stations/":                   Staff("kitchen.view"),
+	"POST /api/v1/:restaurant_id/kitchen/stations/":                  Staff("kitchen.station.create"),
+	"PUT /api/v1/:restaurant_id/kitchen/stations/:station_id":        Staff("kitchen.station.update"),
+	"DELETE /api/v1/:restaurant_id/kitchen/stations/:station_id":     Staff("kitchen.station.delete"),
+	"PUT /api/v1/:restaurant_id/kitchen/stations/:station_id/assign": Staff("kitchen.station.update"),
+	"GET /api/v1/:restaurant_id/kitchen/tickets/":                    Staff("kitchen.view"),
+	"PUT /api/v1/:restaurant_id/kitchen/tickets/:ticket_id/status":   Staff("kitchen.ticket.update"),
+
+	// Inventory
+	"GET /api/v1/:restaurant_id/inventory/stock/":                               Staff("inventory.view"),
+	"PUT /api/v1/:restaurant_id/inventory/stock/":                               Staff("inventory.stock.update"),
+	"POST /api/v1/:restaurant_id/inventory/stock/:stock_id/adjust":              Staff("inventory.stock.adjust"),
+	"DELETE /api/v1/:restaurant_id/inventory/stock/:stock_id":                   Staff("inventory.stock.delete"),
+	"GET /api/v1/:restaurant_id/inventory/alerts/":                              Staff("inventory.view"),
+	"POST /api/v1/:restaurant_id/inventory/alerts/:alert_id/acknowledge":        Staff("inventory.alert.acknowledge"),
+	"GET /api/v1/:restaurant_id/inventory/86/":                                  Staff("inventory.view"),
+	"POST /api/v1/:restaurant_id/inventory/86/":                                 Staff("inventory.86.create"),
+	"DELETE /api/v1/:restaurant_id/inventory/86/:item_id":                       Staff("inventory.86.delete"),
+	"GET /api/v1/:restaurant_id/inventory/ingredients/":                         Staff("inventory.view"),
+	"POST /api/v1/:restaurant_id/inventory/ingredients/":                        Staff("inventory.ingredient.create"),
+	"PUT /api/v1/:restaurant_id/inventory/ingredients/:ingredient_id":           Staff("inventory.ingredient.update"),
+	"DELETE /api/v1/:restaurant_id/inventory/ingredients/:ingredient_id":        Staff("inventory.ingredient.delete"),
+	"POST /api/v1/:restaurant_id/inventory/ingredients/:ingredient_id/count":    Staff("inventory.ingredient.count"),
+	"POST /api/v1/:restaurant_id/inventory/ingredients/:ingredient_id/wastage":  Staff("inventory.ingredient.waste"),
+	"GET /api/v1/:restaurant_id/inventory/ingredients/:ingredient_id/movements": Staff("inventory.view"),
+	"GET /api/v1/:restaurant_id/inventory/recipes/items/:item_id":               Staff("inventory.view"),
+	"PUT /api/v1/:restaurant_id/inventory/recipes/items/:item_id":               Staff("inventory.recipe.update"),
+	"GET /api/v1/:restaurant_id/inventory/suppliers/":                           Staff("inventory.view"),
+	"POST /api/v1/:restaurant_id/inventory/suppliers/":                          Staff("inventory.supplier.create"),
+	"PUT /api/v1/:restaurant_id/inventory/suppliers/:supplier_id":               Staff("inventory.supplier.update"),
+	"DELETE /api/v1/:restaurant_id/inventory/suppliers/:supplier_id":            Staff("inventory.supplier.delete"),
+	"GET /api/v1/:restaurant_id/inventory/receipts/":                            Staff("inventory.view"),
+	"GET /api/v1/:restaurant_id/inventory/receipts/:receipt_id":                 Staff("inventory.view"),
+	"POST /api/v1/:restaurant_id/inventory/receipts/":                           Staff("inventory.receipt.create"),
+	"GET /api/v1/:restaurant_id/inventory/reports/consumption":                  Staff("report.view"),
+	"GET /api/v1/:restaurant_id/inventory/reports/margins":                      Staff("report.view"),
+
+	// Tables, scanned by guests from the QR code
+	"GET /api/v1/:restaurant_id/tables/scan":                 Public(),
+	"GET /api/v1/:restaurant_id/tables/":                     Staff("table.view"),
+	"GET /api/v1/:restaurant_id/tables/:table_id":            Staff("table.view"),
+	"GET /api/v1/:restaurant_id/tables/:table_id/qr":         Staff("table.view"),
+	"POST /api/v1/:restaurant_id/tables/":                    Staff("table.create"),
+	"PUT /api/v1/:restaurant_id/tables/:table_id":            Staff("table.update"),
+	"PUT /api/v1/:restaurant_id/tables/:table_id/status":     Staff("table.status.update"),
+	"DELETE /api/v1/:restaurant_id/tables/:table_id":         Staff("table.delete"),
+	"POST /api/v1/:restaurant_id/tables/:table_id/qr/rotate": Staff("table.qr.rotate"),
+
+	// Table sessions, guests pay their share online and Razorpay calls back
+	"POST /api/v1/:restaurant_id/sessions/:session_id/payments/:payment_id/online": Public(),
+	"GET /api/v1/:restaurant_id/sessions/payments/callback":                        Public(),
+	"GET /api/v1/:restaurant_id/sessions/":                                         Staff("session.view"),
+	"GET /api/v1/:restaurant_id/sessions/:session_id":                              Staff("session.view"),
+	"POST /api/v1/:restaurant_id/sessions/":                                        Staff("session.open"),
+	"POST /api/v1/:restaurant_id/sessions/:session_id/close":                       Staff("session.close"),
+	"POST /api/v1/:restaurant_id/sessions/:session_id/split":                       Staff("session.split"),
+	"POST /api/v1/:restaurant_id/sessions/:session_id/payments/:payment_id/onsite": Staff("payment.record"),
+
+	// Order rules
+	"GET /api/v1/:restaurant_id/order-rules/": Staff("restaurant.view"),
+	"PUT /api/v1/:restaurant_id/order-rules/": Staff("restaurant.order_rules.update"),
+
+	// Opening hours, read by guests
+	"GET /api/v1/:restaurant_id/schedule/":                        Public(),
+	"PUT /api/v1/:restaurant_id/schedule/":                        Staff("restaurant.schedule.update"),
+	"PUT /api/v1/:restaurant_id/schedule/pause":                   Staff("restaurant.schedule.pause"),
+	"POST /api/v1/:restaurant_id/schedule/closures":               Staff("restaurant.schedule.update"),
+	"DELETE /api/v1/:restaurant_id/schedule/closures/:closure_id": Staff("restaurant.schedule.update"),
+
+	// Translations
+	"GET /api/v1/:restaurant_id/translations/":                Staff("menu.view"),
+	"GET /api/v1/:restaurant_id/translations/:locale/export":  Staff("menu.view"),
+	"PUT /api/v1/:restaurant_id/translations/:locale":         Staff("menu.translation.update"),
+	"POST /api/v1/:restaurant_id/translations/:locale/import": Staff("menu.translation.update"),
+	"DELETE /api/v1/:restaurant_id/translations/:locale":      Staff("menu.translation.delete"),
+
+	// Pricing rules
+	"GET /api/v1/:restaurant_id/pricing-rules/":            Staff("menu.view"),
+	"POST /api/v1/:restaurant_id/pricing-rules/":           Staff("menu.pricing.create"),
+	"PUT /api/v1/:restaurant_id/pricing-rules/:rule_id":    Staff("menu.pricing.update"),
+	"DELETE /api/v1/:restaurant_id/pricing-rules/:rule_id": Staff("menu.pricing.delete"),
+
+	// Staff, the handlers also check the caller manages the role changed
+	"GET /api/v1/:restaurant_id/staff/":                              Staff("staff.view"),
+	"GET /api/v1/:restaurant_id/staff/invitations":                   Staff("staff.view"),
+	"PUT /api/v1/:restaurant_id/staff/:member_id":                    Staff("staff.update"),
+	"DELETE /api/v1/:restaurant_id/staff/:member_id":                 Staff("staff.remove"),
+	"POST /api/v1/:restaurant_id/staff/invitations":                  Staff("staff.invite"),
+	"DELETE /api/v1/:restaurant_id/staff/invitations/:invitation_id": Staff("staff.invite.revoke"),
+
+	// Promo codes
+	"POST /api/v1/promo-code/dine": Platform("platform.promo_code.create"),
+
+	// Workflows of the signed in user
+	"POST /api/v1/workflow/plan/order-payment":       User(),
+	"GET /api/v1/workflow/plan/payment-subscription": User(),
+}
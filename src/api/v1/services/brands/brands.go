@@ -0,0 +1,546 @@
+package services_brand
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	services_inventory "dine-server/src/api/v1/services/inventory"
+	"dine-server/src/config/cache"
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	models_subscription "dine-server/src/models/subscriptions"
+	utils "dine-server/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateBrand creates a brand owned by the current user
+// @Summary Create a brand
+// @Description Create a brand to group the outlets of a chain
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand body models_restaurant.AddBrandData true "Brand data"
+// @Router /api/v1/brands [post]
+func CreateBrand(c *gin.Context) {
+	var input models_restaurant.AddBrandData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	adminID, err := uuid.FromString(fmt.Sprint(userID))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	brand := models_restaurant.Brand{
+		Name:         input.Name,
+		Description:  input.Description,
+		LogoImageUrl: input.LogoImageUrl,
+		AdminID:      adminID,
+	}
+	if err := postgres.DB.Create(&brand).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create brand"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Brand Created Successfully", "brand": brand})
+}
+
+// GetBrands lists the brands of the current user, platform admins get every brand
+// @Summary Get brands
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Router /api/v1/brands [get]
+func GetBrands(c *gin.Context) {
+	query := postgres.DB.Preload("Outlets", func(db *gorm.DB) *gorm.DB { return db.Order("restaurants.name") }).Order("name")
+	if role, _ := c.Get("role"); role != "admin" {
+		userID, _ := c.Get("userID")
+		query = query.Where("admin_id = ?", userID)
+	}
+
+	var brands []models_restaurant.Brand
+	if err := query.Find(&brands).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brands"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brands Found Successfully", "brands": brands})
+}
+
+// GetBrandByID returns a brand with its outlets, subscription and shared menus
+// @Summary Get a brand
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Router /api/v1/brands/{brand_id} [get]
+func GetBrandByID(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	if err := postgres.DB.
+		Preload("Outlets", func(db *gorm.DB) *gorm.DB { return db.Order("restaurants.name") }).
+		Preload("Subscription.Plan").
+		First(&brand, "id = ?", brand.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand"})
+		return
+	}
+
+	var menus []models_menu.Menu
+	if err := postgres.DB.Where("brand_id = ?", brand.ID).Order("name").Find(&menus).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shared menus"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brand Found Successfully", "brand": brand, "shared_menus": menus})
+}
+
+// UpdateBrand updates the details of a brand
+// @Summary Update a brand
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param brand body models_restaurant.UpdateBrandData true "Brand data"
+// @Router /api/v1/brands/{brand_id} [put]
+func UpdateBrand(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	var input models_restaurant.UpdateBrandData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Name != "" {
+		brand.Name = input.Name
+	}
+	if input.Description != nil {
+		brand.Description = *input.Description
+	}
+	if input.LogoImageUrl != nil {
+		brand.LogoImageUrl = *input.LogoImageUrl
+	}
+
+	if err := postgres.DB.Select("name", "description", "logo_image_url").Save(&brand).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brand Updated Successfully", "brand": brand})
+}
+
+// DeleteBrand deletes a brand. Its outlets become independent restaurants and its menus stop being shared.
+// @Summary Delete a brand
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Router /api/v1/brands/{brand_id} [delete]
+func DeleteBrand(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	outlets, err := brandOutletIDs(postgres.DB, brand.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand outlets"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := tx.Model(&models_menu.Menu{}).Where("brand_id = ?", brand.ID).Update("brand_id", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unshare brand menus"})
+		return
+	}
+	if brand.SubscriptionID != nil {
+		if err := tx.Model(&models_restaurant.Restaurant{}).Where("brand_id = ? AND subscription_id = ?", brand.ID, brand.SubscriptionID).
+			Update("subscription_id", nil).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update outlets"})
+			return
+		}
+	}
+	if err := tx.Model(&models_restaurant.Restaurant{}).Where("brand_id = ?", brand.ID).Update("brand_id", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update outlets"})
+		return
+	}
+	if err := tx.Delete(&brand).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete brand"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+	invalidateMenuTrees(outlets)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brand deleted successfully"})
+}
+
+// AddBrandOutlet adds a restaurant of the brand owner to the brand
+// @Summary Add an outlet to a brand
+// @Description The restaurant starts serving the menus the brand shares. While the brand has a subscription, it covers the outlet up to the plan's max_outlets.
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param outlet body models_restaurant.AddBrandOutletData true "Outlet data"
+// @Router /api/v1/brands/{brand_id}/outlets [post]
+func AddBrandOutlet(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	var input models_restaurant.AddBrandOutletData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.First(&restaurant, "id = ?", input.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if role, _ := c.Get("role"); role != "admin" && restaurant.AdminID != brand.AdminID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only restaurants of the brand owner can be added to the brand"})
+		return
+	}
+	if restaurant.BrandID != nil {
+		if *restaurant.BrandID == brand.ID {
+			c.JSON(http.StatusConflict, gin.H{"error": "The restaurant is already an outlet of this brand"})
+		} else {
+			c.JSON(http.StatusConflict, gin.H{"error": "The restaurant is an outlet of another brand"})
+		}
+		return
+	}
+
+	subscription, err := activeSubscription(postgres.DB, brand)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand subscription"})
+		return
+	}
+	updates := map[string]interface{}{"brand_id": brand.ID}
+	if subscription != nil {
+		var outlets int64
+		if err := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("brand_id = ?", brand.ID).Count(&outlets).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count brand outlets"})
+			return
+		}
+		if int(outlets) >= subscription.Plan.MaxOutlets {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("The brand plan covers %d outlets, upgrade it to add more", subscription.Plan.MaxOutlets)})
+			return
+		}
+		updates["subscription_id"] = subscription.ID
+	}
+
+	if err := postgres.DB.Model(&restaurant).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add outlet"})
+		return
+	}
+	cache.MenuTrees.Delete(restaurant.ID.String())
+
+	c.JSON(http.StatusOK, gin.H{"message": "Outlet Added Successfully", "restaurant": utils.RestaurantResponse([]models_restaurant.Restaurant{restaurant})[0]})
+}
+
+// RemoveBrandOutlet makes an outlet an independent restaurant again
+// @Summary Remove an outlet from a brand
+// @Description The restaurant stops serving the menus the brand shares, and the menus it shared stop being shared.
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/brands/{brand_id}/outlets/{restaurant_id} [delete]
+func RemoveBrandOutlet(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.First(&restaurant, "id = ? AND brand_id = ?", c.Param("restaurant_id"), brand.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Outlet not found"})
+		return
+	}
+
+	outlets, err := brandOutletIDs(postgres.DB, brand.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand outlets"})
+		return
+	}
+
+	updates := map[string]interface{}{"brand_id": nil}
+	if brand.SubscriptionID != nil && restaurant.SubscriptionID != nil && *restaurant.SubscriptionID == *brand.SubscriptionID {
+		updates["subscription_id"] = nil
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := tx.Model(&models_menu.Menu{}).Where("restaurant_id = ? AND brand_id = ?", restaurant.ID, brand.ID).Update("brand_id", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unshare outlet menus"})
+		return
+	}
+	if err := tx.Model(&restaurant).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove outlet"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+	invalidateMenuTrees(outlets)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Outlet removed successfully"})
+}
+
+// ShareBrandMenu shares a menu of an outlet with every outlet of the brand
+// @Summary Share a menu with the brand
+// @Description Every outlet serves the menu, and can change its prices and availability with menu overrides. The menu is still edited in the outlet that owns it.
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param menu_id path string true "Menu ID"
+// @Router /api/v1/brands/{brand_id}/menus/{menu_id} [put]
+func ShareBrandMenu(c *gin.Context) {
+	setMenuBrand(c, true)
+}
+
+// UnshareBrandMenu stops sharing a menu, only the outlet owning it serves it again
+// @Summary Stop sharing a menu with the brand
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param menu_id path string true "Menu ID"
+// @Router /api/v1/brands/{brand_id}/menus/{menu_id} [delete]
+func UnshareBrandMenu(c *gin.Context) {
+	setMenuBrand(c, false)
+}
+
+// GetBrandSalesReport compares the sales of the outlets of a brand over a period
+// @Summary Brand sales report
+// @Description Completed orders, revenue and food cost of each outlet, and the best selling items across outlets.
+// @Description Dates are days in each outlet's timezone, the last 30 days by default.
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param brand_id path string true "Brand ID"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Router /api/v1/brands/{brand_id}/reports/sales [get]
+func GetBrandSalesReport(c *gin.Context) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	var outlets []models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "name", "timezone").Where("brand_id = ?", brand.ID).Order("name").Find(&outlets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand outlets"})
+		return
+	}
+
+	report := make([]models_restaurant.BrandOutletSales, 0, len(outlets))
+	items := make(map[uuid.UUID]*models_restaurant.BrandItemSales)
+	var orders int
+	var revenue, foodCost float64
+	for _, outlet := range outlets {
+		from, to, err := services_inventory.ReportPeriod(postgres.DB, outlet.ID.String(), c.Query("from"), c.Query("to"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		completed := func(db *gorm.DB) *gorm.DB {
+			return db.Where("orders.restaurant_id = ? AND orders.status = ?", outlet.ID, models_order.OrderStatusCompleted).
+				Where("COALESCE(orders.completed_at, orders.created_at) >= ? AND COALESCE(orders.completed_at, orders.created_at) < ?", from, to)
+		}
+
+		line := models_restaurant.BrandOutletSales{RestaurantID: outlet.ID, Name: outlet.Name}
+		if err := postgres.DB.Model(&models_order.Order{}).Scopes(completed).
+			Select("COUNT(*) AS orders, COALESCE(SUM(orders.sub_total), 0) AS revenue").
+			Scan(&line).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute sales"})
+			return
+		}
+
+		var itemSales []struct {
+			MenuItemID uuid.UUID
+			Name       string
+			Quantity   int
+			Revenue    float64
+			FoodCost   float64
+		}
+		if err := postgres.DB.Model(&models_order.OrderItem{}).
+			Select("order_items.menu_item_id, MAX(order_items.menu_name) AS name, SUM(order_items.quantity) AS quantity, " +
+				"SUM(order_items.subtotal) AS revenue, SUM(order_items.food_cost) AS food_cost").
+			Joins("JOIN orders ON orders.id = order_items.order_id").
+			Scopes(completed).
+			Group("order_items.menu_item_id").
+			Scan(&itemSales).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute item sales"})
+			return
+		}
+
+		for _, sales := range itemSales {
+			line.FoodCost += sales.FoodCost
+			item, ok := items[sales.MenuItemID]
+			if !ok {
+				item = &models_restaurant.BrandItemSales{MenuItemID: sales.MenuItemID, Name: sales.Name}
+				items[sales.MenuItemID] = item
+			}
+			item.Quantity += sales.Quantity
+			item.Revenue += sales.Revenue
+			item.Outlets++
+		}
+
+		line.Revenue = utils.RoundAmount(line.Revenue)
+		line.FoodCost = utils.RoundAmount(line.FoodCost)
+		if line.Orders > 0 {
+			line.AverageOrder = utils.RoundAmount(line.Revenue / float64(line.Orders))
+		}
+		orders += line.Orders
+		revenue += line.Revenue
+		foodCost += line.FoodCost
+		report = append(report, line)
+	}
+
+	// Items of shared menus have the same ID in every outlet, so they add up across the brand
+	topItems := make([]models_restaurant.BrandItemSales, 0, len(items))
+	for _, item := range items {
+		item.Revenue = utils.RoundAmount(item.Revenue)
+		topItems = append(topItems, *item)
+	}
+	sort.Slice(topItems, func(i, j int) bool { return topItems[i].Revenue > topItems[j].Revenue })
+	sort.SliceStable(report, func(i, j int) bool { return report[i].Revenue > report[j].Revenue })
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Brand Report Generated Successfully",
+		"orders":    orders,
+		"revenue":   utils.RoundAmount(revenue),
+		"food_cost": utils.RoundAmount(foodCost),
+		"outlets":   report,
+		"items":     topItems,
+	})
+}
+
+// findBrand returns the brand of the request when the user owns it, platform admins manage every brand.
+// It responds itself when the brand cannot be used.
+func findBrand(c *gin.Context) (models_restaurant.Brand, bool) {
+	var brand models_restaurant.Brand
+	if err := postgres.DB.First(&brand, "id = ?", c.Param("brand_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Brand not found"})
+		return brand, false
+	}
+
+	role, _ := c.Get("role")
+	userID, _ := c.Get("userID")
+	if role != "admin" && brand.AdminID.String() != fmt.Sprint(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this brand"})
+		return brand, false
+	}
+	return brand, true
+}
+
+// setMenuBrand shares a menu of an outlet with its brand, or stops sharing it
+func setMenuBrand(c *gin.Context, share bool) {
+	brand, ok := findBrand(c)
+	if !ok {
+		return
+	}
+
+	var menu models_menu.Menu
+	if err := postgres.DB.
+		Joins("JOIN restaurants ON restaurants.id = menus.restaurant_id").
+		Where("menus.id = ? AND restaurants.brand_id = ?", c.Param("menu_id"), brand.ID).
+		First(&menu).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found in the outlets of this brand"})
+		return
+	}
+
+	var brandID interface{}
+	if share {
+		brandID = brand.ID
+	}
+	if err := postgres.DB.Model(&menu).Update("brand_id", brandID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update menu"})
+		return
+	}
+
+	outlets, err := brandOutletIDs(postgres.DB, brand.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch brand outlets"})
+		return
+	}
+	invalidateMenuTrees(outlets)
+
+	if share {
+		c.JSON(http.StatusOK, gin.H{"message": "Menu Shared Successfully", "menu": menu})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "Menu Unshared Successfully", "menu": menu})
+	}
+}
+
+// activeSubscription returns the running subscription of a brand with its plan, nil when there is none
+func activeSubscription(db *gorm.DB, brand models_restaurant.Brand) (*models_subscription.Subscription, error) {
+	if brand.SubscriptionID == nil {
+		return nil, nil
+	}
+
+	var subscription models_subscription.Subscription
+	err := db.Preload("Plan").
+		Where("id = ? AND canceled = ? AND end_date >= ?", brand.SubscriptionID, false, time.Now().Format("2006-01-02")).
+		First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func brandOutletIDs(db *gorm.DB, brandID uuid.UUID) ([]string, error) {
+	var outlets []string
+	err := db.Model(&models_restaurant.Restaurant{}).Where("brand_id = ?", brandID).Pluck("id", &outlets).Error
+	return outlets, err
+}
+
+// invalidateMenuTrees drops the cached menu trees of outlets whose shared menus changed
+func invalidateMenuTrees(restaurantIDs []string) {
+	for _, restaurantID := range restaurantIDs {
+		cache.MenuTrees.Delete(restaurantID)
+	}
+}
@@ -0,0 +1,362 @@
+package services_images
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/storage"
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// UploadRestaurantImage uploads the logo or banner of a restaurant
+// @Summary Upload a restaurant logo or banner
+// @Description Upload a JPEG, PNG or WebP image of at most 5 MB as the multipart field "image". Large images are scaled down and a thumbnail is made.
+// @Tags Images
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param kind path string true "logo or banner"
+// @Param image formData file true "Image file"
+// @Router /api/v1/restaurants/{id}/images/{kind} [post]
+func UploadRestaurantImage(c *gin.Context) {
+	restaurantID := c.Param("id")
+	owner, ok := restaurantImageOwner(c.Param("kind"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image kind must be logo or banner"})
+		return
+	}
+	uploadImage(c, restaurantID, owner, restaurantID)
+}
+
+// DeleteRestaurantImage removes the logo or banner of a restaurant
+// @Summary Remove a restaurant logo or banner
+// @Tags Images
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param kind path string true "logo or banner"
+// @Router /api/v1/restaurants/{id}/images/{kind} [delete]
+func DeleteRestaurantImage(c *gin.Context) {
+	restaurantID := c.Param("id")
+	owner, ok := restaurantImageOwner(c.Param("kind"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image kind must be logo or banner"})
+		return
+	}
+	deleteImage(c, restaurantID, owner, restaurantID)
+}
+
+// UploadCategoryImage uploads the image of a menu category
+// @Summary Upload a category image
+// @Description Upload a JPEG, PNG or WebP image of at most 5 MB as the multipart field "image". Large images are scaled down and a thumbnail is made.
+// @Tags Images
+// @Accept multipart/form-data
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param image formData file true "Image file"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/image [post]
+func UploadCategoryImage(c *gin.Context) {
+	uploadImage(c, c.Param("restaurant_id"), models_restaurant.ImageCategory, c.Param("category_id"))
+}
+
+// DeleteCategoryImage removes the image of a menu category
+// @Summary Remove a category image
+// @Tags Images
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/image [delete]
+func DeleteCategoryImage(c *gin.Context) {
+	deleteImage(c, c.Param("restaurant_id"), models_restaurant.ImageCategory, c.Param("category_id"))
+}
+
+// UploadMenuItemImage uploads the image of a menu item
+// @Summary Upload a menu item image
+// @Description Upload a JPEG, PNG or WebP image of at most 5 MB as the multipart field "image". Large images are scaled down and a thumbnail is made.
+// @Tags Images
+// @Accept multipart/form-data
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param image formData file true "Image file"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/image [post]
+func UploadMenuItemImage(c *gin.Context) {
+	uploadImage(c, c.Param("restaurant_id"), models_restaurant.ImageItem, c.Param("item_id"))
+}
+
+// DeleteMenuItemImage removes the image of a menu item
+// @Summary Remove a menu item image
+// @Tags Images
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/image [delete]
+func DeleteMenuItemImage(c *gin.Context) {
+	deleteImage(c, c.Param("restaurant_id"), models_restaurant.ImageItem, c.Param("item_id"))
+}
+
+// CleanupImages removes the stored images of a restaurant nothing shows anymore.
+// An image is kept while a restaurant, category or item points at it, including menus cloned to other restaurants,
+// or any menu version or menu template contains it, so replacing or deleting an image never breaks a published menu.
+func CleanupImages(db *gorm.DB, restaurantID string) error {
+	var images []models_restaurant.Image
+	if err := db.Where("restaurant_id = ?", restaurantID).Find(&images).Error; err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	urls := make([]string, 0, len(images))
+	for _, image := range images {
+		urls = append(urls, image.URL)
+	}
+
+	inUse := map[string]bool{}
+	var logos []string
+	if err := db.Model(&models_restaurant.Restaurant{}).Where("logo_image_url IN ?", urls).Pluck("logo_image_url", &logos).Error; err != nil {
+		return err
+	}
+	var banners []string
+	if err := db.Model(&models_restaurant.Restaurant{}).Where("banner_image_url IN ?", urls).Pluck("banner_image_url", &banners).Error; err != nil {
+		return err
+	}
+	var categories []string
+	if err := db.Model(&models_menu.MenuCategory{}).Where("image_url IN ?", urls).Pluck("image_url", &categories).Error; err != nil {
+		return err
+	}
+	var items []string
+	if err := db.Model(&models_menu.MenuItem{}).Where("image_url IN ?", urls).Pluck("image_url", &items).Error; err != nil {
+		return err
+	}
+	for _, url := range append(append(append(logos, banners...), categories...), items...) {
+		inUse[url] = true
+	}
+
+	for _, image := range images {
+		if inUse[image.URL] {
+			continue
+		}
+		var inSnapshot bool
+		if err := db.Raw("SELECT EXISTS (SELECT 1 FROM menu_versions WHERE strpos(menus::text, ?) > 0) OR EXISTS (SELECT 1 FROM menu_templates WHERE strpos(menu::text, ?) > 0)",
+			image.URL, image.URL).Scan(&inSnapshot).Error; err != nil {
+			return err
+		}
+		if inSnapshot {
+			continue
+		}
+
+		if err := storage.Client.Delete(image.Key); err != nil {
+			return err
+		}
+		if err := storage.Client.Delete(image.ThumbnailKey); err != nil {
+			return err
+		}
+		if err := db.Delete(&image).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CleanupImagesLater runs CleanupImages after a change and only logs failures, the next cleanup retries them
+func CleanupImagesLater(restaurantID string) {
+	if err := CleanupImages(postgres.DB, restaurantID); err != nil {
+		log.Printf("Failed to clean up images of restaurant %s: %v", restaurantID, err)
+	}
+}
+
+func uploadImage(c *gin.Context, restaurantID string, owner models_restaurant.ImageOwner, ownerID string) {
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+	ownerUUID, err := uuid.FromString(ownerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + string(owner) + " ID format"})
+		return
+	}
+	if err := findOwner(postgres.DB, restaurantID, owner, ownerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ownerName(owner) + " not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		}
+		return
+	}
+
+	// Leave room for the multipart headers around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, utils.MaxImageSize+1<<20)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Image must be at most %d MB", utils.MaxImageSize>>20)})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload the image as the multipart field \"image\""})
+		}
+		return
+	}
+	if header.Size > utils.MaxImageSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Image must be at most %d MB", utils.MaxImageSize>>20)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read image"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, utils.MaxImageSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read image"})
+		return
+	}
+
+	processed, err := utils.ProcessImage(data)
+	if errors.Is(err, utils.ErrUnsupportedImage) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imageID, err := uuid.NewV4()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		return
+	}
+	path := fmt.Sprintf("restaurants/%s/%s/%s", restaurantID, owner, imageID)
+	image := models_restaurant.Image{
+		ID:           imageID,
+		RestaurantID: restaurantUUID,
+		OwnerType:    owner,
+		OwnerID:      ownerUUID,
+		Key:          path + utils.ImageExtensions[processed.ContentType],
+		ThumbnailKey: path + "_thumb" + utils.ImageExtensions[processed.ThumbnailContentType],
+		ContentType:  processed.ContentType,
+		Size:         len(processed.Data),
+		Width:        processed.Width,
+		Height:       processed.Height,
+	}
+	image.URL = storage.Client.URL(image.Key)
+	image.ThumbnailURL = storage.Client.URL(image.ThumbnailKey)
+
+	if err := storage.Client.Put(image.Key, processed.Data, processed.ContentType); err != nil {
+		log.Printf("Failed to store image %s: %v", image.Key, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to store image"})
+		return
+	}
+	if err := storage.Client.Put(image.ThumbnailKey, processed.Thumbnail, processed.ThumbnailContentType); err != nil {
+		log.Printf("Failed to store image %s: %v", image.ThumbnailKey, err)
+		storage.Client.Delete(image.Key)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to store image"})
+		return
+	}
+
+	if err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&image).Error; err != nil {
+			return err
+		}
+		return setOwnerImage(tx, restaurantID, owner, ownerID, &image.URL, &image.ThumbnailURL)
+	}); err != nil {
+		storage.Client.Delete(image.Key)
+		storage.Client.Delete(image.ThumbnailKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		return
+	}
+
+	// The replaced image is removed unless a menu version still shows it
+	CleanupImagesLater(restaurantID)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Image Uploaded Successfully", "image": image})
+}
+
+func deleteImage(c *gin.Context, restaurantID string, owner models_restaurant.ImageOwner, ownerID string) {
+	if err := findOwner(postgres.DB, restaurantID, owner, ownerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": ownerName(owner) + " not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image"})
+		}
+		return
+	}
+
+	if err := setOwnerImage(postgres.DB, restaurantID, owner, ownerID, nil, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image"})
+		return
+	}
+	CleanupImagesLater(restaurantID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
+
+func restaurantImageOwner(kind string) (models_restaurant.ImageOwner, bool) {
+	switch owner := models_restaurant.ImageOwner(strings.ToLower(kind)); owner {
+	case models_restaurant.ImageLogo, models_restaurant.ImageBanner:
+		return owner, true
+	}
+	return "", false
+}
+
+func ownerName(owner models_restaurant.ImageOwner) string {
+	switch owner {
+	case models_restaurant.ImageCategory:
+		return "Category"
+	case models_restaurant.ImageItem:
+		return "Item"
+	}
+	return "Restaurant"
+}
+
+// findOwner checks that the owner of an image belongs to the restaurant
+func findOwner(db *gorm.DB, restaurantID string, owner models_restaurant.ImageOwner, ownerID string) error {
+	switch owner {
+	case models_restaurant.ImageCategory:
+		return db.Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+			Where("menu_categories.id = ? AND menus.restaurant_id = ?", ownerID, restaurantID).
+			First(&models_menu.MenuCategory{}).Error
+	case models_restaurant.ImageItem:
+		return db.Joins("JOIN menus ON menus.id = menu_items.menu_id").
+			Where("menu_items.id = ? AND menus.restaurant_id = ?", ownerID, restaurantID).
+			First(&models_menu.MenuItem{}).Error
+	}
+	return db.First(&models_restaurant.Restaurant{}, "id = ?", restaurantID).Error
+}
+
+// setOwnerImage points the owner at an image, nil URLs remove it
+func setOwnerImage(db *gorm.DB, restaurantID string, owner models_restaurant.ImageOwner, ownerID string, url, thumbnailURL *string) error {
+	switch owner {
+	case models_restaurant.ImageLogo, models_restaurant.ImageBanner:
+		value := ""
+		if url != nil {
+			value = *url
+		}
+		return db.Model(&models_restaurant.Restaurant{}).Where("id = ?", restaurantID).
+			Update(string(owner)+"_image_url", value).Error
+	case models_restaurant.ImageCategory:
+		return db.Model(&models_menu.MenuCategory{}).Where("id = ?", ownerID).
+			Updates(map[string]interface{}{"image_url": url, "thumbnail_url": thumbnailURL}).Error
+	}
+	return db.Model(&models_menu.MenuItem{}).Where("id = ?", ownerID).
+		Updates(map[string]interface{}{"image_url": url, "thumbnail_url": thumbnailURL}).Error
+}
@@ -0,0 +1,350 @@
+package services_inventory
+
+import (
+	models_inventory "dine-server/src/models/inventory"
+	models_menu "dine-server/src/models/menu"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockState holds what cannot be ordered right now: items out of stock or 86'd, and options out of stock.
+// It is live state applied on top of the published menus, so running out does not need a new version.
+type StockState struct {
+	soldOutItems   map[uuid.UUID]bool
+	soldOutOptions map[uuid.UUID]bool
+}
+
+// OutOfStockError is returned when an order asks for more portions than are left
+type OutOfStockError struct {
+	Name string
+	Left int
+}
+
+func (e *OutOfStockError) Error() string {
+	if e.Left == 0 {
+		return fmt.Sprintf("%s is out of stock", e.Name)
+	}
+	return fmt.Sprintf("only %d left of %s", e.Left, e.Name)
+}
+
+// LoadStockState resets the stocks due for their daily reset and returns what is sold out today
+func LoadStockState(db *gorm.DB, restaurantID string) (StockState, error) {
+	state := StockState{soldOutItems: make(map[uuid.UUID]bool), soldOutOptions: make(map[uuid.UUID]bool)}
+
+	today, err := restaurantToday(db, restaurantID)
+	if err != nil {
+		return state, err
+	}
+	if err := resetDailyStock(db, restaurantID, today); err != nil {
+		return state, err
+	}
+
+	var soldOut []models_inventory.ItemStock
+	if err := db.Select("id", "menu_item_id", "item_option_id").
+		Where("restaurant_id = ? AND quantity = 0", restaurantID).
+		Find(&soldOut).Error; err != nil {
+		return state, err
+	}
+	for _, stock := range soldOut {
+		if stock.ItemOptionID != nil {
+			state.soldOutOptions[*stock.ItemOptionID] = true
+		} else {
+			state.soldOutItems[stock.MenuItemID] = true
+		}
+	}
+
+	var eightySixed []uuid.UUID
+	if err := db.Model(&models_inventory.EightySixedItem{}).
+		Where("restaurant_id = ? AND date = ?", restaurantID, today).
+		Pluck("menu_item_id", &eightySixed).Error; err != nil {
+		return state, err
+	}
+	for _, itemID := range eightySixed {
+		state.soldOutItems[itemID] = true
+	}
+
+	return state, nil
+}
+
+// ItemAvailable reports whether an item is neither out of stock nor 86'd
+func (state StockState) ItemAvailable(itemID uuid.UUID) bool {
+	return !state.soldOutItems[itemID]
+}
+
+// OptionAvailable reports whether an option tracked on its own is still in stock
+func (state StockState) OptionAvailable(optionID uuid.UUID) bool {
+	return !state.soldOutOptions[optionID]
+}
+
+// ApplyStock marks the items that ran out as unavailable and drops the options that ran out.
+// An item whose every option ran out is unavailable. The menus are copied, shared menus are left untouched.
+func ApplyStock(menus []models_menu.Menu, state StockState) []models_menu.Menu {
+	if len(state.soldOutItems) == 0 && len(state.soldOutOptions) == 0 {
+		return menus
+	}
+
+	applyItem := func(item models_menu.MenuItem) models_menu.MenuItem {
+		if !state.ItemAvailable(item.ID) {
+			item.IsAvailable = false
+			return item
+		}
+		options := []models_menu.MenuItemOption{}
+		for _, option := range item.ItemOptions {
+			if state.OptionAvailable(option.ID) {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 && len(item.ItemOptions) > 0 {
+			item.IsAvailable = false
+		}
+		item.ItemOptions = options
+		return item
+	}
+
+	applied := make([]models_menu.Menu, 0, len(menus))
+	for _, menu := range menus {
+		categories := make([]models_menu.MenuCategory, 0, len(menu.Categories))
+		for _, category := range menu.Categories {
+			items := make([]models_menu.MenuItem, 0, len(category.MenuItems))
+			for _, item := range category.MenuItems {
+				items = append(items, applyItem(item))
+			}
+			category.MenuItems = items
+			categories = append(categories, category)
+		}
+		menu.Categories = categories
+
+		combos := make([]models_menu.Combo, 0, len(menu.Combos))
+		for _, combo := range menu.Combos {
+			slots := make([]models_menu.ComboSlot, 0, len(combo.Slots))
+			for _, slot := range combo.Slots {
+				slotItems := make([]models_menu.ComboSlotItem, 0, len(slot.Items))
+				for _, slotItem := range slot.Items {
+					if slotItem.MenuItem != nil {
+						item := applyItem(*slotItem.MenuItem)
+						if slotItem.ItemOptionID != nil && !state.OptionAvailable(*slotItem.ItemOptionID) {
+							item.IsAvailable = false
+						}
+						slotItem.MenuItem = &item
+					}
+					slotItems = append(slotItems, slotItem)
+				}
+				slot.Items = slotItems
+				slots = append(slots, slot)
+			}
+			combo.Slots = slots
+			combos = append(combos, combo)
+		}
+		menu.Combos = combos
+
+		applied = append(applied, menu)
+	}
+	return applied
+}
+
+// ReserveStock takes the ordered portions from the tracked stocks, failing when one runs short.
+// Stocks are locked in a fixed order so concurrent orders cannot deadlock or oversell.
+func ReserveStock(tx *gorm.DB, restaurantID, orderID uuid.UUID, orderItems []models_order.OrderItem) error {
+	itemIDs := make([]uuid.UUID, 0, len(orderItems))
+	for _, item := range orderItems {
+		itemIDs = append(itemIDs, item.MenuItemID)
+	}
+
+	var stocks []models_inventory.ItemStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("restaurant_id = ? AND menu_item_id IN ?", restaurantID, itemIDs).
+		Order("id").
+		Find(&stocks).Error; err != nil {
+		return fmt.Errorf("failed to fetch stock")
+	}
+	if len(stocks) == 0 {
+		return nil
+	}
+
+	byItem := make(map[uuid.UUID]*models_inventory.ItemStock)
+	byOption := make(map[uuid.UUID]*models_inventory.ItemStock)
+	for i := range stocks {
+		if stocks[i].ItemOptionID != nil {
+			byOption[*stocks[i].ItemOptionID] = &stocks[i]
+		} else {
+			byItem[stocks[i].MenuItemID] = &stocks[i]
+		}
+	}
+
+	needed := make(map[uuid.UUID]int)
+	names := make(map[uuid.UUID]string)
+	for _, item := range orderItems {
+		stock, ok := byOption[item.ItemOptionID]
+		name := item.MenuName + " (" + item.ItemOptionName + ")"
+		if !ok {
+			if stock, ok = byItem[item.MenuItemID]; !ok {
+				continue
+			}
+			name = item.MenuName
+		}
+		needed[stock.ID] += item.Quantity
+		names[stock.ID] = name
+	}
+
+	for i := range stocks {
+		stock := &stocks[i]
+		quantity, ok := needed[stock.ID]
+		if !ok {
+			continue
+		}
+		if stock.Quantity < quantity {
+			return &OutOfStockError{Name: names[stock.ID], Left: stock.Quantity}
+		}
+		if err := moveStock(tx, stock, -quantity, models_inventory.MovementOrder, &orderID, names[stock.ID], "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestockOrder gives the portions of a cancelled order back to their stocks.
+// Portions taken before the last daily reset are not given back, the day's stock was already replaced.
+// Restocking twice has no effect.
+func RestockOrder(tx *gorm.DB, orderID uuid.UUID) error {
+	var restocked int64
+	if err := tx.Model(&models_inventory.StockMovement{}).
+		Where("order_id = ? AND reason = ?", orderID, models_inventory.MovementCancel).
+		Count(&restocked).Error; err != nil {
+		return fmt.Errorf("failed to fetch stock movements")
+	}
+	if restocked > 0 {
+		return nil
+	}
+
+	var movements []models_inventory.StockMovement
+	if err := tx.Where("order_id = ? AND reason = ?", orderID, models_inventory.MovementOrder).
+		Order("stock_id").
+		Find(&movements).Error; err != nil {
+		return fmt.Errorf("failed to fetch stock movements")
+	}
+
+	for _, movement := range movements {
+		var stock models_inventory.ItemStock
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stock, "id = ?", movement.StockID).Error; err != nil {
+			continue // No longer tracked
+		}
+		if stock.ResetAt != nil && movement.CreatedAt.Before(*stock.ResetAt) {
+			continue
+		}
+		if err := moveStock(tx, &stock, -movement.Change, models_inventory.MovementCancel, &orderID, "", "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveStock changes the quantity of a locked stock, records the movement
+// and raises an alert when the stock drops to its threshold or runs out
+func moveStock(tx *gorm.DB, stock *models_inventory.ItemStock, change int, reason models_inventory.MovementReason, orderID *uuid.UUID, name, note string, userID *uuid.UUID) error {
+	before := stock.Quantity
+	stock.Quantity += change
+	if err := tx.Model(&models_inventory.ItemStock{}).Where("id = ?", stock.ID).Update("quantity", stock.Quantity).Error; err != nil {
+		return fmt.Errorf("failed to update stock")
+	}
+
+	movement := models_inventory.StockMovement{
+		ID:        uuid.Must(uuid.NewV4()),
+		StockID:   stock.ID,
+		OrderID:   orderID,
+		Reason:    reason,
+		Change:    change,
+		Quantity:  stock.Quantity,
+		Note:      note,
+		CreatedBy: userID,
+	}
+	if err := tx.Create(&movement).Error; err != nil {
+		return fmt.Errorf("failed to record stock movement")
+	}
+
+	crossedThreshold := before > stock.LowStockThreshold && stock.Quantity <= stock.LowStockThreshold
+	ranOut := before > 0 && stock.Quantity == 0
+	if !crossedThreshold && !ranOut {
+		return nil
+	}
+
+	if name == "" {
+		var err error
+		if name, err = stockName(tx, *stock); err != nil {
+			return err
+		}
+	}
+	alert := models_inventory.StockAlert{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: stock.RestaurantID,
+		StockID:      &stock.ID,
+		Name:         name,
+		Quantity:     float64(stock.Quantity),
+		Threshold:    float64(stock.LowStockThreshold),
+		Status:       models_inventory.AlertOpen,
+	}
+	if err := tx.Create(&alert).Error; err != nil {
+		return fmt.Errorf("failed to raise stock alert")
+	}
+	return nil
+}
+
+// stockName names a stock after its item, and its option when tracked per option
+func stockName(db *gorm.DB, stock models_inventory.ItemStock) (string, error) {
+	var item models_menu.MenuItem
+	if err := db.Select("id", "name").First(&item, "id = ?", stock.MenuItemID).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch menu item")
+	}
+	if stock.ItemOptionID == nil {
+		return item.Name, nil
+	}
+	var option models_menu.MenuItemOption
+	if err := db.Select("id", "name").First(&option, "id = ?", *stock.ItemOptionID).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch menu item option")
+	}
+	return item.Name + " (" + option.Name + ")", nil
+}
+
+// resetDailyStock brings the stocks with a par level back to it once per day
+func resetDailyStock(db *gorm.DB, restaurantID, today string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var due []models_inventory.ItemStock
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("restaurant_id = ? AND par_level > 0 AND (reset_date IS NULL OR reset_date < ?)", restaurantID, today).
+			Order("id").
+			Find(&due).Error; err != nil || len(due) == 0 {
+			return err
+		}
+
+		now := time.Now()
+		for i := range due {
+			stock := &due[i]
+			if err := tx.Model(&models_inventory.ItemStock{}).Where("id = ?", stock.ID).
+				Updates(map[string]interface{}{"reset_date": today, "reset_at": now}).Error; err != nil {
+				return err
+			}
+			stock.ResetAt = &now
+			if change := stock.ParLevel - stock.Quantity; change != 0 {
+				if err := moveStock(tx, stock, change, models_inventory.MovementReset, nil, "", "", nil); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// restaurantToday returns the current date in the restaurant's timezone
+func restaurantToday(db *gorm.DB, restaurantID interface{}) (string, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.Select("id", "timezone").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return "", err
+	}
+	return time.Now().In(utils.RestaurantLocation(restaurant)).Format("2006-01-02"), nil
+}
@@ -0,0 +1,155 @@
+package services_inventory
+
+import (
+	models_inventory "dine-server/src/models/inventory"
+	models_order "dine-server/src/models/orders"
+	utils "dine-server/src/utils"
+	"fmt"
+	"math"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConsumeIngredients takes the ingredients of a completed order from the stock, following the recipes,
+// and stores the food cost of each order item. Consuming an order twice has no effect.
+func ConsumeIngredients(tx *gorm.DB, orderID uuid.UUID) error {
+	var consumed int64
+	if err := tx.Model(&models_inventory.IngredientMovement{}).
+		Where("order_id = ? AND reason = ?", orderID, models_inventory.IngredientConsumption).
+		Count(&consumed).Error; err != nil {
+		return fmt.Errorf("failed to check ingredient consumption")
+	}
+	if consumed > 0 {
+		return nil
+	}
+
+	var order models_order.Order
+	if err := tx.Select("id", "restaurant_id").First(&order, "id = ?", orderID).Error; err != nil {
+		return fmt.Errorf("order not found")
+	}
+
+	var orderItems []models_order.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&orderItems).Error; err != nil {
+		return fmt.Errorf("failed to fetch order items")
+	}
+	if len(orderItems) == 0 {
+		return nil
+	}
+
+	itemIDs := make([]uuid.UUID, 0, len(orderItems))
+	for _, item := range orderItems {
+		itemIDs = append(itemIDs, item.MenuItemID)
+	}
+
+	var lines []models_inventory.RecipeLine
+	if err := tx.Where("restaurant_id = ? AND menu_item_id IN ?", order.RestaurantID, itemIDs).Find(&lines).Error; err != nil {
+		return fmt.Errorf("failed to fetch recipes")
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	ingredientIDs := make([]uuid.UUID, 0, len(lines))
+	for _, line := range lines {
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	var ingredients []models_inventory.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ingredientIDs).
+		Order("id").
+		Find(&ingredients).Error; err != nil {
+		return fmt.Errorf("failed to fetch ingredients")
+	}
+	byID := make(map[uuid.UUID]*models_inventory.Ingredient, len(ingredients))
+	for i := range ingredients {
+		byID[ingredients[i].ID] = &ingredients[i]
+	}
+
+	used := make(map[uuid.UUID]float64)
+	for _, item := range orderItems {
+		foodCost := 0.0
+		for _, line := range lines {
+			if line.MenuItemID != item.MenuItemID || (line.ItemOptionID != nil && *line.ItemOptionID != item.ItemOptionID) {
+				continue
+			}
+			ingredient, ok := byID[line.IngredientID]
+			if !ok {
+				continue
+			}
+			quantity := line.Quantity * float64(item.Quantity)
+			used[ingredient.ID] += quantity
+			foodCost += quantity * ingredient.CostPerUnit
+		}
+		if foodCost == 0 {
+			continue
+		}
+		if err := tx.Model(&models_order.OrderItem{}).Where("id = ?", item.ID).Update("food_cost", utils.RoundAmount(foodCost)).Error; err != nil {
+			return fmt.Errorf("failed to update food cost")
+		}
+	}
+
+	for i := range ingredients {
+		quantity, ok := used[ingredients[i].ID]
+		if !ok {
+			continue
+		}
+		if err := moveIngredient(tx, &ingredients[i], -quantity, models_inventory.IngredientConsumption, &orderID, nil, "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveIngredient changes the quantity of a locked ingredient, records the movement at its current cost
+// and raises an alert when the quantity drops to the low stock threshold
+func moveIngredient(tx *gorm.DB, ingredient *models_inventory.Ingredient, change float64, reason models_inventory.IngredientMovementReason, orderID, receiptID *uuid.UUID, note string, userID *uuid.UUID) error {
+	change = roundQuantity(change)
+	before := ingredient.Quantity
+	ingredient.Quantity = roundQuantity(ingredient.Quantity + change)
+	if err := tx.Model(&models_inventory.Ingredient{}).Where("id = ?", ingredient.ID).
+		Updates(map[string]interface{}{"quantity": ingredient.Quantity, "cost_per_unit": ingredient.CostPerUnit}).Error; err != nil {
+		return fmt.Errorf("failed to update ingredient")
+	}
+
+	movement := models_inventory.IngredientMovement{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: ingredient.RestaurantID,
+		IngredientID: ingredient.ID,
+		Reason:       reason,
+		Change:       change,
+		Quantity:     ingredient.Quantity,
+		UnitCost:     ingredient.CostPerUnit,
+		OrderID:      orderID,
+		ReceiptID:    receiptID,
+		Note:         note,
+		CreatedBy:    userID,
+	}
+	if err := tx.Create(&movement).Error; err != nil {
+		return fmt.Errorf("failed to record ingredient movement")
+	}
+
+	if before <= ingredient.LowStockThreshold || ingredient.Quantity > ingredient.LowStockThreshold {
+		return nil
+	}
+	alert := models_inventory.StockAlert{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: ingredient.RestaurantID,
+		IngredientID: &ingredient.ID,
+		Name:         ingredient.Name,
+		Quantity:     ingredient.Quantity,
+		Threshold:    ingredient.LowStockThreshold,
+		Status:       models_inventory.AlertOpen,
+	}
+	if err := tx.Create(&alert).Error; err != nil {
+		return fmt.Errorf("failed to raise stock alert")
+	}
+	return nil
+}
+
+// roundQuantity rounds an ingredient quantity to the precision it is stored with
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
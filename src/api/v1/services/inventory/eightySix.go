@@ -0,0 +1,122 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// GetEightySixedItems lists the items 86'd today
+// @Summary List 86'd items
+// @Description List the items the kitchen ran out of today
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/inventory/86 [get]
+func GetEightySixedItems(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	today, err := restaurantToday(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	var items []models_inventory.EightySixedItem
+	if err := postgres.DB.Preload("MenuItem").
+		Where("restaurant_id = ? AND date = ?", restaurantID, today).
+		Order("created_at").
+		Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch 86'd items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "86'd Items Found Successfully", "items": items})
+}
+
+// EightySixItem marks an item as run out for the rest of the day
+// @Summary 86 an item
+// @Description Mark an item as run out, guests cannot order it until the end of the day in the restaurant's timezone
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param item body models_inventory.EightySixItemData true "Item data"
+// @Router /api/v1/{restaurant_id}/inventory/86 [post]
+func EightySixItem(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.EightySixItemData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := restaurantMenuItem(postgres.DB, restaurantID, input.MenuItemID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu item not found"})
+		return
+	}
+
+	today, err := restaurantToday(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	var existing models_inventory.EightySixedItem
+	if err := postgres.DB.Where("menu_item_id = ? AND date = ?", item.ID, today).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": item.Name + " is already 86'd today"})
+		return
+	}
+
+	eightySixed := models_inventory.EightySixedItem{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: uuid.FromStringOrNil(restaurantID),
+		MenuItemID:   item.ID,
+		Date:         today,
+		Reason:       input.Reason,
+		CreatedBy:    currentUserID(c),
+	}
+	if err := postgres.DB.Create(&eightySixed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to 86 item"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Item 86'd Successfully", "item": eightySixed})
+}
+
+// RestoreEightySixedItem makes an item 86'd today orderable again
+// @Summary Bring back a 86'd item
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param item_id path string true "Menu item ID"
+// @Router /api/v1/{restaurant_id}/inventory/86/{item_id} [delete]
+func RestoreEightySixedItem(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	today, err := restaurantToday(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	result := postgres.DB.Delete(&models_inventory.EightySixedItem{}, "restaurant_id = ? AND menu_item_id = ? AND date = ?", restaurantID, c.Param("item_id"), today)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bring back item"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item is not 86'd today"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item Brought Back Successfully"})
+}
@@ -0,0 +1,298 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// GetIngredients lists the ingredients of a restaurant
+// @Summary List ingredients
+// @Description List the ingredients with what is left and their average cost
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param low query bool false "Only ingredients at or below their low stock threshold"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients [get]
+func GetIngredients(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	query := postgres.DB.Where("restaurant_id = ?", restaurantID)
+	if c.Query("low") == "true" {
+		query = query.Where("quantity <= low_stock_threshold")
+	}
+
+	var ingredients []models_inventory.Ingredient
+	if err := query.Order("name").Find(&ingredients).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingredients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredients Found Successfully", "ingredients": ingredients})
+}
+
+// CreateIngredient adds an ingredient to a restaurant
+// @Summary Create an ingredient
+// @Description Add an ingredient with its unit, the quantity on hand and its cost per unit
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient body models_inventory.AddIngredientData true "Ingredient data"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients [post]
+func CreateIngredient(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AddIngredientData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existing models_inventory.Ingredient
+	if err := postgres.DB.Where("restaurant_id = ? AND name = ?", restaurantID, input.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "An ingredient with this name already exists"})
+		return
+	}
+
+	ingredient := models_inventory.Ingredient{
+		ID:                uuid.Must(uuid.NewV4()),
+		RestaurantID:      uuid.FromStringOrNil(restaurantID),
+		Name:              input.Name,
+		Unit:              input.Unit,
+		CostPerUnit:       input.CostPerUnit,
+		LowStockThreshold: input.LowStockThreshold,
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := tx.Create(&ingredient).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ingredient"})
+		return
+	}
+
+	// The opening quantity is recorded as a count so reports start from it
+	if input.Quantity > 0 {
+		if err := moveIngredient(tx, &ingredient, input.Quantity, models_inventory.IngredientCount, nil, nil, "Opening stock", currentUserID(c)); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Ingredient Created Successfully", "ingredient": ingredient})
+}
+
+// UpdateIngredient updates the name, cost or threshold of an ingredient
+// @Summary Update an ingredient
+// @Description Update an ingredient. The quantity is changed by counts, wastage and purchase receipts.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient_id path string true "Ingredient ID"
+// @Param ingredient body models_inventory.UpdateIngredientData true "Ingredient data"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients/{ingredient_id} [put]
+func UpdateIngredient(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.UpdateIngredientData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var ingredient models_inventory.Ingredient
+	if err := postgres.DB.First(&ingredient, "id = ? AND restaurant_id = ?", c.Param("ingredient_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != nil && *input.Name != ingredient.Name {
+		var existing models_inventory.Ingredient
+		if err := postgres.DB.Where("restaurant_id = ? AND name = ?", restaurantID, *input.Name).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "An ingredient with this name already exists"})
+			return
+		}
+		updates["name"] = *input.Name
+	}
+	if input.CostPerUnit != nil {
+		updates["cost_per_unit"] = *input.CostPerUnit
+	}
+	if input.LowStockThreshold != nil {
+		updates["low_stock_threshold"] = *input.LowStockThreshold
+	}
+
+	if len(updates) > 0 {
+		if err := postgres.DB.Model(&ingredient).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ingredient"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredient Updated Successfully", "ingredient": ingredient})
+}
+
+// DeleteIngredient removes an ingredient and the recipe lines using it
+// @Summary Delete an ingredient
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient_id path string true "Ingredient ID"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients/{ingredient_id} [delete]
+func DeleteIngredient(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var receiptLines int64
+	if err := postgres.DB.Model(&models_inventory.PurchaseReceiptLine{}).Where("ingredient_id = ?", c.Param("ingredient_id")).Count(&receiptLines).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check purchase receipts"})
+		return
+	}
+	if receiptLines > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ingredient is on purchase receipts and cannot be deleted"})
+		return
+	}
+
+	result := postgres.DB.Delete(&models_inventory.Ingredient{}, "id = ? AND restaurant_id = ?", c.Param("ingredient_id"), restaurantID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete ingredient"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredient Deleted Successfully"})
+}
+
+// CountIngredient records a physical count of an ingredient
+// @Summary Count an ingredient
+// @Description Set the quantity found on the shelf, the difference is recorded as a count movement
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient_id path string true "Ingredient ID"
+// @Param count body models_inventory.CountIngredientData true "Count data"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients/{ingredient_id}/count [post]
+func CountIngredient(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.CountIngredientData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	var ingredient models_inventory.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ingredient, "id = ? AND restaurant_id = ?", c.Param("ingredient_id"), restaurantID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+		return
+	}
+
+	if change := roundQuantity(input.Quantity - ingredient.Quantity); change != 0 {
+		if err := moveIngredient(tx, &ingredient, change, models_inventory.IngredientCount, nil, nil, input.Note, currentUserID(c)); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredient Counted Successfully", "ingredient": ingredient})
+}
+
+// AddWastage records a quantity of an ingredient thrown away
+// @Summary Record wastage
+// @Description Remove a spoiled, dropped or returned quantity of an ingredient from the stock
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient_id path string true "Ingredient ID"
+// @Param wastage body models_inventory.AddWastageData true "Wastage data"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients/{ingredient_id}/wastage [post]
+func AddWastage(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AddWastageData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	var ingredient models_inventory.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ingredient, "id = ? AND restaurant_id = ?", c.Param("ingredient_id"), restaurantID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+		return
+	}
+
+	if err := moveIngredient(tx, &ingredient, -input.Quantity, models_inventory.IngredientWastage, nil, nil, input.Reason, currentUserID(c)); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Wastage Recorded Successfully", "ingredient": ingredient})
+}
+
+// GetIngredientMovements lists the latest changes of an ingredient stock
+// @Summary List ingredient movements
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ingredient_id path string true "Ingredient ID"
+// @Param reason query string false "purchase, consumption, wastage or count"
+// @Router /api/v1/{restaurant_id}/inventory/ingredients/{ingredient_id}/movements [get]
+func GetIngredientMovements(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	query := postgres.DB.Where("restaurant_id = ? AND ingredient_id = ?", restaurantID, c.Param("ingredient_id"))
+	if reason := c.Query("reason"); reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	var movements []models_inventory.IngredientMovement
+	if err := query.Order("created_at DESC").Limit(200).Find(&movements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingredient movements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ingredient Movements Found Successfully", "movements": movements})
+}
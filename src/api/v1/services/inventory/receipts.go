@@ -0,0 +1,165 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	utils "dine-server/src/utils"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// GetPurchaseReceipts lists the purchase receipts of a restaurant, newest first
+// @Summary List purchase receipts
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param supplier_id query string false "Only the receipts of a supplier"
+// @Router /api/v1/{restaurant_id}/inventory/receipts [get]
+func GetPurchaseReceipts(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	query := postgres.DB.Preload("Supplier").Where("restaurant_id = ?", restaurantID)
+	if supplierID := c.Query("supplier_id"); supplierID != "" {
+		query = query.Where("supplier_id = ?", supplierID)
+	}
+
+	var receipts []models_inventory.PurchaseReceipt
+	if err := query.Order("received_at DESC").Limit(200).Find(&receipts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch purchase receipts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Purchase Receipts Found Successfully", "receipts": receipts})
+}
+
+// GetPurchaseReceipt returns a purchase receipt with its lines
+// @Summary Get a purchase receipt
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param receipt_id path string true "Receipt ID"
+// @Router /api/v1/{restaurant_id}/inventory/receipts/{receipt_id} [get]
+func GetPurchaseReceipt(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var receipt models_inventory.PurchaseReceipt
+	if err := postgres.DB.Preload("Supplier").Preload("Lines.Ingredient").
+		First(&receipt, "id = ? AND restaurant_id = ?", c.Param("receipt_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase receipt not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Purchase Receipt Found Successfully", "receipt": receipt})
+}
+
+// CreatePurchaseReceipt records ingredients received from a supplier
+// @Summary Receive a purchase
+// @Description Add the received ingredients to the stock. The cost per unit of each ingredient becomes the weighted average of what was on hand and what was received.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param receipt body models_inventory.AddPurchaseReceiptData true "Receipt data"
+// @Router /api/v1/{restaurant_id}/inventory/receipts [post]
+func CreatePurchaseReceipt(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AddPurchaseReceiptData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.SupplierID != nil {
+		var supplier models_inventory.Supplier
+		if err := postgres.DB.First(&supplier, "id = ? AND restaurant_id = ?", *input.SupplierID, restaurantID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+			return
+		}
+	}
+
+	receivedAt := time.Now()
+	if input.ReceivedAt != nil {
+		receivedAt = *input.ReceivedAt
+	}
+
+	ingredientIDs := make([]uuid.UUID, 0, len(input.Lines))
+	for _, line := range input.Lines {
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	tx := postgres.DB.Begin()
+
+	var ingredients []models_inventory.Ingredient
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("restaurant_id = ? AND id IN ?", restaurantID, ingredientIDs).
+		Order("id").
+		Find(&ingredients).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingredients"})
+		return
+	}
+	byID := make(map[uuid.UUID]*models_inventory.Ingredient, len(ingredients))
+	for i := range ingredients {
+		byID[ingredients[i].ID] = &ingredients[i]
+	}
+
+	receipt := models_inventory.PurchaseReceipt{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: uuid.FromStringOrNil(restaurantID),
+		SupplierID:   input.SupplierID,
+		Reference:    input.Reference,
+		ReceivedAt:   receivedAt,
+		CreatedBy:    currentUserID(c),
+	}
+	for _, line := range input.Lines {
+		if _, ok := byID[line.IngredientID]; !ok {
+			tx.Rollback()
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+			return
+		}
+		total := utils.RoundAmount(line.Quantity * line.UnitCost)
+		receipt.Lines = append(receipt.Lines, models_inventory.PurchaseReceiptLine{
+			ID:           uuid.Must(uuid.NewV4()),
+			IngredientID: line.IngredientID,
+			Quantity:     roundQuantity(line.Quantity),
+			UnitCost:     line.UnitCost,
+			Total:        total,
+		})
+		receipt.Total += total
+	}
+	receipt.Total = utils.RoundAmount(receipt.Total)
+
+	if err := tx.Create(&receipt).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create purchase receipt"})
+		return
+	}
+
+	for _, line := range receipt.Lines {
+		ingredient := byID[line.IngredientID]
+		// What is owed to the stock (a negative quantity) carries no value into the average
+		onHand := math.Max(ingredient.Quantity, 0)
+		ingredient.CostPerUnit = math.Round((onHand*ingredient.CostPerUnit+line.Quantity*line.UnitCost)/(onHand+line.Quantity)*10000) / 10000
+		if err := moveIngredient(tx, ingredient, line.Quantity, models_inventory.IngredientPurchase, nil, &receipt.ID, receipt.Reference, receipt.CreatedBy); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Purchase Receipt Created Successfully", "receipt": receipt})
+}
@@ -0,0 +1,179 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	models_menu "dine-server/src/models/menu"
+	utils "dine-server/src/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// GetRecipe returns the recipe of a menu item with the food cost of each option
+// @Summary Get a recipe
+// @Description Get the ingredients used by one portion of a menu item and what they cost at the current average prices
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param item_id path string true "Menu item ID"
+// @Router /api/v1/{restaurant_id}/inventory/recipes/items/{item_id} [get]
+func GetRecipe(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	item, err := restaurantMenuItem(postgres.DB, restaurantID, uuid.FromStringOrNil(c.Param("item_id")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu item not found"})
+		return
+	}
+
+	var lines []models_inventory.RecipeLine
+	if err := postgres.DB.Preload("Ingredient").Where("menu_item_id = ?", item.ID).Order("created_at").Find(&lines).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recipe"})
+		return
+	}
+
+	var options []models_menu.MenuItemOption
+	if err := postgres.DB.Where("menu_item_id = ?", item.ID).Order("position, price").Find(&options).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch item options"})
+		return
+	}
+
+	costs := make([]gin.H, 0, len(options))
+	for _, option := range options {
+		cost := 0.0
+		for _, line := range lines {
+			if line.Ingredient != nil && (line.ItemOptionID == nil || *line.ItemOptionID == option.ID) {
+				cost += line.Quantity * line.Ingredient.CostPerUnit
+			}
+		}
+		costs = append(costs, gin.H{
+			"item_option_id": option.ID,
+			"name":           option.Name,
+			"price":          option.Price,
+			"food_cost":      utils.RoundAmount(cost),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe Found Successfully", "menu_item_id": item.ID, "lines": lines, "costs": costs})
+}
+
+// SetRecipe replaces the recipe of a menu item
+// @Summary Set a recipe
+// @Description Replace the ingredients used by one portion of a menu item. Lines without an option are used by every option.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param item_id path string true "Menu item ID"
+// @Param recipe body models_inventory.SetRecipeData true "Recipe data"
+// @Router /api/v1/{restaurant_id}/inventory/recipes/items/{item_id} [put]
+func SetRecipe(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.SetRecipeData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := restaurantMenuItem(postgres.DB, restaurantID, uuid.FromStringOrNil(c.Param("item_id")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu item not found"})
+		return
+	}
+
+	ingredientIDs := make([]uuid.UUID, 0, len(input.Lines))
+	optionIDs := make([]uuid.UUID, 0, len(input.Lines))
+	seen := make(map[string]bool, len(input.Lines))
+	for _, line := range input.Lines {
+		key := line.IngredientID.String()
+		if line.ItemOptionID != nil {
+			key += "/" + line.ItemOptionID.String()
+			optionIDs = append(optionIDs, *line.ItemOptionID)
+		}
+		if seen[key] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each ingredient can only be listed once per option"})
+			return
+		}
+		seen[key] = true
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	if len(ingredientIDs) > 0 {
+		var count int64
+		if err := postgres.DB.Model(&models_inventory.Ingredient{}).
+			Where("restaurant_id = ? AND id IN ?", restaurantID, ingredientIDs).
+			Distinct("id").Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingredients"})
+			return
+		}
+		if int(count) != len(uniqueIDs(ingredientIDs)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ingredient not found"})
+			return
+		}
+	}
+	if len(optionIDs) > 0 {
+		var count int64
+		if err := postgres.DB.Model(&models_menu.MenuItemOption{}).
+			Where("menu_item_id = ? AND id IN ?", item.ID, optionIDs).
+			Distinct("id").Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch item options"})
+			return
+		}
+		if int(count) != len(uniqueIDs(optionIDs)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Menu item option not found"})
+			return
+		}
+	}
+
+	lines := make([]models_inventory.RecipeLine, 0, len(input.Lines))
+	for _, line := range input.Lines {
+		lines = append(lines, models_inventory.RecipeLine{
+			ID:           uuid.Must(uuid.NewV4()),
+			RestaurantID: uuid.FromStringOrNil(restaurantID),
+			MenuItemID:   item.ID,
+			ItemOptionID: line.ItemOptionID,
+			IngredientID: line.IngredientID,
+			Quantity:     roundQuantity(line.Quantity),
+		})
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := tx.Delete(&models_inventory.RecipeLine{}, "menu_item_id = ?", item.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replace recipe"})
+		return
+	}
+	if len(lines) > 0 {
+		if err := tx.Create(&lines).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replace recipe"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe Updated Successfully", "menu_item_id": item.ID, "lines": lines})
+}
+
+// uniqueIDs drops the repeated IDs of a list
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,190 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetConsumptionReport compares the theoretical and actual use of each ingredient over a period
+// @Summary Ingredient consumption report
+// @Description Compare what completed orders should have used following the recipes with what actually left the stock through wastage and counts.
+// @Description Dates are days in the restaurant's timezone, the last 30 days by default.
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Router /api/v1/{restaurant_id}/inventory/reports/consumption [get]
+func GetConsumptionReport(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	from, to, err := ReportPeriod(postgres.DB, restaurantID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var totals []struct {
+		IngredientID uuid.UUID
+		Reason       models_inventory.IngredientMovementReason
+		Quantity     float64
+		Cost         float64
+	}
+	if err := postgres.DB.Model(&models_inventory.IngredientMovement{}).
+		Select("ingredient_id, reason, SUM(change) AS quantity, SUM(change * unit_cost) AS cost").
+		Where("restaurant_id = ? AND created_at >= ? AND created_at < ?", restaurantID, from, to).
+		Group("ingredient_id, reason").
+		Scan(&totals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute consumption"})
+		return
+	}
+
+	var ingredients []models_inventory.Ingredient
+	if err := postgres.DB.Where("restaurant_id = ?", restaurantID).Order("name").Find(&ingredients).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ingredients"})
+		return
+	}
+
+	lines := make(map[uuid.UUID]*models_inventory.ConsumptionReportLine, len(ingredients))
+	report := make([]models_inventory.ConsumptionReportLine, len(ingredients))
+	for i, ingredient := range ingredients {
+		report[i] = models_inventory.ConsumptionReportLine{IngredientID: ingredient.ID, Name: ingredient.Name, Unit: ingredient.Unit}
+		lines[ingredient.ID] = &report[i]
+	}
+
+	for _, total := range totals {
+		line, ok := lines[total.IngredientID]
+		if !ok {
+			continue
+		}
+		switch total.Reason {
+		case models_inventory.IngredientPurchase:
+			line.Purchased = total.Quantity
+		case models_inventory.IngredientConsumption:
+			line.Theoretical = -total.Quantity
+		case models_inventory.IngredientWastage:
+			line.Wastage = -total.Quantity
+			line.VarianceCost -= total.Cost
+		case models_inventory.IngredientCount:
+			line.CountChange = total.Quantity
+			line.VarianceCost -= total.Cost
+		}
+	}
+	for i := range report {
+		report[i].Actual = roundQuantity(report[i].Theoretical + report[i].Wastage - report[i].CountChange)
+		report[i].Variance = roundQuantity(report[i].Actual - report[i].Theoretical)
+		report[i].VarianceCost = utils.RoundAmount(report[i].VarianceCost)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Consumption Report Generated Successfully",
+		"from":    from,
+		"to":      to,
+		"lines":   report,
+	})
+}
+
+// GetMarginReport returns the revenue, food cost and margin of each menu item sold over a period
+// @Summary Menu item margin report
+// @Description Revenue and food cost of the menu items of completed orders, by highest revenue.
+// @Description Dates are days in the restaurant's timezone, the last 30 days by default.
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Router /api/v1/{restaurant_id}/inventory/reports/margins [get]
+func GetMarginReport(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	from, to, err := ReportPeriod(postgres.DB, restaurantID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var report []models_inventory.MarginReportLine
+	if err := postgres.DB.Model(&models_order.OrderItem{}).
+		Select("order_items.menu_item_id, MAX(order_items.menu_name) AS name, SUM(order_items.quantity) AS quantity, "+
+			"SUM(order_items.subtotal) AS revenue, SUM(order_items.food_cost) AS food_cost").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.restaurant_id = ? AND orders.status = ?", restaurantID, models_order.OrderStatusCompleted).
+		Where("COALESCE(orders.completed_at, orders.created_at) >= ? AND COALESCE(orders.completed_at, orders.created_at) < ?", from, to).
+		Group("order_items.menu_item_id").
+		Scan(&report).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute margins"})
+		return
+	}
+
+	var revenue, foodCost float64
+	for i := range report {
+		line := &report[i]
+		line.Revenue = utils.RoundAmount(line.Revenue)
+		line.FoodCost = utils.RoundAmount(line.FoodCost)
+		line.Margin = utils.RoundAmount(line.Revenue - line.FoodCost)
+		if line.Revenue > 0 {
+			line.MarginPercent = utils.RoundAmount(line.Margin / line.Revenue * 100)
+			line.FoodCostPercent = utils.RoundAmount(line.FoodCost / line.Revenue * 100)
+		}
+		revenue += line.Revenue
+		foodCost += line.FoodCost
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Revenue > report[j].Revenue })
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Margin Report Generated Successfully",
+		"from":      from,
+		"to":        to,
+		"revenue":   utils.RoundAmount(revenue),
+		"food_cost": utils.RoundAmount(foodCost),
+		"margin":    utils.RoundAmount(revenue - foodCost),
+		"lines":     report,
+	})
+}
+
+// ReportPeriod turns the from and to days of a report into times in the restaurant's timezone.
+// The period ends at the end of the to day, and covers the last 30 days when no day is given.
+func ReportPeriod(db *gorm.DB, restaurantID, fromDay, toDay string) (time.Time, time.Time, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.Select("id", "timezone").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("restaurant not found")
+	}
+	location := utils.RestaurantLocation(restaurant)
+
+	now := time.Now().In(location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location).AddDate(0, 0, 1)
+	if toDay != "" {
+		day, err := time.ParseInLocation("2006-01-02", toDay, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be a date like 2006-01-02")
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromDay != "" {
+		day, err := time.ParseInLocation("2006-01-02", fromDay, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be a date like 2006-01-02")
+		}
+		from = day
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,303 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	models_menu "dine-server/src/models/menu"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetItemStocks lists the tracked stocks of a restaurant
+// @Summary List item stocks
+// @Description List the items and options whose portions are counted, with what is left today
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param low query bool false "Only stocks at or below their low stock threshold"
+// @Router /api/v1/{restaurant_id}/inventory/stock [get]
+func GetItemStocks(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	// Stocks due for their daily reset are reset before being listed
+	if _, err := LoadStockState(postgres.DB, restaurantID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset stock"})
+		return
+	}
+
+	query := postgres.DB.Preload("MenuItem").Preload("ItemOption").Where("restaurant_id = ?", restaurantID)
+	if c.Query("low") == "true" {
+		query = query.Where("quantity <= low_stock_threshold")
+	}
+
+	var stocks []models_inventory.ItemStock
+	if err := query.Order("quantity").Find(&stocks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item Stocks Found Successfully", "stocks": stocks})
+}
+
+// SetItemStock starts counting the portions of an item or option, or updates its count and levels
+// @Summary Set item stock
+// @Description Set what is left of an item, or of one of its options, with the daily par level and the low stock threshold.
+// @Description An item is counted either as a whole or per option.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param stock body models_inventory.SetItemStockData true "Stock data"
+// @Router /api/v1/{restaurant_id}/inventory/stock [put]
+func SetItemStock(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.SetItemStockData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := restaurantMenuItem(postgres.DB, restaurantID, input.MenuItemID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu item not found"})
+		return
+	}
+	if input.ItemOptionID != nil {
+		var option models_menu.MenuItemOption
+		if err := postgres.DB.First(&option, "id = ? AND menu_item_id = ?", *input.ItemOptionID, item.ID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Menu item option not found"})
+			return
+		}
+	}
+
+	today, err := restaurantToday(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	var existing []models_inventory.ItemStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("menu_item_id = ?", item.ID).Find(&existing).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock"})
+		return
+	}
+
+	var stock *models_inventory.ItemStock
+	for i := range existing {
+		switch {
+		case existing[i].ItemOptionID == nil && input.ItemOptionID == nil,
+			existing[i].ItemOptionID != nil && input.ItemOptionID != nil && *existing[i].ItemOptionID == *input.ItemOptionID:
+			stock = &existing[i]
+		case existing[i].ItemOptionID == nil:
+			tx.Rollback()
+			c.JSON(http.StatusConflict, gin.H{"error": item.Name + " is counted as a whole, remove its stock to count it per option"})
+			return
+		case input.ItemOptionID == nil:
+			tx.Rollback()
+			c.JSON(http.StatusConflict, gin.H{"error": item.Name + " is counted per option, remove their stock to count it as a whole"})
+			return
+		}
+	}
+
+	status := http.StatusOK
+	if stock == nil {
+		stock = &models_inventory.ItemStock{
+			ID:                uuid.Must(uuid.NewV4()),
+			RestaurantID:      uuid.FromStringOrNil(restaurantID),
+			MenuItemID:        item.ID,
+			ItemOptionID:      input.ItemOptionID,
+			ParLevel:          input.ParLevel,
+			LowStockThreshold: input.LowStockThreshold,
+			ResetDate:         today,
+		}
+		if err := tx.Create(stock).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create stock"})
+			return
+		}
+		status = http.StatusCreated
+	} else {
+		stock.ParLevel, stock.LowStockThreshold = input.ParLevel, input.LowStockThreshold
+		if err := tx.Model(&models_inventory.ItemStock{}).Where("id = ?", stock.ID).
+			Updates(map[string]interface{}{"par_level": stock.ParLevel, "low_stock_threshold": stock.LowStockThreshold}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update stock"})
+			return
+		}
+	}
+
+	if change := input.Quantity - stock.Quantity; change != 0 {
+		if err := moveStock(tx, stock, change, models_inventory.MovementAdjust, nil, "", "Counted", currentUserID(c)); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(status, gin.H{"message": "Item Stock Saved Successfully", "stock": stock})
+}
+
+// AdjustItemStock adds or removes portions from a stock
+// @Summary Adjust item stock
+// @Description Add portions for a delivery or a new batch, or remove them for losses
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param stock_id path string true "Stock ID"
+// @Param adjustment body models_inventory.AdjustItemStockData true "Adjustment data"
+// @Router /api/v1/{restaurant_id}/inventory/stock/{stock_id}/adjust [post]
+func AdjustItemStock(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AdjustItemStockData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	var stock models_inventory.ItemStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&stock, "id = ? AND restaurant_id = ?", c.Param("stock_id"), restaurantID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
+		return
+	}
+	if stock.Quantity+input.Change < 0 {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Only %d left", stock.Quantity)})
+		return
+	}
+
+	if err := moveStock(tx, &stock, input.Change, models_inventory.MovementAdjust, nil, "", input.Note, currentUserID(c)); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item Stock Adjusted Successfully", "stock": stock})
+}
+
+// DeleteItemStock stops counting the portions of an item or option
+// @Summary Stop tracking item stock
+// @Description Stop counting an item or option, it can be ordered without limit again
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param stock_id path string true "Stock ID"
+// @Router /api/v1/{restaurant_id}/inventory/stock/{stock_id} [delete]
+func DeleteItemStock(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Delete(&models_inventory.ItemStock{}, "id = ? AND restaurant_id = ?", c.Param("stock_id"), restaurantID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete stock"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item Stock Deleted Successfully"})
+}
+
+// GetStockAlerts lists the low stock alerts of a restaurant, newest first
+// @Summary List stock alerts
+// @Description List the alerts raised when a stock dropped to its threshold or ran out
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param status query string false "open or acknowledged"
+// @Router /api/v1/{restaurant_id}/inventory/alerts [get]
+func GetStockAlerts(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	query := postgres.DB.Where("restaurant_id = ?", restaurantID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var alerts []models_inventory.StockAlert
+	if err := query.Order("created_at DESC").Limit(200).Find(&alerts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock Alerts Found Successfully", "alerts": alerts})
+}
+
+// AcknowledgeStockAlert marks a stock alert as seen
+// @Summary Acknowledge a stock alert
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param alert_id path string true "Alert ID"
+// @Router /api/v1/{restaurant_id}/inventory/alerts/{alert_id}/acknowledge [post]
+func AcknowledgeStockAlert(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Model(&models_inventory.StockAlert{}).
+		Where("id = ? AND restaurant_id = ? AND status = ?", c.Param("alert_id"), restaurantID, models_inventory.AlertOpen).
+		Updates(map[string]interface{}{"status": models_inventory.AlertAcknowledged, "acknowledged_at": time.Now(), "acknowledged_by": currentUserID(c)})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to acknowledge stock alert"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open stock alert not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock Alert Acknowledged Successfully"})
+}
+
+// restaurantMenuItem fetches a menu item from a menu of the restaurant
+func restaurantMenuItem(db *gorm.DB, restaurantID string, itemID uuid.UUID) (models_menu.MenuItem, error) {
+	var item models_menu.MenuItem
+	err := db.Joins("JOIN menus ON menus.id = menu_items.menu_id").
+		Where("menu_items.id = ? AND menus.restaurant_id = ?", itemID, restaurantID).
+		First(&item).Error
+	return item, err
+}
+
+// currentUserID returns the ID of the signed in user, nil for guests
+func currentUserID(c *gin.Context) *uuid.UUID {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return nil
+	}
+	id, err := uuid.FromString(fmt.Sprint(userID))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
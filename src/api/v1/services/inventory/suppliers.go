@@ -0,0 +1,124 @@
+package services_inventory
+
+import (
+	postgres "dine-server/src/config/database"
+	models_inventory "dine-server/src/models/inventory"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// GetSuppliers lists the suppliers of a restaurant
+// @Summary List suppliers
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/inventory/suppliers [get]
+func GetSuppliers(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var suppliers []models_inventory.Supplier
+	if err := postgres.DB.Where("restaurant_id = ?", restaurantID).Order("name").Find(&suppliers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suppliers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suppliers Found Successfully", "suppliers": suppliers})
+}
+
+// CreateSupplier adds a supplier to a restaurant
+// @Summary Create a supplier
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param supplier body models_inventory.AddSupplierData true "Supplier data"
+// @Router /api/v1/{restaurant_id}/inventory/suppliers [post]
+func CreateSupplier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AddSupplierData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier := models_inventory.Supplier{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: uuid.FromStringOrNil(restaurantID),
+		Name:         input.Name,
+		Phone:        input.Phone,
+		Email:        input.Email,
+		Notes:        input.Notes,
+	}
+	if err := postgres.DB.Create(&supplier).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create supplier"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Supplier Created Successfully", "supplier": supplier})
+}
+
+// UpdateSupplier replaces the details of a supplier
+// @Summary Update a supplier
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param supplier_id path string true "Supplier ID"
+// @Param supplier body models_inventory.AddSupplierData true "Supplier data"
+// @Router /api/v1/{restaurant_id}/inventory/suppliers/{supplier_id} [put]
+func UpdateSupplier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_inventory.AddSupplierData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var supplier models_inventory.Supplier
+	if err := postgres.DB.First(&supplier, "id = ? AND restaurant_id = ?", c.Param("supplier_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+		return
+	}
+
+	supplier.Name = input.Name
+	supplier.Phone = input.Phone
+	supplier.Email = input.Email
+	supplier.Notes = input.Notes
+	if err := postgres.DB.Save(&supplier).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update supplier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier Updated Successfully", "supplier": supplier})
+}
+
+// DeleteSupplier removes a supplier, its receipts are kept without it
+// @Summary Delete a supplier
+// @Tags Inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param supplier_id path string true "Supplier ID"
+// @Router /api/v1/{restaurant_id}/inventory/suppliers/{supplier_id} [delete]
+func DeleteSupplier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Delete(&models_inventory.Supplier{}, "id = ? AND restaurant_id = ?", c.Param("supplier_id"), restaurantID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete supplier"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Supplier not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier Deleted Successfully"})
+}
@@ -0,0 +1,230 @@
+package services_kitchen
+
+import (
+	postgres "dine-server/src/config/database"
+	models_kitchen "dine-server/src/models/kitchen"
+	models_menu "dine-server/src/models/menu"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// CreateStation handles the creation of a new kitchen station
+// @Summary Create a new kitchen station
+// @Description Create a preparation station (tandoor, bar, desserts...) for a restaurant
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station body models_kitchen.AddStationData true "Station data"
+// @Router /api/v1/{restaurant_id}/kitchen/stations [post]
+func CreateStation(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var input models_kitchen.AddStationData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existingStation models_kitchen.Station
+	if err := postgres.DB.Where("name = ? AND restaurant_id = ?", input.Name, restaurantUUID).First(&existingStation).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "This Restaurant has a Station with the same name"})
+		return
+	}
+
+	station := models_kitchen.Station{
+		RestaurantID: restaurantUUID,
+		Name:         input.Name,
+		IsActive:     true,
+	}
+	if input.IsActive != nil {
+		station.IsActive = *input.IsActive
+	}
+
+	if err := postgres.DB.Create(&station).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create station"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Station created successfully", "station": station})
+}
+
+// GetStations retrieves all kitchen stations of a restaurant
+// @Summary Retrieve all kitchen stations
+// @Description Retrieve all kitchen stations of a restaurant
+// @Tags Kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/kitchen/stations [get]
+func GetStations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var stations []models_kitchen.Station
+	if err := postgres.DB.Where("restaurant_id = ?", restaurantID).Order("name").Find(&stations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stations Found Successfully", "stations": stations})
+}
+
+// UpdateStation updates a kitchen station by ID
+// @Summary Update a kitchen station
+// @Description Update a kitchen station by ID
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station_id path string true "Station ID"
+// @Param station body models_kitchen.UpdateStationData true "Station data"
+// @Router /api/v1/{restaurant_id}/kitchen/stations/{station_id} [put]
+func UpdateStation(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var station models_kitchen.Station
+	if err := postgres.DB.First(&station, "id = ? AND restaurant_id = ?", c.Param("station_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Station not found"})
+		return
+	}
+
+	var input models_kitchen.UpdateStationData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Name != "" {
+		station.Name = input.Name
+	}
+	if input.IsActive != nil {
+		station.IsActive = *input.IsActive
+	}
+
+	if err := postgres.DB.Save(&station).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update station"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Station Updated Successfully", "station": station})
+}
+
+// DeleteStation deletes a kitchen station and unassigns its menu categories and items
+// @Summary Delete a kitchen station
+// @Description Delete a kitchen station by ID
+// @Tags Kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station_id path string true "Station ID"
+// @Router /api/v1/{restaurant_id}/kitchen/stations/{station_id} [delete]
+func DeleteStation(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var station models_kitchen.Station
+	if err := postgres.DB.First(&station, "id = ? AND restaurant_id = ?", c.Param("station_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Station not found"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if err := tx.Model(&models_menu.MenuCategory{}).Where("station_id = ?", station.ID).Update("station_id", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unassign categories"})
+		return
+	}
+	if err := tx.Model(&models_menu.MenuItem{}).Where("station_id = ?", station.ID).Update("station_id", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unassign items"})
+		return
+	}
+	if err := tx.Delete(&station).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete station"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Station deleted successfully"})
+}
+
+// AssignStation maps menu categories and items to a kitchen station
+// @Summary Assign categories and items to a station
+// @Description Route the given menu categories and items to a kitchen station. Items override their category.
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station_id path string true "Station ID"
+// @Param input body models_kitchen.AssignStationData true "Categories and items"
+// @Router /api/v1/{restaurant_id}/kitchen/stations/{station_id}/assign [put]
+func AssignStation(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var station models_kitchen.Station
+	if err := postgres.DB.First(&station, "id = ? AND restaurant_id = ?", c.Param("station_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Station not found"})
+		return
+	}
+
+	var input models_kitchen.AssignStationData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only touch rows that belong to one of this restaurant's menus
+	restaurantMenus := postgres.DB.Model(&models_menu.Menu{}).Select("id").Where("restaurant_id = ?", restaurantID)
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if len(input.CategoryIDs) > 0 {
+		if err := tx.Model(&models_menu.MenuCategory{}).
+			Where("id IN ? AND menu_id IN (?)", input.CategoryIDs, restaurantMenus).
+			Update("station_id", station.ID).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign categories"})
+			return
+		}
+	}
+	if len(input.ItemIDs) > 0 {
+		if err := tx.Model(&models_menu.MenuItem{}).
+			Where("id IN ? AND menu_id IN (?)", input.ItemIDs, restaurantMenus).
+			Update("station_id", station.ID).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign items"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Station assigned successfully", "station": station})
+}
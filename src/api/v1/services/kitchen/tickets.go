@@ -0,0 +1,289 @@
+package services_kitchen
+
+import (
+	postgres "dine-server/src/config/database"
+	models_kitchen "dine-server/src/models/kitchen"
+	models_menu "dine-server/src/models/menu"
+	models_order "dine-server/src/models/orders"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateOrderTickets splits the items of an order into one kitchen ticket per station.
+// An item is routed to its own station, falling back to the station of its category;
+// items without an active station are grouped on a ticket with no station.
+func CreateOrderTickets(tx *gorm.DB, order models_order.Order, orderItems []models_order.OrderItem) ([]models_kitchen.KitchenTicket, error) {
+	if len(orderItems) == 0 {
+		return nil, nil
+	}
+
+	var menuItemIDs []uuid.UUID
+	for _, item := range orderItems {
+		menuItemIDs = append(menuItemIDs, item.MenuItemID)
+	}
+
+	var menuItems []models_menu.MenuItem
+	if err := tx.Preload("Category").Where("id IN ?", menuItemIDs).Find(&menuItems).Error; err != nil {
+		return nil, fmt.Errorf("failed to load menu items")
+	}
+	menuItemByID := make(map[uuid.UUID]models_menu.MenuItem, len(menuItems))
+	for _, menuItem := range menuItems {
+		menuItemByID[menuItem.ID] = menuItem
+	}
+
+	var stations []models_kitchen.Station
+	if err := tx.Where("restaurant_id = ? AND is_active = ?", order.RestaurantID, true).Find(&stations).Error; err != nil {
+		return nil, fmt.Errorf("failed to load stations")
+	}
+	stationByID := make(map[uuid.UUID]models_kitchen.Station, len(stations))
+	for _, station := range stations {
+		stationByID[station.ID] = station
+	}
+
+	// Group order items by station, keeping the order in which stations first appear
+	ticketByStation := make(map[uuid.UUID]*models_kitchen.KitchenTicket)
+	var stationOrder []uuid.UUID
+	for _, item := range orderItems {
+		menuItem := menuItemByID[item.MenuItemID]
+
+		stationID := uuid.Nil
+		if menuItem.StationID != nil {
+			stationID = *menuItem.StationID
+		} else if menuItem.Category.StationID != nil {
+			stationID = *menuItem.Category.StationID
+		}
+		if _, ok := stationByID[stationID]; !ok {
+			stationID = uuid.Nil
+		}
+
+		ticket, ok := ticketByStation[stationID]
+		if !ok {
+			ticket = &models_kitchen.KitchenTicket{
+				ID:           uuid.Must(uuid.NewV4()),
+				RestaurantID: order.RestaurantID,
+				OrderID:      order.ID,
+				Status:       models_kitchen.TicketStatusQueued,
+			}
+			if stationID != uuid.Nil {
+				id := stationID
+				ticket.StationID = &id
+				ticket.StationName = stationByID[stationID].Name
+			}
+			ticketByStation[stationID] = ticket
+			stationOrder = append(stationOrder, stationID)
+		}
+
+		modifierNames := []string{}
+		for _, modifier := range item.Modifiers {
+			modifierNames = append(modifierNames, modifier.Name)
+		}
+
+		ticket.Items = append(ticket.Items, models_kitchen.KitchenTicketItem{
+			ID:             uuid.Must(uuid.NewV4()),
+			TicketID:       ticket.ID,
+			OrderItemID:    item.ID,
+			MenuItemID:     item.MenuItemID,
+			Name:           item.MenuName,
+			ItemOptionName: item.ItemOptionName,
+			Modifiers:      modifierNames,
+			Quantity:       item.Quantity,
+		})
+	}
+
+	var tickets []models_kitchen.KitchenTicket
+	for _, stationID := range stationOrder {
+		ticket := ticketByStation[stationID]
+		if err := tx.Create(ticket).Error; err != nil {
+			return nil, fmt.Errorf("failed to create kitchen ticket")
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	return tickets, nil
+}
+
+// CancelOrderTickets removes the kitchen tickets of a cancelled order
+func CancelOrderTickets(tx *gorm.DB, orderID uuid.UUID) error {
+	if err := tx.Where("order_id = ?", orderID).Delete(&models_kitchen.KitchenTicket{}).Error; err != nil {
+		return fmt.Errorf("failed to remove kitchen tickets")
+	}
+	return nil
+}
+
+// CompleteOrderTickets marks the open kitchen tickets of an order done once the order no longer needs the kitchen
+func CompleteOrderTickets(tx *gorm.DB, orderID uuid.UUID) error {
+	now := time.Now()
+	if err := tx.Model(&models_kitchen.KitchenTicket{}).
+		Where("order_id = ? AND status <> ?", orderID, models_kitchen.TicketStatusDone).
+		Updates(map[string]interface{}{
+			"status":       models_kitchen.TicketStatusDone,
+			"started_at":   gorm.Expr("COALESCE(started_at, ?)", now),
+			"completed_at": now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to complete kitchen tickets")
+	}
+	return nil
+}
+
+// syncOrderStatus aggregates the ticket statuses onto the parent order:
+// the order is READY once every ticket is done and PREPARING as soon as one has started.
+// Like tickets, the order only moves forward, a status set further by the staff is kept.
+func syncOrderStatus(tx *gorm.DB, orderID uuid.UUID) (models_order.OrderStatus, error) {
+	var order models_order.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", orderID).Error; err != nil {
+		return "", fmt.Errorf("order not found")
+	}
+
+	switch order.Status {
+	case models_order.OrderStatusCompleted, models_order.OrderStatusCancelled:
+		return order.Status, nil
+	}
+
+	var tickets []models_kitchen.KitchenTicket
+	if err := tx.Where("order_id = ?", orderID).Find(&tickets).Error; err != nil {
+		return "", fmt.Errorf("failed to load kitchen tickets")
+	}
+
+	allDone, started := len(tickets) > 0, false
+	for _, ticket := range tickets {
+		if ticket.Status != models_kitchen.TicketStatusDone {
+			allDone = false
+		}
+		if ticket.Status != models_kitchen.TicketStatusQueued {
+			started = true
+		}
+	}
+
+	status := order.Status
+	switch {
+	case allDone:
+		status = models_order.OrderStatusReady
+	case started:
+		status = models_order.OrderStatusPreparing
+	}
+
+	if !order.Status.CanMoveTo(status) {
+		return order.Status, nil
+	}
+	if err := tx.Model(&order).Update("status", status).Error; err != nil {
+		return "", fmt.Errorf("failed to update order status")
+	}
+
+	return status, nil
+}
+
+// GetTickets retrieves the kitchen tickets of a restaurant
+// @Summary Retrieve kitchen tickets
+// @Description Retrieve the kitchen tickets of a restaurant, oldest first. Filter by station and status.
+// @Tags Kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station_id query string false "Station ID"
+// @Param status query string false "Ticket Status Filter (queued, cooking, done)"
+// @Router /api/v1/{restaurant_id}/kitchen/tickets [get]
+func GetTickets(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	query := postgres.DB.Where("restaurant_id = ?", restaurantID)
+	if stationID := c.Query("station_id"); stationID != "" {
+		query = query.Where("station_id = ?", stationID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status <> ?", models_kitchen.TicketStatusDone)
+	}
+
+	var tickets []models_kitchen.KitchenTicket
+	if err := query.Preload("Items").Order("created_at").Find(&tickets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tickets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tickets Found Successfully", "tickets": tickets})
+}
+
+// UpdateTicketStatus updates the status of a kitchen ticket
+// @Summary Update kitchen ticket status
+// @Description Move a ticket forward from queued to cooking or done, a done ticket is never reopened. The order becomes READY when all its tickets are done.
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param ticket_id path string true "Ticket ID"
+// @Param status body models_kitchen.UpdateTicketStatusData true "New Status"
+// @Router /api/v1/{restaurant_id}/kitchen/tickets/{ticket_id}/status [put]
+func UpdateTicketStatus(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_kitchen.UpdateTicketStatusData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Lock the ticket so two updates cannot both move it
+	var ticket models_kitchen.KitchenTicket
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, "id = ? AND restaurant_id = ?", c.Param("ticket_id"), restaurantID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+		return
+	}
+	if !ticket.Status.CanMoveTo(input.Status) {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ticket cannot move from " + string(ticket.Status) + " to " + string(input.Status)})
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": input.Status}
+	switch input.Status {
+	case models_kitchen.TicketStatusCooking:
+		updates["started_at"] = now
+	case models_kitchen.TicketStatusDone:
+		if ticket.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		updates["completed_at"] = now
+	}
+
+	if err := tx.Model(&ticket).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ticket status"})
+		return
+	}
+
+	orderStatus, err := syncOrderStatus(tx, ticket.OrderID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	postgres.DB.Preload("Items").First(&ticket, "id = ?", ticket.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Ticket status updated successfully",
+		"ticket":       ticket,
+		"order_status": orderStatus,
+	})
+}
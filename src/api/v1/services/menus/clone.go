@@ -0,0 +1,304 @@
+package services_menu
+
+import (
+	"dine-server/src/config/cache"
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CloneMenu copies a menu into the same or another restaurant
+// @Summary Clone a menu
+// @Description Copy a menu with its schedules, categories, items, options and modifiers into a restaurant the admin manages, optionally adjusting every price by a percentage. Combos, stock and translations are not copied, kitchen stations only within the same restaurant.
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param clone body models_menu.CloneMenuData false "Clone data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/clone [post]
+func CloneMenu(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.CloneMenuData
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	target := restaurantID
+	if input.RestaurantID != nil {
+		target = input.RestaurantID.String()
+	}
+	if target != restaurantID {
+		if allowed, err := utils.HasPermission(c, target, models_restaurant.PermissionMenuCreate); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		} else if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create menu for the target restaurant"})
+			return
+		}
+	}
+
+	source, err := loadMenuForClone(postgres.DB, restaurantID, c.Param("menu_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+	if input.Name == "" && target == restaurantID {
+		input.Name = source.Name + " (copy)"
+	}
+
+	createMenuCopy(c, source, target, input, target == restaurantID)
+}
+
+// CreateMenuFromTemplate creates a menu of the restaurant from a published menu template
+// @Summary Create a menu from a template
+// @Description Copy a published menu template into the restaurant, optionally adjusting every price by a percentage.
+// @Tags Menu Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param template_id path string true "Template ID"
+// @Param clone body models_menu.CloneMenuData false "Clone data"
+// @Router /api/v1/{restaurant_id}/menus/from-template/{template_id} [post]
+func CreateMenuFromTemplate(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.CloneMenuData
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var template models_menu.MenuTemplate
+	if err := postgres.DB.First(&template, "id = ? AND is_published = ?", c.Param("template_id"), true).Error; err != nil || template.Menu == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu template not found"})
+		return
+	}
+	if input.Name == "" {
+		input.Name = template.Name
+	}
+
+	createMenuCopy(c, *template.Menu, restaurantID, input, false)
+}
+
+// createMenuCopy checks and saves a copy of a menu in the restaurant, then responds with it
+func createMenuCopy(c *gin.Context, source models_menu.Menu, restaurantID string, input models_menu.CloneMenuData, keepStations bool) {
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	if nonVeg, err := pureVegViolations(postgres.DB, restaurantID, []models_menu.Menu{source}); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	} else if len(nonVeg) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot serve non-veg items", "items": nonVeg})
+		return
+	}
+
+	name := source.Name
+	if input.Name != "" {
+		name = input.Name
+	}
+	var existingMenu models_menu.Menu
+	if err := postgres.DB.Where("name = ? AND restaurant_id = ?", name, restaurantID).First(&existingMenu).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "This Restaurant has a Menu with the same name"})
+		return
+	}
+
+	menu := copyMenu(source, restaurantUUID, name, input.PriceAdjustment, keepStations)
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if err := saveMenuCopy(tx, menu); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	// The route only drops the cached tree of the source restaurant
+	cache.MenuTrees.Delete(restaurantID)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Menu Cloned Successfully", "menu": menu})
+}
+
+// loadMenuForClone loads a menu of the restaurant with everything a copy keeps
+func loadMenuForClone(db *gorm.DB, restaurantID, menuID string) (models_menu.Menu, error) {
+	var menu models_menu.Menu
+	err := db.
+		Preload("Schedules").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("menu_categories.position, menu_categories.created_at") }).
+		Preload("Categories.Schedules").
+		Preload("Categories.MenuItems", func(db *gorm.DB) *gorm.DB { return db.Order("menu_items.position, menu_items.created_at") }).
+		Preload("Categories.MenuItems.ItemOptions", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_options.position, menu_item_options.price") }).
+		Preload("Categories.MenuItems.ModifierGroups", func(db *gorm.DB) *gorm.DB { return db.Order("modifier_groups.created_at") }).
+		Preload("Categories.MenuItems.ModifierGroups.Modifiers", func(db *gorm.DB) *gorm.DB { return db.Order("modifiers.created_at") }).
+		First(&menu, "id = ? AND restaurant_id = ?", menuID, restaurantID).Error
+	return menu, err
+}
+
+// copyMenu gives a menu and everything in it new IDs in the restaurant, adjusting every price by a percentage
+func copyMenu(source models_menu.Menu, restaurantID uuid.UUID, name string, priceAdjustment float64, keepStations bool) models_menu.Menu {
+	price := func(price float64) float64 {
+		return utils.RoundAmount(price * (1 + priceAdjustment/100))
+	}
+	station := func(stationID *uuid.UUID) *uuid.UUID {
+		if keepStations {
+			return stationID
+		}
+		return nil
+	}
+
+	menu := models_menu.Menu{ID: uuid.Must(uuid.NewV4()), RestaurantID: restaurantID, Name: name}
+	for _, schedule := range source.Schedules {
+		menu.Schedules = append(menu.Schedules, copySchedule(schedule, &menu.ID, nil))
+	}
+
+	for _, sourceCategory := range source.Categories {
+		category := models_menu.MenuCategory{
+			ID:           uuid.Must(uuid.NewV4()),
+			MenuID:       menu.ID,
+			Name:         sourceCategory.Name,
+			ImageURL:     sourceCategory.ImageURL,
+			ThumbnailURL: sourceCategory.ThumbnailURL,
+			Description:  sourceCategory.Description,
+			StationID:    station(sourceCategory.StationID),
+			Position:     sourceCategory.Position,
+		}
+		for _, schedule := range sourceCategory.Schedules {
+			category.Schedules = append(category.Schedules, copySchedule(schedule, nil, &category.ID))
+		}
+
+		for _, sourceItem := range sourceCategory.MenuItems {
+			item := models_menu.MenuItem{
+				ID:           uuid.Must(uuid.NewV4()),
+				MenuID:       menu.ID,
+				CategoryID:   category.ID,
+				Name:         sourceItem.Name,
+				Description:  sourceItem.Description,
+				ImageURL:     sourceItem.ImageURL,
+				ThumbnailURL: sourceItem.ThumbnailURL,
+				IsVegetarian: sourceItem.IsVegetarian,
+				IsAvailable:  sourceItem.IsAvailable,
+				Diets:        sourceItem.Diets,
+				Allergens:    sourceItem.Allergens,
+				SpiceLevel:   sourceItem.SpiceLevel,
+				Nutrition:    sourceItem.Nutrition,
+				StationID:    station(sourceItem.StationID),
+				Position:     sourceItem.Position,
+			}
+			for _, sourceOption := range sourceItem.ItemOptions {
+				item.ItemOptions = append(item.ItemOptions, models_menu.MenuItemOption{
+					ID:         uuid.Must(uuid.NewV4()),
+					MenuItemID: item.ID,
+					Name:       sourceOption.Name,
+					Price:      price(sourceOption.Price),
+					Position:   sourceOption.Position,
+				})
+			}
+			for _, sourceGroup := range sourceItem.ModifierGroups {
+				group := models_menu.ModifierGroup{
+					ID:         uuid.Must(uuid.NewV4()),
+					MenuItemID: item.ID,
+					Name:       sourceGroup.Name,
+					MinSelect:  sourceGroup.MinSelect,
+					MaxSelect:  sourceGroup.MaxSelect,
+				}
+				for _, sourceModifier := range sourceGroup.Modifiers {
+					group.Modifiers = append(group.Modifiers, models_menu.Modifier{
+						ID:          uuid.Must(uuid.NewV4()),
+						GroupID:     group.ID,
+						Name:        sourceModifier.Name,
+						Price:       price(sourceModifier.Price),
+						IsAvailable: sourceModifier.IsAvailable,
+					})
+				}
+				item.ModifierGroups = append(item.ModifierGroups, group)
+			}
+			category.MenuItems = append(category.MenuItems, item)
+		}
+		menu.Categories = append(menu.Categories, category)
+	}
+	return menu
+}
+
+func copySchedule(source models_menu.MenuSchedule, menuID, categoryID *uuid.UUID) models_menu.MenuSchedule {
+	return models_menu.MenuSchedule{
+		ID:         uuid.Must(uuid.NewV4()),
+		MenuID:     menuID,
+		CategoryID: categoryID,
+		Days:       source.Days,
+		StartTime:  source.StartTime,
+		EndTime:    source.EndTime,
+		StartDate:  source.StartDate,
+		EndDate:    source.EndDate,
+	}
+}
+
+// saveMenuCopy creates a copied menu level by level
+func saveMenuCopy(tx *gorm.DB, menu models_menu.Menu) error {
+	var (
+		schedules  = menu.Schedules
+		categories []models_menu.MenuCategory
+		items      []models_menu.MenuItem
+		options    []models_menu.MenuItemOption
+		groups     []models_menu.ModifierGroup
+		modifiers  []models_menu.Modifier
+	)
+	for _, category := range menu.Categories {
+		categories = append(categories, category)
+		schedules = append(schedules, category.Schedules...)
+		for _, item := range category.MenuItems {
+			items = append(items, item)
+			options = append(options, item.ItemOptions...)
+			for _, group := range item.ModifierGroups {
+				groups = append(groups, group)
+				modifiers = append(modifiers, group.Modifiers...)
+			}
+		}
+	}
+
+	if err := tx.Omit(clause.Associations).Create(&menu).Error; err != nil {
+		return err
+	}
+	for _, records := range []interface{}{&categories, &schedules, &items, &options, &groups, &modifiers} {
+		if err := createAll(tx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createAll creates a slice of records without their associations, doing nothing for an empty slice
+func createAll(tx *gorm.DB, records interface{}) error {
+	if reflect.ValueOf(records).Elem().Len() == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(records).Error
+}
@@ -0,0 +1,316 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateCombo adds a combo with its slots to a menu
+// @Summary Create a combo
+// @Description Add a combo sold at a bundle price, e.g. "Burger + fries + drink", with a choice of items per slot and optional upcharges
+// @Tags Menu Combos
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param combo body models_menu.AddComboData true "Combo data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/combos [post]
+func CreateCombo(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.AddComboData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var menu models_menu.Menu
+	if err := postgres.DB.First(&menu, "id = ? AND restaurant_id = ?", c.Param("menu_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	if err := checkComboCategory(postgres.DB, menu.ID, input.CategoryID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slots, err := buildComboSlots(postgres.DB, restaurantID, input.Slots)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	combo := models_menu.Combo{
+		MenuID:      menu.ID,
+		CategoryID:  input.CategoryID,
+		Name:        input.Name,
+		Price:       input.Price,
+		IsAvailable: true,
+		Slots:       slots,
+	}
+	if input.Description != "" {
+		combo.Description = &input.Description
+	}
+	if input.ImageURL != "" {
+		combo.ImageURL = &input.ImageURL
+	}
+	if input.IsAvailable != nil {
+		combo.IsAvailable = *input.IsAvailable
+	}
+
+	// Creates the combo with its slots and slot items together
+	if err := postgres.DB.Create(&combo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create combo"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Combo Created Successfully", "combo": combo})
+}
+
+// GetCombos retrieves the combos of a menu
+// @Summary Get combos
+// @Description Get the combos of a menu with their slots and items
+// @Tags Menu Combos
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/combos [get]
+func GetCombos(c *gin.Context) {
+	var menu models_menu.Menu
+	if err := postgres.DB.First(&menu, "id = ? AND restaurant_id = ?", c.Param("menu_id"), c.Param("restaurant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	var combos []models_menu.Combo
+	if err := preloadComboSlots(postgres.DB).Where("menu_id = ?", menu.ID).Order("created_at").Find(&combos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch combos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Combos Found Successfully", "combos": combos})
+}
+
+// GetComboByID retrieves a combo
+// @Summary Get a combo
+// @Description Get a combo with its slots and items
+// @Tags Menu Combos
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param combo_id path string true "Combo ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/combos/{combo_id} [get]
+func GetComboByID(c *gin.Context) {
+	combo, err := findCombo(preloadComboSlots(postgres.DB), c.Param("restaurant_id"), c.Param("menu_id"), c.Param("combo_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Combo not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Combo Found Successfully", "combo": combo})
+}
+
+// UpdateCombo updates a combo, replacing its slots when they are given
+// @Summary Update a combo
+// @Description Update the details of a combo. Slots, when given, replace every existing slot.
+// @Tags Menu Combos
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param combo_id path string true "Combo ID"
+// @Param combo body models_menu.UpdateComboData true "Combo data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/combos/{combo_id} [put]
+func UpdateCombo(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.UpdateComboData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	combo, err := findCombo(postgres.DB, restaurantID, c.Param("menu_id"), c.Param("combo_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Combo not found"})
+		return
+	}
+
+	if input.Name != "" {
+		combo.Name = input.Name
+	}
+	if input.Description != nil {
+		combo.Description = input.Description
+	}
+	if input.ImageURL != nil {
+		combo.ImageURL = input.ImageURL
+	}
+	if input.CategoryID != nil {
+		if err := checkComboCategory(postgres.DB, combo.MenuID, input.CategoryID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		combo.CategoryID = input.CategoryID
+	}
+	if input.Price != nil {
+		combo.Price = *input.Price
+	}
+	if input.IsAvailable != nil {
+		combo.IsAvailable = *input.IsAvailable
+	}
+
+	var slots []models_menu.ComboSlot
+	if input.Slots != nil {
+		slots, err = buildComboSlots(postgres.DB, restaurantID, *input.Slots)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := tx.Save(&combo).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update combo"})
+		return
+	}
+
+	if input.Slots != nil {
+		if err := tx.Where("combo_id = ?", combo.ID).Delete(&models_menu.ComboSlot{}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update combo slots"})
+			return
+		}
+		for i := range slots {
+			slots[i].ComboID = combo.ID
+		}
+		if err := tx.Create(&slots).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update combo slots"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update combo"})
+		return
+	}
+
+	combo, err = findCombo(preloadComboSlots(postgres.DB), restaurantID, c.Param("menu_id"), c.Param("combo_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch combo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Combo Updated Successfully", "combo": combo})
+}
+
+// DeleteCombo deletes a combo with its slots
+// @Summary Delete a combo
+// @Description Delete a combo with its slots. Past orders keep their combo snapshot.
+// @Tags Menu Combos
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param combo_id path string true "Combo ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/combos/{combo_id} [delete]
+func DeleteCombo(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	combo, err := findCombo(postgres.DB, restaurantID, c.Param("menu_id"), c.Param("combo_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Combo not found"})
+		return
+	}
+
+	if err := postgres.DB.Delete(&combo).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete combo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Combo Deleted Successfully"})
+}
+
+// findCombo loads a combo of a menu belonging to the restaurant
+func findCombo(db *gorm.DB, restaurantID, menuID, comboID string) (models_menu.Combo, error) {
+	var combo models_menu.Combo
+	err := db.Joins("JOIN menus ON menus.id = combos.menu_id").
+		Where("combos.id = ? AND combos.menu_id = ? AND menus.restaurant_id = ?", comboID, menuID, restaurantID).
+		First(&combo).Error
+	return combo, err
+}
+
+// preloadComboSlots loads the slots of combos in order with the items they offer
+func preloadComboSlots(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("combo_slots.position") }).
+		Preload("Slots.Items.MenuItem")
+}
+
+// checkComboCategory makes sure the category a combo is listed under belongs to its menu
+func checkComboCategory(db *gorm.DB, menuID uuid.UUID, categoryID *uuid.UUID) error {
+	if categoryID == nil {
+		return nil
+	}
+	var category models_menu.MenuCategory
+	if err := db.First(&category, "id = ? AND menu_id = ?", *categoryID, menuID).Error; err != nil {
+		return fmt.Errorf("category not found in this menu")
+	}
+	return nil
+}
+
+// buildComboSlots checks that every slot item is a menu item of the restaurant
+// with a valid option, and returns the slots ready to be created
+func buildComboSlots(db *gorm.DB, restaurantID string, input []models_menu.AddComboSlotData) ([]models_menu.ComboSlot, error) {
+	var slots []models_menu.ComboSlot
+	for position, slotData := range input {
+		slot := models_menu.ComboSlot{Name: slotData.Name, Position: position}
+
+		defaults := 0
+		seen := make(map[uuid.UUID]bool)
+		for _, itemData := range slotData.Items {
+			if seen[itemData.MenuItemID] {
+				return nil, fmt.Errorf("item %s appears more than once in %s", itemData.MenuItemID, slotData.Name)
+			}
+			seen[itemData.MenuItemID] = true
+
+			item, err := findRestaurantItem(db, restaurantID, itemData.MenuItemID.String())
+			if err != nil {
+				return nil, fmt.Errorf("item %s not found", itemData.MenuItemID)
+			}
+			if itemData.ItemOptionID != nil {
+				var option models_menu.MenuItemOption
+				if err := db.First(&option, "id = ? AND menu_item_id = ?", *itemData.ItemOptionID, item.ID).Error; err != nil {
+					return nil, fmt.Errorf("option %s not found for %s", *itemData.ItemOptionID, item.Name)
+				}
+			}
+			if itemData.IsDefault {
+				defaults++
+			}
+
+			slot.Items = append(slot.Items, models_menu.ComboSlotItem{
+				MenuItemID:   item.ID,
+				ItemOptionID: itemData.ItemOptionID,
+				Upcharge:     itemData.Upcharge,
+				IsDefault:    itemData.IsDefault,
+			})
+		}
+		if defaults > 1 {
+			return nil, fmt.Errorf("%s can only have one default item", slotData.Name)
+		}
+
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
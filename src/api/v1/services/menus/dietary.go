@@ -0,0 +1,77 @@
+package services_menu
+
+import (
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// FilterMenuItems keeps the items matching the filter, and the combos whose every slot still has an item.
+// Categories left empty are kept so the menu layout does not change.
+func FilterMenuItems(menus []models_menu.Menu, filter utils.MenuItemFilter) []models_menu.Menu {
+	if filter.IsEmpty() {
+		return menus
+	}
+
+	filtered := make([]models_menu.Menu, 0, len(menus))
+	for _, menu := range menus {
+		categories := make([]models_menu.MenuCategory, 0, len(menu.Categories))
+		for _, category := range menu.Categories {
+			items := []models_menu.MenuItem{}
+			for _, item := range category.MenuItems {
+				if filter.Matches(item) {
+					items = append(items, item)
+				}
+			}
+			category.MenuItems = items
+			categories = append(categories, category)
+		}
+		menu.Categories = categories
+
+		combos := []models_menu.Combo{}
+		for _, combo := range menu.Combos {
+			slots := []models_menu.ComboSlot{}
+			for _, slot := range combo.Slots {
+				items := []models_menu.ComboSlotItem{}
+				for _, item := range slot.Items {
+					if item.MenuItem != nil && filter.Matches(*item.MenuItem) {
+						items = append(items, item)
+					}
+				}
+				if len(items) == 0 {
+					break
+				}
+				slot.Items = items
+				slots = append(slots, slot)
+			}
+			if len(slots) == len(combo.Slots) {
+				combo.Slots = slots
+				combos = append(combos, combo)
+			}
+		}
+		menu.Combos = combos
+
+		filtered = append(filtered, menu)
+	}
+	return filtered
+}
+
+// restaurantPureVeg reports whether a restaurant only serves vegetarian items
+func restaurantPureVeg(db *gorm.DB, restaurantID string) (bool, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.Select("id", "pure_veg").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return false, err
+	}
+	return restaurant.PureVeg, nil
+}
+
+// pureVegViolations returns the non-veg items of the menus when the restaurant is pure veg
+func pureVegViolations(db *gorm.DB, restaurantID string, menus []models_menu.Menu) ([]string, error) {
+	pureVeg, err := restaurantPureVeg(db, restaurantID)
+	if err != nil || !pureVeg {
+		return nil, err
+	}
+	return utils.NonVegItems(menus), nil
+}
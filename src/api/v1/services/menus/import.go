@@ -0,0 +1,431 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	utils "dine-server/src/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Largest number of rows accepted in one import
+const maxMenuImportRows = 5000
+
+// Option name used for rows that give a price without an option
+const defaultOptionName = "Regular"
+
+// ImportMenu imports categories, items and options into a menu from a CSV, XLSX or JSON file
+// @Summary Import a menu
+// @Description Import categories, items and options from a CSV, XLSX or JSON file, sent as the "file" form field or as the request body.
+// @Description Categories, items and options are matched by name and updated, the others are created. Nothing is written if any row is invalid.
+// @Description CSV and XLSX columns: category, category_description, item, description, image_url, is_vegetarian, is_available, option, price.
+// @Tags Menu
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param file formData file false "Menu file"
+// @Param format query string false "File format (csv, xlsx or json), taken from the file name or content type when empty"
+// @Param dry_run query bool false "Only validate the file and report what would change"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/import [post]
+func ImportMenu(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	menu, err := loadMenuForFile(postgres.DB, restaurantID, c.Param("menu_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	// The file comes either as a multipart upload or as the raw body
+	var reader io.Reader = c.Request.Body
+	fileName, contentType := "", c.ContentType()
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+		fileName, contentType = header.Filename, header.Header.Get("Content-Type")
+	}
+
+	format, err := utils.MenuFileFormat(c.Query("format"), fileName, contentType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := utils.ParseMenuFile(format, reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File has no rows"})
+		return
+	}
+	if len(rows) > maxMenuImportRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File has more than %d rows", maxMenuImportRows)})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	pureVeg, err := restaurantPureVeg(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant"})
+		return
+	}
+
+	if rowErrors := validateMenuImportRows(rows, pureVeg); len(rowErrors) > 0 {
+		if dryRun {
+			c.JSON(http.StatusOK, gin.H{"message": "Menu Import Validated", "valid": false, "rows": len(rows), "errors": rowErrors})
+		} else {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Menu file has invalid rows", "errors": rowErrors})
+		}
+		return
+	}
+
+	if dryRun {
+		summary, err := applyMenuImport(postgres.DB, menu, rows, pureVeg, true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Menu Import Validated", "valid": true, "rows": len(rows), "errors": []models_menu.MenuImportError{}, "summary": summary})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	summary, err := applyMenuImport(tx, menu, rows, pureVeg, false)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Imported Successfully", "rows": len(rows), "summary": summary})
+}
+
+// ExportMenu exports the categories, items and options of a menu
+// @Summary Export a menu
+// @Description Export the categories, items and options of a menu as CSV, XLSX or JSON, in the layout accepted by the import
+// @Tags Menu
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param format query string false "File format (csv, xlsx or json), defaults to json"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/export [get]
+func ExportMenu(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	format := c.DefaultQuery("format", utils.MenuFormatJSON)
+	format, err := utils.MenuFileFormat(format, "", "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	menu, err := loadMenuForFile(postgres.DB, restaurantID, c.Param("menu_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	body, err := utils.MenuFileBuffer(format, menuFile(menu))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export menu"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="menu-%s.%s"`, menu.ID, format))
+	c.Data(http.StatusOK, utils.MenuFileContentType(format), body)
+}
+
+// loadMenuForFile loads a menu of the restaurant with its categories, items and options in order
+func loadMenuForFile(db *gorm.DB, restaurantID, menuID string) (models_menu.Menu, error) {
+	var menu models_menu.Menu
+	err := db.
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("menu_categories.position, menu_categories.created_at") }).
+		Preload("Categories.MenuItems", func(db *gorm.DB) *gorm.DB { return db.Order("menu_items.position, menu_items.created_at") }).
+		Preload("Categories.MenuItems.ItemOptions", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_options.position, menu_item_options.price") }).
+		First(&menu, "id = ? AND restaurant_id = ?", menuID, restaurantID).Error
+	return menu, err
+}
+
+// menuFile converts a menu to the layout used by import and export
+func menuFile(menu models_menu.Menu) models_menu.MenuFile {
+	file := models_menu.MenuFile{Menu: menu.Name, Categories: []models_menu.MenuFileCategory{}}
+	for _, category := range menu.Categories {
+		fileCategory := models_menu.MenuFileCategory{Name: category.Name, Items: []models_menu.MenuFileItem{}}
+		if category.Description != nil {
+			fileCategory.Description = *category.Description
+		}
+
+		for _, item := range category.MenuItems {
+			isVegetarian, isAvailable := item.IsVegetarian, item.IsAvailable
+			fileItem := models_menu.MenuFileItem{
+				Name:         item.Name,
+				IsVegetarian: &isVegetarian,
+				IsAvailable:  &isAvailable,
+				Options:      []models_menu.MenuFileOption{},
+			}
+			if item.Description != nil {
+				fileItem.Description = *item.Description
+			}
+			if item.ImageURL != nil {
+				fileItem.ImageURL = *item.ImageURL
+			}
+			for _, option := range item.ItemOptions {
+				fileItem.Options = append(fileItem.Options, models_menu.MenuFileOption{Name: option.Name, Price: option.Price})
+			}
+			fileCategory.Items = append(fileCategory.Items, fileItem)
+		}
+
+		file.Categories = append(file.Categories, fileCategory)
+	}
+	return file
+}
+
+// importKey normalises names so that upserts ignore case and surrounding spaces
+func importKey(parts ...string) string {
+	for i := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+	}
+	return strings.Join(parts, "|")
+}
+
+// validateMenuImportRows checks every row and returns all the problems found.
+// Pure veg restaurants cannot import non-veg items.
+func validateMenuImportRows(rows []models_menu.MenuImportRow, pureVeg bool) []models_menu.MenuImportError {
+	rowErrors := []models_menu.MenuImportError{}
+	seen := make(map[string]int)
+
+	for _, row := range rows {
+		rowErrors = append(rowErrors, row.Errors...)
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, models_menu.MenuImportError{Row: row.Row, Field: field, Error: message})
+		}
+
+		if length := len(strings.TrimSpace(row.Category)); length < 2 || length > 100 {
+			fail("category", "must be between 2 and 100 characters")
+		}
+		if length := len(strings.TrimSpace(row.Item)); length < 2 || length > 100 {
+			fail("item", "must be between 2 and 100 characters")
+		}
+		if len(row.ImageURL) > 255 {
+			fail("image_url", "must be at most 255 characters")
+		}
+		if pureVeg && row.IsVegetarian != nil && !*row.IsVegetarian {
+			fail("is_vegetarian", "a pure veg restaurant cannot serve non-veg items")
+		}
+
+		// A row without option nor price only describes the item
+		if row.Option == "" && row.Price == 0 {
+			continue
+		}
+		option := row.Option
+		if option == "" {
+			option = defaultOptionName
+		}
+		if len(option) > 50 {
+			fail("option", "must be at most 50 characters")
+		}
+		if row.Price <= 0 {
+			fail("price", "must be greater than 0")
+		}
+
+		key := importKey(row.Category, row.Item, option)
+		if first, ok := seen[key]; ok {
+			fail("option", fmt.Sprintf("%s of %s is already on row %d", option, row.Item, first))
+			continue
+		}
+		seen[key] = row.Row
+	}
+
+	return rowErrors
+}
+
+// applyMenuImport upserts the rows into the menu by name. A dry run only counts the changes.
+// New items of pure veg restaurants are vegetarian when the file does not say.
+func applyMenuImport(tx *gorm.DB, menu models_menu.Menu, rows []models_menu.MenuImportRow, pureVeg, dryRun bool) (models_menu.MenuImportSummary, error) {
+	var summary models_menu.MenuImportSummary
+
+	categories := make(map[string]*models_menu.MenuCategory)
+	items := make(map[string]*models_menu.MenuItem)
+	options := make(map[string]*models_menu.MenuItemOption)
+	// New records are shown after the existing ones of their parent, in file order
+	positions := make(map[uuid.UUID]int)
+	place := func(parentID uuid.UUID, position int) {
+		if position >= positions[parentID] {
+			positions[parentID] = position + 1
+		}
+	}
+	for i := range menu.Categories {
+		category := &menu.Categories[i]
+		categories[importKey(category.Name)] = category
+		place(menu.ID, category.Position)
+		for j := range category.MenuItems {
+			item := &category.MenuItems[j]
+			items[importKey(category.ID.String(), item.Name)] = item
+			place(category.ID, item.Position)
+			for k := range item.ItemOptions {
+				option := &item.ItemOptions[k]
+				options[importKey(item.ID.String(), option.Name)] = option
+				place(item.ID, option.Position)
+			}
+		}
+	}
+
+	// Records created or updated by this import are only counted once
+	touched := make(map[uuid.UUID]bool)
+
+	update := func(model interface{}, id uuid.UUID, changes map[string]interface{}) error {
+		if len(changes) == 0 || dryRun {
+			return nil
+		}
+		return tx.Model(model).Where("id = ?", id).Updates(changes).Error
+	}
+
+	for _, row := range rows {
+		// Category
+		category, ok := categories[importKey(row.Category)]
+		if !ok {
+			category = &models_menu.MenuCategory{ID: uuid.Must(uuid.NewV4()), MenuID: menu.ID, Name: strings.TrimSpace(row.Category), Position: positions[menu.ID]}
+			place(menu.ID, category.Position)
+			if row.CategoryDescription != "" {
+				category.Description = &row.CategoryDescription
+			}
+			if !dryRun {
+				if err := tx.Create(category).Error; err != nil {
+					return summary, fmt.Errorf("failed to create category %s", category.Name)
+				}
+			}
+			categories[importKey(row.Category)] = category
+			touched[category.ID] = true
+			summary.CategoriesCreated++
+		} else if row.CategoryDescription != "" && (category.Description == nil || *category.Description != row.CategoryDescription) {
+			if err := update(&models_menu.MenuCategory{}, category.ID, map[string]interface{}{"description": row.CategoryDescription}); err != nil {
+				return summary, fmt.Errorf("failed to update category %s", category.Name)
+			}
+			description := row.CategoryDescription
+			category.Description = &description
+			if !touched[category.ID] {
+				touched[category.ID] = true
+				summary.CategoriesUpdated++
+			}
+		}
+
+		// Item
+		itemKey := importKey(category.ID.String(), row.Item)
+		item, ok := items[itemKey]
+		if !ok {
+			item = &models_menu.MenuItem{
+				ID:           uuid.Must(uuid.NewV4()),
+				MenuID:       menu.ID,
+				CategoryID:   category.ID,
+				Name:         strings.TrimSpace(row.Item),
+				IsVegetarian: pureVeg,
+				IsAvailable:  true,
+				StationID:    category.StationID,
+				Position:     positions[category.ID],
+			}
+			place(category.ID, item.Position)
+			if row.Description != "" {
+				item.Description = &row.Description
+			}
+			if row.ImageURL != "" {
+				item.ImageURL = &row.ImageURL
+			}
+			if row.IsVegetarian != nil {
+				item.IsVegetarian = *row.IsVegetarian
+			}
+			if row.IsAvailable != nil {
+				item.IsAvailable = *row.IsAvailable
+			}
+			if !dryRun {
+				if err := tx.Create(item).Error; err != nil {
+					return summary, fmt.Errorf("failed to create item %s", item.Name)
+				}
+			}
+			items[itemKey] = item
+			touched[item.ID] = true
+			summary.ItemsCreated++
+		} else {
+			changes := make(map[string]interface{})
+			if row.Description != "" && (item.Description == nil || *item.Description != row.Description) {
+				description := row.Description
+				item.Description = &description
+				changes["description"] = description
+			}
+			if row.ImageURL != "" && (item.ImageURL == nil || *item.ImageURL != row.ImageURL) {
+				imageURL := row.ImageURL
+				item.ImageURL = &imageURL
+				changes["image_url"] = imageURL
+			}
+			if row.IsVegetarian != nil && item.IsVegetarian != *row.IsVegetarian {
+				item.IsVegetarian = *row.IsVegetarian
+				changes["is_vegetarian"] = item.IsVegetarian
+			}
+			if row.IsAvailable != nil && item.IsAvailable != *row.IsAvailable {
+				item.IsAvailable = *row.IsAvailable
+				changes["is_available"] = item.IsAvailable
+			}
+			if err := update(&models_menu.MenuItem{}, item.ID, changes); err != nil {
+				return summary, fmt.Errorf("failed to update item %s", item.Name)
+			}
+			if len(changes) > 0 && !touched[item.ID] {
+				touched[item.ID] = true
+				summary.ItemsUpdated++
+			}
+		}
+
+		// Option
+		if row.Option == "" && row.Price == 0 {
+			continue
+		}
+		optionName := strings.TrimSpace(row.Option)
+		if optionName == "" {
+			optionName = defaultOptionName
+		}
+
+		optionKey := importKey(item.ID.String(), optionName)
+		option, ok := options[optionKey]
+		if !ok {
+			option = &models_menu.MenuItemOption{ID: uuid.Must(uuid.NewV4()), MenuItemID: item.ID, Name: optionName, Price: row.Price, Position: positions[item.ID]}
+			place(item.ID, option.Position)
+			if !dryRun {
+				if err := tx.Create(option).Error; err != nil {
+					return summary, fmt.Errorf("failed to create option %s of %s", optionName, item.Name)
+				}
+			}
+			options[optionKey] = option
+			summary.OptionsCreated++
+		} else if option.Price != row.Price {
+			if err := update(&models_menu.MenuItemOption{}, option.ID, map[string]interface{}{"price": row.Price}); err != nil {
+				return summary, fmt.Errorf("failed to update option %s of %s", optionName, item.Name)
+			}
+			option.Price = row.Price
+			summary.OptionsUpdated++
+		}
+	}
+
+	return summary, nil
+}
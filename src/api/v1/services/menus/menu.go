@@ -1,6 +1,7 @@
 package services_menu
 
 import (
+	services_images "dine-server/src/api/v1/services/images"
 	postgres "dine-server/src/config/database"
 	models_menu "dine-server/src/models/menu"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
 	// uuid "github.com/jackc/pgx/pgtype/ext/gofrs-uuid"
 )
 
@@ -31,15 +33,6 @@ func CreateMenu(c *gin.Context) {
 
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create menu for this restaurant"})
-		return
-	}
-
 	var existingMenu models_menu.Menu
 	if err := postgres.DB.Where("name = ? AND restaurant_id = ?", menu.Name, restaurantID).First(&existingMenu).Error; err == nil {
 		// If a Menu with the same name and restaurant_id exists
@@ -63,50 +56,127 @@ func CreateMenu(c *gin.Context) {
 
 // GetMenus retrieves all menus
 // @Summary Retrieve all menus
-// @Description Retrieve all menus
+// @Description Retrieve all menus. Public callers only get the published menus served now, or at the given time.
 // @Tags Menu
 // @Produce json
 // @Param restaurant_id path string true "Restaurant ID"
+// @Param at query string false "Evaluate schedules at this time (RFC 3339, YYYY-MM-DDTHH:MM or HH:MM)"
 // @Router /api/v1/{restaurant_id}/menus [get]
 func GetMenus(c *gin.Context) {
 	restaurant_Id := c.Param("restaurant_id")
 
-	var menus []models_menu.Menu
-	if err := postgres.DB.Where("restaurant_id = ?", restaurant_Id).Find(&menus).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+	at, err := menuTime(c, restaurant_Id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Staff see every menu they edit, guests only the published menus being served
+	var menus []models_menu.Menu
+	if canManageMenus(c, restaurant_Id) {
+		if err := postgres.DB.Preload("Schedules").Where("restaurant_id = ?", restaurant_Id).Find(&menus).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+			return
+		}
+		markServedMenus(menus, at)
+	} else {
+		served, err := ServedMenus(postgres.DB, restaurant_Id, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+			return
+		}
+		menus = []models_menu.Menu{}
+		for _, menu := range served {
+			menu.Categories = nil
+			menu.Combos = nil
+			menus = append(menus, menu)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Menus Found Successfully", "menus": menus})
 }
 
 // GetMenuByID retrieves a specific menu by ID
 // @Summary Retrieve a specific menu by ID
-// @Description Retrieve a specific menu by ID
+// @Description Retrieve a specific menu by ID. Public callers get the published menu while it is served, without inactive categories and unavailable items.
 // @Tags Menu
 // @Produce json
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param menu_id path string true "Menu ID"
+// @Param at query string false "Evaluate schedules at this time (RFC 3339, YYYY-MM-DDTHH:MM or HH:MM)"
+// @Param diet query string false "Only items suitable for these diets, comma separated (vegetarian, vegan, jain, gluten_free, halal)"
+// @Param exclude_allergens query string false "Leave out items containing these allergens, comma separated (e.g. nuts,dairy)"
+// @Param lang query string false "Language of the names and descriptions, e.g. fr"
+// @Param Accept-Language header string false "Languages the guest reads, used when lang is not given"
 // @Router /api/v1/{restaurant_id}/menus/{menu_id} [get]
 func GetMenuByID(c *gin.Context) {
 	menuID := c.Param("menu_id")
 	restaurantID := c.Param("restaurant_id")
 
+	at, err := menuTime(c, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := utils.ParseMenuItemFilter(c.QueryArray("diet"), c.QueryArray("exclude_allergens"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Staff edit the default language, they only get a translation when they ask for it with ?lang=
+	manager := canManageMenus(c, restaurantID)
+	language, translations, err := MenuLanguage(c, postgres.DB, restaurantID, !manager)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+
+	// Guests get the menu from the published version while it is served
+	if !manager {
+		served, err := ServedMenus(postgres.DB, restaurantID, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+			return
+		}
+		for _, menu := range TranslateMenus(FilterMenuItems(served, filter), translations) {
+			if menu.ID.String() == menuID {
+				c.JSON(http.StatusOK, gin.H{"message": "Menu retrieved successfully", "language": language, "menu": menu})
+				return
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu is not served at this time"})
+		return
+	}
+
 	// Fetch menu with related categories, items, and item options
 	var menu models_menu.Menu
 	if err := postgres.DB.
-		Preload("Categories.MenuItems").
-		Preload("Categories.MenuItems.ItemOptions"). // Preload ItemOptions for each MenuItem
+		Preload("Schedules").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("menu_categories.position, menu_categories.created_at") }).
+		Preload("Categories.Schedules").
+		Preload("Categories.MenuItems", func(db *gorm.DB) *gorm.DB { return db.Order("menu_items.position, menu_items.created_at") }).
+		Preload("Categories.MenuItems.ItemOptions", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_options.position, menu_item_options.price") }). // Preload ItemOptions for each MenuItem
+		Preload("Categories.MenuItems.ModifierGroups.Modifiers").
+		Preload("Combos", func(db *gorm.DB) *gorm.DB { return db.Order("combos.created_at") }).
+		Preload("Combos.Slots", func(db *gorm.DB) *gorm.DB { return db.Order("combo_slots.position") }).
+		Preload("Combos.Slots.Items.MenuItem").
 		Where("id = ? AND restaurant_id = ?", menuID, restaurantID).
 		First(&menu).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
 		return
 	}
 
+	menus := []models_menu.Menu{menu}
+	markServedMenus(menus, at)
+	menu = TranslateMenus(FilterMenuItems(menus, filter), translations)[0]
+
 	// Success response
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Menu retrieved successfully",
-		"menu":    menu,
+		"message":  "Menu retrieved successfully",
+		"language": language,
+		"menu":     menu,
 	})
 }
 
@@ -115,17 +185,6 @@ func UpdateMenu(c *gin.Context) {
 	id := c.Param("menu_id")
 	var menu models_menu.Menu
 
-	restaurantID := c.Param("restaurant_id")
-
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update menu for this restaurant"})
-		return
-	}
-
 	if err := postgres.DB.First(&menu, "id = ?", id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
 		return
@@ -150,19 +209,11 @@ func DeleteMenu(c *gin.Context) {
 
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create menu for this restaurant"})
-		return
-	}
-
 	if err := postgres.DB.Delete(&models_menu.Menu{}, "id = ?", id).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete menu"})
 		return
 	}
+	services_images.CleanupImagesLater(restaurantID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Menu deleted successfully"})
 }
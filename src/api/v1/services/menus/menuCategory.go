@@ -1,9 +1,9 @@
 package services_menu
 
 import (
+	services_images "dine-server/src/api/v1/services/images"
 	postgres "dine-server/src/config/database"
 	models_menu "dine-server/src/models/menu"
-	utils "dine-server/src/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,17 +21,6 @@ import (
 // @Param restaurant_id path string true "Restaurant ID"
 // @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories [post]
 func CreateMenuCategory(c *gin.Context) {
-	restaurantID := c.Param("restaurant_id")
-
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create Category for this menu"})
-		return
-	}
-
 	menuID := c.Param("menu_id")
 	var category models_menu.MenuCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
@@ -53,6 +42,10 @@ func CreateMenuCategory(c *gin.Context) {
 	}
 
 	category.MenuID = menuUUID
+	if category.Position, err = nextPosition(postgres.DB, &models_menu.MenuCategory{}, "menu_id", menuUUID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		return
+	}
 	if err := postgres.DB.Create(&category).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
 		return
@@ -63,10 +56,10 @@ func CreateMenuCategory(c *gin.Context) {
 
 // GetMenuCategories retrieves all categories for a specific menu
 func GetMenuCategories(c *gin.Context) {
-	menuID := c.Query("menu_id")
+	menuID := c.Param("menu_id")
 
 	var categories []models_menu.MenuCategory
-	if err := postgres.DB.Where("menu_id = ?", menuID).Find(&categories).Error; err != nil {
+	if err := postgres.DB.Where("menu_id = ?", menuID).Order("position, created_at").Find(&categories).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
 		return
 	}
@@ -91,14 +84,6 @@ func GetMenuCategoryByID(c *gin.Context) {
 func UpdateMenuCategory(c *gin.Context) {
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update category for this menu"})
-		return
-	}
 	categoryID := c.Param("category_id")
 	var category models_menu.MenuCategory
 
@@ -106,16 +91,21 @@ func UpdateMenuCategory(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	}
+	imageURL, thumbnailURL := category.ImageURL, category.ThumbnailURL
 
 	if err := c.ShouldBindJSON(&category); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	category.ThumbnailURL = keptThumbnail(imageURL, thumbnailURL, category.ImageURL, category.ThumbnailURL)
 
 	if err := postgres.DB.Save(&category).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
 		return
 	}
+	if !sameURL(imageURL, category.ImageURL) {
+		services_images.CleanupImagesLater(restaurantID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Category Updated Successfully", "category": category})
 }
@@ -124,20 +114,12 @@ func UpdateMenuCategory(c *gin.Context) {
 func DeleteMenuCategory(c *gin.Context) {
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete category for this menu"})
-		return
-	}
-
 	categoryID := c.Param("category_id")
 	if err := postgres.DB.Delete(&models_menu.MenuCategory{}, "id = ?", categoryID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
 		return
 	}
+	services_images.CleanupImagesLater(restaurantID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
 }
@@ -1,6 +1,7 @@
 package services_menu
 
 import (
+	services_images "dine-server/src/api/v1/services/images"
 	postgres "dine-server/src/config/database"
 	models_menu "dine-server/src/models/menu"
 	utils "dine-server/src/utils"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
 )
 
 // CreateMenuItem handles the creation of a new menu item
@@ -25,15 +27,6 @@ func CreateMenuItem(c *gin.Context) {
 	// Retrieve user info from context
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create item for this category"})
-		return
-	}
-
 	// Extract parameters
 	categoryID := c.Param("category_id")
 	menuID := c.Param("menu_id")
@@ -45,6 +38,12 @@ func CreateMenuItem(c *gin.Context) {
 		return
 	}
 
+	pureVeg, err := restaurantPureVeg(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
 	// Check if item already exists
 	var existingItem models_menu.MenuItem
 	if err := postgres.DB.Where("name = ? AND category_id = ?", addMenuItemData.Name, categoryID).First(&existingItem).Error; err == nil {
@@ -82,7 +81,32 @@ func CreateMenuItem(c *gin.Context) {
 		Description:  &addMenuItemData.Description,
 		ImageURL:     &addMenuItemData.ImageURL,
 		IsVegetarian: addMenuItemData.IsVegetarian,
-		IsAvailable:  addMenuItemData.IsAvailable,
+		IsAvailable:  true,
+		Diets:        addMenuItemData.Diets,
+		Allergens:    addMenuItemData.Allergens,
+		SpiceLevel:   addMenuItemData.SpiceLevel,
+		Nutrition:    addMenuItemData.Nutrition,
+		StationID:    addMenuItemData.StationID,
+	}
+	if addMenuItemData.IsAvailable != nil {
+		item.IsAvailable = *addMenuItemData.IsAvailable
+	}
+
+	if err := utils.NormalizeMenuItemDietary(&item); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if pureVeg && !item.IsVegetarian {
+		tx.Rollback()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot serve non-veg items"})
+		return
+	}
+
+	if item.Position, err = nextPosition(tx, &models_menu.MenuItem{}, "category_id", categoryUUID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu item"})
+		return
 	}
 
 	if err := tx.Create(&item).Error; err != nil {
@@ -93,13 +117,14 @@ func CreateMenuItem(c *gin.Context) {
 
 	// Create MenuItemOptions if provided
 	var createdOptions []models_menu.MenuItemOption
-	for _, option := range addMenuItemData.ItemOptions {
+	for position, option := range addMenuItemData.ItemOptions {
 		optionUUID := uuid.Must(uuid.NewV4())
 		itemOption := models_menu.MenuItemOption{
 			ID:         optionUUID,
 			MenuItemID: newItemUUID,
 			Name:       option.Name,
 			Price:      option.Price,
+			Position:   position,
 		}
 		if err := tx.Create(&itemOption).Error; err != nil {
 			tx.Rollback()
@@ -125,7 +150,7 @@ func CreateMenuItem(c *gin.Context) {
 
 // CreateMultipleMenuItems handles the creation of multiple menu items
 // @Summary Create multiple menu items
-// @Description Create multiple menu items
+// @Description Create multiple menu items in one transaction. Nothing is created if any item already exists.
 // @Tags Menu Category Item
 // @Accept json
 // @Produce json
@@ -133,36 +158,24 @@ func CreateMenuItem(c *gin.Context) {
 // @Param category_id path string true "Category ID"
 // @Param menu_id path string true "Menu ID"
 // @Param restaurant_id path string true "Restaurant ID"
-// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items [post]
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/bulk [post]
 func CreateMultipleMenuItems(c *gin.Context) {
 	// Retrieve user info from context
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create item for this category"})
-		return
-	}
-
 	// Extract parameters
 	categoryID := c.Param("category_id")
 	menuID := c.Param("menu_id")
 
-	// Parse UUIDs
-	categoryUUID, err := uuid.FromString(categoryID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID format"})
+	// The category must belong to a menu of this restaurant
+	var category models_menu.MenuCategory
+	if err := postgres.DB.Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+		Where("menu_categories.id = ? AND menu_categories.menu_id = ? AND menus.restaurant_id = ?", categoryID, menuID, restaurantID).
+		First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	}
 
-	menuUUID, err := uuid.FromString(menuID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu ID format"})
-		return
-	}
 	// Parse and validate request body
 	var addMenuItemData []models_menu.AddMenuItemData
 	if err := c.ShouldBindJSON(&addMenuItemData); err != nil {
@@ -170,6 +183,12 @@ func CreateMultipleMenuItems(c *gin.Context) {
 		return
 	}
 
+	pureVeg, err := restaurantPureVeg(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
 	// Start a transaction
 	tx := postgres.DB.Begin()
 	if tx.Error != nil {
@@ -177,27 +196,57 @@ func CreateMultipleMenuItems(c *gin.Context) {
 		return
 	}
 
-	// Create new MenuItem
+	position, err := nextPosition(tx, &models_menu.MenuItem{}, "category_id", category.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu items"})
+		return
+	}
+
 	var createdItems []models_menu.MenuItem
+	names := make(map[string]bool)
 	for _, itemData := range addMenuItemData {
-		// Check if item already exists
-		tx := postgres.DB.Begin()
-		if tx.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		// Check if item already exists, in the category or earlier in the request
+		var existingItem models_menu.MenuItem
+		if err := tx.Where("name = ? AND category_id = ?", itemData.Name, category.ID).First(&existingItem).Error; err == nil || names[itemData.Name] {
+			tx.Rollback()
+			c.JSON(http.StatusConflict, gin.H{"error": "An item named " + itemData.Name + " already exists in this category"})
 			return
 		}
+		names[itemData.Name] = true
 
 		// Create new MenuItem
 		newItemUUID := uuid.Must(uuid.NewV4())
 		item := models_menu.MenuItem{
 			ID:           newItemUUID,
-			MenuID:       menuUUID,
-			CategoryID:   categoryUUID,
+			MenuID:       category.MenuID,
+			CategoryID:   category.ID,
 			Name:         itemData.Name,
 			Description:  &itemData.Description,
 			ImageURL:     &itemData.ImageURL,
 			IsVegetarian: itemData.IsVegetarian,
-			IsAvailable:  itemData.IsAvailable,
+			IsAvailable:  true,
+			Diets:        itemData.Diets,
+			Allergens:    itemData.Allergens,
+			SpiceLevel:   itemData.SpiceLevel,
+			Nutrition:    itemData.Nutrition,
+			StationID:    itemData.StationID,
+			Position:     position,
+		}
+		if itemData.IsAvailable != nil {
+			item.IsAvailable = *itemData.IsAvailable
+		}
+		position++
+
+		if err := utils.NormalizeMenuItemDietary(&item); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": item.Name + ": " + err.Error()})
+			return
+		}
+		if pureVeg && !item.IsVegetarian {
+			tx.Rollback()
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot serve non-veg items like " + item.Name})
+			return
 		}
 
 		if err := tx.Create(&item).Error; err != nil {
@@ -207,23 +256,24 @@ func CreateMultipleMenuItems(c *gin.Context) {
 		}
 
 		// Create MenuItemOptions if provided
-		var createdOptions []models_menu.MenuItemOption
-		for _, option := range itemData.ItemOptions {
+		for optionPosition, option := range itemData.ItemOptions {
 			optionUUID := uuid.Must(uuid.NewV4())
 			itemOption := models_menu.MenuItemOption{
 				ID:         optionUUID,
 				MenuItemID: newItemUUID,
 				Name:       option.Name,
 				Price:      option.Price,
+				Position:   optionPosition,
 			}
 			if err := tx.Create(&itemOption).Error; err != nil {
 				tx.Rollback()
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create item options"})
 				return
 			}
-			createdOptions = append(createdOptions, itemOption)
+			item.ItemOptions = append(item.ItemOptions, itemOption)
 		}
 
+		createdItems = append(createdItems, item)
 	}
 
 	// Commit the transaction
@@ -247,11 +297,14 @@ func CreateMultipleMenuItems(c *gin.Context) {
 // @Param category_id path string true "Category ID"
 // @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items [get]
 func GetMenuItems(c *gin.Context) {
-	categoryID := c.Query("category_id")
+	categoryID := c.Param("category_id")
 
 	var items []models_menu.MenuItem
 
-	if err := postgres.DB.Where(" menu_category_id = ?", categoryID).Find(&items).Error; err != nil {
+	if err := postgres.DB.Preload("ItemOptions", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_options.position, menu_item_options.price") }).
+		Where("category_id = ?", categoryID).
+		Order("position, created_at").
+		Find(&items).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
 		return
 	}
@@ -277,15 +330,6 @@ func GetMenuItemByID(c *gin.Context) {
 func UpdateMenuItem(c *gin.Context) {
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update item for this category"})
-		return
-	}
-
 	itemID := c.Param("item_id")
 	var item models_menu.MenuItem
 
@@ -293,16 +337,33 @@ func UpdateMenuItem(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
 		return
 	}
+	imageURL, thumbnailURL := item.ImageURL, item.ThumbnailURL
 
 	if err := c.ShouldBindJSON(&item); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	item.ThumbnailURL = keptThumbnail(imageURL, thumbnailURL, item.ImageURL, item.ThumbnailURL)
+
+	if err := utils.NormalizeMenuItemDietary(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if pureVeg, err := restaurantPureVeg(postgres.DB, restaurantID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	} else if pureVeg && !item.IsVegetarian {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot serve non-veg items"})
+		return
+	}
 
 	if err := postgres.DB.Save(&item).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
 		return
 	}
+	if !sameURL(imageURL, item.ImageURL) {
+		services_images.CleanupImagesLater(restaurantID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Item Updated Successfully", "item": item})
 }
@@ -311,20 +372,27 @@ func UpdateMenuItem(c *gin.Context) {
 func DeleteMenuItem(c *gin.Context) {
 	restaurantID := c.Param("restaurant_id")
 
-	if isAdmin, err := utils.IsAuthorised(c, restaurantID); err != nil {
-		// Unauthorized or Forbidden response
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	} else if !isAdmin {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to create menu for this restaurant"})
-		return
-	}
-
 	itemID := c.Param("item_id")
 	if err := postgres.DB.Delete(&models_menu.MenuItem{}, "id = ?", itemID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete item"})
 		return
 	}
+	services_images.CleanupImagesLater(restaurantID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Item deleted successfully"})
 }
+
+// keptThumbnail drops the thumbnail of an uploaded image when the image URL is changed without giving a new thumbnail
+func keptThumbnail(oldImage, oldThumbnail, newImage, newThumbnail *string) *string {
+	if !sameURL(oldImage, newImage) && sameURL(oldThumbnail, newThumbnail) {
+		return nil
+	}
+	return newThumbnail
+}
+
+func sameURL(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
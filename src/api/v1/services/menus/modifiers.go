@@ -0,0 +1,337 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateModifierGroup adds a modifier group with its modifiers to a menu item
+// @Summary Create a modifier group
+// @Description Add a group of choices to a menu item, e.g. "Toppings" (0 to 5) or "Size" (exactly 1)
+// @Tags Menu Item Modifiers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group body models_menu.AddModifierGroupData true "Modifier group data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups [post]
+func CreateModifierGroup(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.AddModifierGroupData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := findRestaurantItem(postgres.DB, restaurantID, c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	group := models_menu.ModifierGroup{
+		MenuItemID: item.ID,
+		Name:       input.Name,
+		MinSelect:  input.MinSelect,
+		MaxSelect:  1,
+	}
+	if input.MaxSelect != nil {
+		group.MaxSelect = *input.MaxSelect
+	}
+	if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_select cannot be greater than max_select"})
+		return
+	}
+
+	for _, modifier := range input.Modifiers {
+		isAvailable := true
+		if modifier.IsAvailable != nil {
+			isAvailable = *modifier.IsAvailable
+		}
+		group.Modifiers = append(group.Modifiers, models_menu.Modifier{
+			Name:        modifier.Name,
+			Price:       modifier.Price,
+			IsAvailable: isAvailable,
+		})
+	}
+
+	// Creates the group and its modifiers together
+	if err := postgres.DB.Create(&group).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Modifier Group Created Successfully", "modifier_group": group})
+}
+
+// GetModifierGroups retrieves the modifier groups of a menu item
+// @Summary Get modifier groups
+// @Description Get the modifier groups of a menu item with their modifiers
+// @Tags Menu Item Modifiers
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups [get]
+func GetModifierGroups(c *gin.Context) {
+	item, err := findRestaurantItem(postgres.DB, c.Param("restaurant_id"), c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	var groups []models_menu.ModifierGroup
+	if err := postgres.DB.Preload("Modifiers").Where("menu_item_id = ?", item.ID).Order("created_at").Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch modifier groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier Groups Found Successfully", "modifier_groups": groups})
+}
+
+// UpdateModifierGroup updates the name and selection rules of a modifier group
+// @Summary Update a modifier group
+// @Description Update the name and selection rules of a modifier group
+// @Tags Menu Item Modifiers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group_id path string true "Modifier Group ID"
+// @Param group body models_menu.UpdateModifierGroupData true "Modifier group data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups/{group_id} [put]
+func UpdateModifierGroup(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.UpdateModifierGroupData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := findModifierGroup(postgres.DB, restaurantID, c.Param("item_id"), c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier group not found"})
+		return
+	}
+
+	if input.Name != "" {
+		group.Name = input.Name
+	}
+	if input.MinSelect != nil {
+		group.MinSelect = *input.MinSelect
+	}
+	if input.MaxSelect != nil {
+		group.MaxSelect = *input.MaxSelect
+	}
+	if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_select cannot be greater than max_select"})
+		return
+	}
+
+	if err := postgres.DB.Model(&group).Select("name", "min_select", "max_select").Updates(&group).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier Group Updated Successfully", "modifier_group": group})
+}
+
+// DeleteModifierGroup deletes a modifier group and its modifiers
+// @Summary Delete a modifier group
+// @Description Delete a modifier group and its modifiers
+// @Tags Menu Item Modifiers
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group_id path string true "Modifier Group ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups/{group_id} [delete]
+func DeleteModifierGroup(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	group, err := findModifierGroup(postgres.DB, restaurantID, c.Param("item_id"), c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier group not found"})
+		return
+	}
+
+	if err := postgres.DB.Delete(&group).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete modifier group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier group deleted successfully"})
+}
+
+// CreateModifier adds a modifier to a modifier group
+// @Summary Create a modifier
+// @Description Add a choice to a modifier group
+// @Tags Menu Item Modifiers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group_id path string true "Modifier Group ID"
+// @Param modifier body models_menu.AddModifierData true "Modifier data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups/{group_id}/modifiers [post]
+func CreateModifier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.AddModifierData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := findModifierGroup(postgres.DB, restaurantID, c.Param("item_id"), c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier group not found"})
+		return
+	}
+
+	modifier := models_menu.Modifier{
+		GroupID:     group.ID,
+		Name:        input.Name,
+		Price:       input.Price,
+		IsAvailable: true,
+	}
+	if input.IsAvailable != nil {
+		modifier.IsAvailable = *input.IsAvailable
+	}
+
+	if err := postgres.DB.Create(&modifier).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create modifier"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Modifier Created Successfully", "modifier": modifier})
+}
+
+// UpdateModifier updates a modifier
+// @Summary Update a modifier
+// @Description Update the name, price or availability of a modifier
+// @Tags Menu Item Modifiers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group_id path string true "Modifier Group ID"
+// @Param modifier_id path string true "Modifier ID"
+// @Param modifier body models_menu.UpdateModifierData true "Modifier data"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups/{group_id}/modifiers/{modifier_id} [put]
+func UpdateModifier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.UpdateModifierData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, err := findModifierGroup(postgres.DB, restaurantID, c.Param("item_id"), c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier group not found"})
+		return
+	}
+
+	var modifier models_menu.Modifier
+	if err := postgres.DB.First(&modifier, "id = ? AND group_id = ?", c.Param("modifier_id"), group.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier not found"})
+		return
+	}
+
+	if input.Name != "" {
+		modifier.Name = input.Name
+	}
+	if input.Price != nil {
+		modifier.Price = *input.Price
+	}
+	if input.IsAvailable != nil {
+		modifier.IsAvailable = *input.IsAvailable
+	}
+
+	if err := postgres.DB.Model(&modifier).Select("name", "price", "is_available").Updates(&modifier).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update modifier"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier Updated Successfully", "modifier": modifier})
+}
+
+// DeleteModifier deletes a modifier
+// @Summary Delete a modifier
+// @Description Delete a modifier from its group
+// @Tags Menu Item Modifiers
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param group_id path string true "Modifier Group ID"
+// @Param modifier_id path string true "Modifier ID"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/modifier-groups/{group_id}/modifiers/{modifier_id} [delete]
+func DeleteModifier(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	group, err := findModifierGroup(postgres.DB, restaurantID, c.Param("item_id"), c.Param("group_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier group not found"})
+		return
+	}
+
+	result := postgres.DB.Where("id = ? AND group_id = ?", c.Param("modifier_id"), group.ID).Delete(&models_menu.Modifier{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete modifier"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Modifier not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Modifier deleted successfully"})
+}
+
+// findRestaurantItem loads a menu item belonging to one of the restaurant's menus
+func findRestaurantItem(db *gorm.DB, restaurantID, itemID string) (models_menu.MenuItem, error) {
+	var item models_menu.MenuItem
+	err := db.Joins("JOIN menus ON menus.id = menu_items.menu_id").
+		Where("menu_items.id = ? AND menus.restaurant_id = ?", itemID, restaurantID).
+		First(&item).Error
+	return item, err
+}
+
+// findModifierGroup loads a modifier group of a menu item belonging to the restaurant
+func findModifierGroup(db *gorm.DB, restaurantID, itemID, groupID string) (models_menu.ModifierGroup, error) {
+	var group models_menu.ModifierGroup
+
+	item, err := findRestaurantItem(db, restaurantID, itemID)
+	if err != nil {
+		return group, err
+	}
+
+	err = db.First(&group, "id = ? AND menu_item_id = ?", groupID, item.ID).Error
+	return group, err
+}
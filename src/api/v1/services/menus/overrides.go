@@ -0,0 +1,190 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	utils "dine-server/src/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetMenuOverrides lists the overrides of an outlet
+// @Summary Get menu overrides
+// @Description List the prices and availability this outlet changed on the menus shared by its brand.
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/menu-overrides [get]
+func GetMenuOverrides(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	overrides, err := LoadMenuOverrides(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu overrides"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Overrides Found Successfully", "overrides": overrides})
+}
+
+// SetMenuOverride changes the availability of an item or the price of an option for this outlet
+// @Summary Set a menu override
+// @Description Give menu_item_id with is_available to change whether the outlet serves an item,
+// @Description or menu_item_id, menu_item_option_id and price to change what an option costs at the outlet. Setting an override again replaces it.
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param override body models_menu.SetMenuOverrideData true "Override data"
+// @Router /api/v1/{restaurant_id}/menu-overrides [put]
+func SetMenuOverride(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.SetMenuOverrideData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.MenuItemOptionID != nil && (input.Price == nil || input.IsAvailable != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Option overrides set a price only"})
+		return
+	}
+	if input.MenuItemOptionID == nil && (input.IsAvailable == nil || input.Price != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Item overrides set is_available only, give menu_item_option_id to change a price"})
+		return
+	}
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var item models_menu.MenuItem
+	if err := outletMenus(postgres.DB.Joins("JOIN menus ON menus.id = menu_items.menu_id"), restaurantID).
+		First(&item, "menu_items.id = ?", input.MenuItemID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if input.MenuItemOptionID != nil {
+		if err := postgres.DB.First(&models_menu.MenuItemOption{}, "id = ? AND menu_item_id = ?", input.MenuItemOptionID, item.ID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Option not found"})
+			return
+		}
+	}
+
+	var override models_menu.MenuOverride
+	query := postgres.DB.Where("restaurant_id = ? AND menu_item_id = ?", restaurantID, item.ID)
+	if input.MenuItemOptionID != nil {
+		query = query.Where("menu_item_option_id = ?", input.MenuItemOptionID)
+	} else {
+		query = query.Where("menu_item_option_id IS NULL")
+	}
+	err = query.First(&override).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu override"})
+		return
+	}
+
+	override.RestaurantID = restaurantUUID
+	override.MenuItemID = item.ID
+	override.MenuItemOptionID = input.MenuItemOptionID
+	override.Price = nil
+	override.IsAvailable = input.IsAvailable
+	if input.Price != nil {
+		price := utils.RoundAmount(*input.Price)
+		override.Price = &price
+	}
+
+	if err := postgres.DB.Save(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save menu override"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Override Saved Successfully", "override": override})
+}
+
+// DeleteMenuOverride removes an override, the outlet follows the brand menu again
+// @Summary Delete a menu override
+// @Tags Brands
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param override_id path string true "Override ID"
+// @Router /api/v1/{restaurant_id}/menu-overrides/{override_id} [delete]
+func DeleteMenuOverride(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Delete(&models_menu.MenuOverride{}, "id = ? AND restaurant_id = ?", c.Param("override_id"), restaurantID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete menu override"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu override not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu override deleted successfully"})
+}
+
+// LoadMenuOverrides returns the overrides of an outlet
+func LoadMenuOverrides(db *gorm.DB, restaurantID string) ([]models_menu.MenuOverride, error) {
+	var overrides []models_menu.MenuOverride
+	err := db.Where("restaurant_id = ?", restaurantID).Order("created_at").Find(&overrides).Error
+	return overrides, err
+}
+
+// ApplyMenuOverrides sets the availability and prices an outlet changed. The menus are modified in place.
+func ApplyMenuOverrides(menus []models_menu.Menu, overrides []models_menu.MenuOverride) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	available := make(map[uuid.UUID]bool)
+	prices := make(map[uuid.UUID]float64)
+	for _, override := range overrides {
+		if override.MenuItemOptionID != nil && override.Price != nil {
+			prices[*override.MenuItemOptionID] = *override.Price
+		} else if override.IsAvailable != nil {
+			available[override.MenuItemID] = *override.IsAvailable
+		}
+	}
+
+	for i := range menus {
+		for j := range menus[i].Categories {
+			items := menus[i].Categories[j].MenuItems
+			for k := range items {
+				if isAvailable, ok := available[items[k].ID]; ok {
+					items[k].IsAvailable = isAvailable
+				}
+				for l := range items[k].ItemOptions {
+					if price, ok := prices[items[k].ItemOptions[l].ID]; ok {
+						items[k].ItemOptions[l].Price = price
+					}
+				}
+			}
+		}
+	}
+}
+
+// applyOutletOverrides loads the overrides of an outlet and applies them to its menus in place
+func applyOutletOverrides(db *gorm.DB, restaurantID string, menus []models_menu.Menu) error {
+	overrides, err := LoadMenuOverrides(db, restaurantID)
+	if err != nil {
+		return err
+	}
+	ApplyMenuOverrides(menus, overrides)
+	return nil
+}
+
+// outletMenus limits a query on menus to the menus of the restaurant and the menus its brand shares
+func outletMenus(query *gorm.DB, restaurantID string) *gorm.DB {
+	return query.Where("(menus.restaurant_id = ? OR menus.brand_id = (SELECT brand_id FROM restaurants WHERE id = ?))", restaurantID, restaurantID)
+}
@@ -0,0 +1,301 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	utils "dine-server/src/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetPricingRules lists the pricing rules of a restaurant
+// @Summary List pricing rules
+// @Description List the happy hours, surcharges and other pricing rules of the restaurant
+// @Tags Pricing Rules
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param active query bool false "Only the rules applying now, or at the given time"
+// @Param at query string false "Evaluate the rules at this time (RFC 3339, YYYY-MM-DDTHH:MM or HH:MM)"
+// @Router /api/v1/{restaurant_id}/pricing-rules [get]
+func GetPricingRules(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	at, err := menuTime(c, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, err := LoadPricingRules(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pricing rules"})
+		return
+	}
+
+	if c.Query("active") == "true" {
+		active := []models_menu.PricingRule{}
+		for _, rule := range rules {
+			if utils.PricingRuleActiveAt(rule, at) {
+				active = append(active, rule)
+			}
+		}
+		rules = active
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing Rules Found Successfully", "pricing_rules": rules})
+}
+
+// CreatePricingRule adds a pricing rule
+// @Summary Create a pricing rule
+// @Description Add a percentage or fixed price change on the whole restaurant, a category or an item, within optional days, times and dates
+// @Tags Pricing Rules
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param rule body models_menu.AddPricingRuleData true "Pricing rule"
+// @Router /api/v1/{restaurant_id}/pricing-rules [post]
+func CreatePricingRule(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.AddPricingRuleData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models_menu.PricingRule{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: uuid.FromStringOrNil(restaurantID),
+		Name:         input.Name,
+		CategoryID:   input.CategoryID,
+		MenuItemID:   input.MenuItemID,
+		Adjustment:   input.Adjustment,
+		Value:        utils.RoundAmount(input.Value),
+		Days:         input.Days,
+		StartTime:    input.StartTime,
+		EndTime:      input.EndTime,
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+		IsActive:     true,
+	}
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+	if rule.Days == nil {
+		rule.Days = []int{}
+	}
+
+	if err := checkPricingRule(postgres.DB, restaurantID, rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := postgres.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pricing rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Pricing Rule Created Successfully", "pricing_rule": rule})
+}
+
+// UpdatePricingRule changes a pricing rule
+// @Summary Update a pricing rule
+// @Description Update the scope, adjustment or window of a pricing rule, or pause it with is_active
+// @Tags Pricing Rules
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param rule_id path string true "Pricing rule ID"
+// @Param rule body models_menu.UpdatePricingRuleData true "Pricing rule"
+// @Router /api/v1/{restaurant_id}/pricing-rules/{rule_id} [put]
+func UpdatePricingRule(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.UpdatePricingRuleData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rule models_menu.PricingRule
+	if err := postgres.DB.First(&rule, "id = ? AND restaurant_id = ?", c.Param("rule_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pricing rule not found"})
+		return
+	}
+
+	if input.Name != "" {
+		rule.Name = input.Name
+	}
+	if input.CategoryID != nil {
+		rule.CategoryID = input.CategoryID
+		if *input.CategoryID == uuid.Nil {
+			rule.CategoryID = nil
+		}
+	}
+	if input.MenuItemID != nil {
+		rule.MenuItemID = input.MenuItemID
+		if *input.MenuItemID == uuid.Nil {
+			rule.MenuItemID = nil
+		}
+	}
+	if input.Adjustment != "" {
+		rule.Adjustment = input.Adjustment
+	}
+	if input.Value != nil {
+		rule.Value = utils.RoundAmount(*input.Value)
+	}
+	if input.Days != nil {
+		rule.Days = *input.Days
+		if rule.Days == nil {
+			rule.Days = []int{}
+		}
+	}
+	if input.StartTime != nil {
+		rule.StartTime = *input.StartTime
+	}
+	if input.EndTime != nil {
+		rule.EndTime = *input.EndTime
+	}
+	if input.StartDate != nil {
+		rule.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		rule.EndDate = *input.EndDate
+	}
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+
+	if err := checkPricingRule(postgres.DB, restaurantID, rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := postgres.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pricing rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing Rule Updated Successfully", "pricing_rule": rule})
+}
+
+// DeletePricingRule deletes a pricing rule
+// @Summary Delete a pricing rule
+// @Description Delete a pricing rule. Past orders keep the rule they were priced with.
+// @Tags Pricing Rules
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param rule_id path string true "Pricing rule ID"
+// @Router /api/v1/{restaurant_id}/pricing-rules/{rule_id} [delete]
+func DeletePricingRule(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Delete(&models_menu.PricingRule{}, "id = ? AND restaurant_id = ?", c.Param("rule_id"), restaurantID)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pricing rule"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pricing rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing Rule Deleted Successfully"})
+}
+
+// LoadPricingRules loads the pricing rules of a restaurant, oldest first
+func LoadPricingRules(db *gorm.DB, restaurantID string) ([]models_menu.PricingRule, error) {
+	rules := []models_menu.PricingRule{}
+	err := db.Where("restaurant_id = ?", restaurantID).Order("created_at").Find(&rules).Error
+	return rules, err
+}
+
+// ApplyPricing returns the menus with the option prices changed by the pricing rules applying at t.
+// The original price is kept in base_price. The menus given are not changed, they may be shared with the cache.
+func ApplyPricing(menus []models_menu.Menu, rules []models_menu.PricingRule, t time.Time) []models_menu.Menu {
+	if len(rules) == 0 {
+		return menus
+	}
+
+	priced := make([]models_menu.Menu, 0, len(menus))
+	for _, menu := range menus {
+		categories := make([]models_menu.MenuCategory, 0, len(menu.Categories))
+		for _, category := range menu.Categories {
+			items := make([]models_menu.MenuItem, 0, len(category.MenuItems))
+			for _, item := range category.MenuItems {
+				if rule := utils.PricingRuleFor(rules, item.ID, category.ID, t); rule != nil {
+					options := make([]models_menu.MenuItemOption, 0, len(item.ItemOptions))
+					for _, option := range item.ItemOptions {
+						basePrice := option.Price
+						option.BasePrice = &basePrice
+						option.PricingRuleID = &rule.ID
+						option.Price = utils.AdjustPrice(basePrice, *rule)
+						options = append(options, option)
+					}
+					item.ItemOptions = options
+				}
+				items = append(items, item)
+			}
+			category.MenuItems = items
+			categories = append(categories, category)
+		}
+		menu.Categories = categories
+		priced = append(priced, menu)
+	}
+	return priced
+}
+
+// checkPricingRule validates the scope and the window of a rule
+func checkPricingRule(db *gorm.DB, restaurantID string, rule models_menu.PricingRule) error {
+	if rule.CategoryID != nil && rule.MenuItemID != nil {
+		return errors.New("a pricing rule applies to a category or an item, not both")
+	}
+	if rule.Adjustment == models_menu.PriceAdjustmentPercentage && rule.Value < -100 {
+		return errors.New("a percentage discount cannot be more than 100")
+	}
+	for _, value := range []string{rule.StartTime, rule.EndTime} {
+		if _, err := time.Parse("15:04", value); value != "" && err != nil {
+			return errors.New("times must be formatted as HH:MM")
+		}
+	}
+	for _, value := range []string{rule.StartDate, rule.EndDate} {
+		if _, err := time.Parse("2006-01-02", value); value != "" && err != nil {
+			return errors.New("dates must be formatted as YYYY-MM-DD")
+		}
+	}
+	if (rule.StartTime == "") != (rule.EndTime == "") {
+		return errors.New("start_time and end_time must be set together")
+	}
+	if rule.StartDate != "" && rule.EndDate != "" && rule.EndDate < rule.StartDate {
+		return errors.New("end_date is before start_date")
+	}
+
+	if rule.CategoryID != nil {
+		var count int64
+		if err := db.Model(&models_menu.MenuCategory{}).
+			Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+			Where("menu_categories.id = ? AND menus.restaurant_id = ?", rule.CategoryID, restaurantID).
+			Count(&count).Error; err != nil || count == 0 {
+			return errors.New("category not found")
+		}
+	}
+	if rule.MenuItemID != nil {
+		var count int64
+		if err := db.Model(&models_menu.MenuItem{}).
+			Joins("JOIN menus ON menus.id = menu_items.menu_id").
+			Where("menu_items.id = ? AND menus.restaurant_id = ?", rule.MenuItemID, restaurantID).
+			Count(&count).Error; err != nil || count == 0 {
+			return errors.New("menu item not found")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// SetMenuSchedules replaces the schedules of a menu
+// @Summary Set menu schedules
+// @Description Set when a menu is served (days of week, time range, date range). An empty list means always served.
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param schedules body models_menu.SetMenuSchedulesData true "Schedules"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/schedules [put]
+func SetMenuSchedules(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.SetMenuSchedulesData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var menu models_menu.Menu
+	if err := postgres.DB.First(&menu, "id = ? AND restaurant_id = ?", c.Param("menu_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	schedules, err := replaceSchedules(postgres.DB, "menu_id", menu.ID, input.Schedules, func(schedule *models_menu.MenuSchedule) {
+		schedule.MenuID = &menu.ID
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update menu schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Schedules Updated Successfully", "schedules": schedules})
+}
+
+// SetCategorySchedules replaces the schedules of a menu category
+// @Summary Set category schedules
+// @Description Set when a category is served within its menu. An empty list means always served.
+// @Tags Menu Category
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param schedules body models_menu.SetMenuSchedulesData true "Schedules"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/schedules [put]
+func SetCategorySchedules(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.SetMenuSchedulesData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var category models_menu.MenuCategory
+	if err := postgres.DB.Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+		Where("menu_categories.id = ? AND menu_categories.menu_id = ? AND menus.restaurant_id = ?", c.Param("category_id"), c.Param("menu_id"), restaurantID).
+		First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	schedules, err := replaceSchedules(postgres.DB, "category_id", category.ID, input.Schedules, func(schedule *models_menu.MenuSchedule) {
+		schedule.CategoryID = &category.ID
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category Schedules Updated Successfully", "schedules": schedules})
+}
+
+// replaceSchedules swaps the schedules owned by a menu or category in one transaction
+func replaceSchedules(db *gorm.DB, ownerColumn string, ownerID uuid.UUID, input []models_menu.MenuScheduleData, setOwner func(*models_menu.MenuSchedule)) ([]models_menu.MenuSchedule, error) {
+	schedules := []models_menu.MenuSchedule{}
+	for _, data := range input {
+		schedule := models_menu.MenuSchedule{
+			Days:      data.Days,
+			StartTime: data.StartTime,
+			EndTime:   data.EndTime,
+			StartDate: data.StartDate,
+			EndDate:   data.EndDate,
+		}
+		if schedule.Days == nil {
+			schedule.Days = []int{}
+		}
+		setOwner(&schedule)
+		schedules = append(schedules, schedule)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(ownerColumn+" = ?", ownerID).Delete(&models_menu.MenuSchedule{}).Error; err != nil {
+			return err
+		}
+		if len(schedules) == 0 {
+			return nil
+		}
+		return tx.Create(&schedules).Error
+	})
+
+	return schedules, err
+}
+
+// menuTime returns the time menus are evaluated at: now, or the at= query, in the restaurant's timezone
+func menuTime(c *gin.Context, restaurantID string) (time.Time, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "timezone").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return time.Time{}, err
+	}
+
+	return utils.ParseMenuTime(c.Query("at"), utils.RestaurantLocation(restaurant))
+}
+
+// canManageMenus reports whether the caller is staff of the restaurant and may see inactive menus
+func canManageMenus(c *gin.Context, restaurantID string) bool {
+	if _, exists := c.Get("userID"); !exists {
+		return false
+	}
+	allowed, err := utils.HasPermission(c, restaurantID, models_restaurant.PermissionMenuView)
+	return err == nil && allowed
+}
+
+// markServedMenus sets IsActive on the menus and their categories for the given time
+func markServedMenus(menus []models_menu.Menu, t time.Time) {
+	for i := range menus {
+		menus[i].IsActive = utils.MenuServedAt(menus[i].Schedules, t)
+		for j := range menus[i].Categories {
+			menus[i].Categories[j].IsActive = utils.MenuServedAt(menus[i].Categories[j].Schedules, t)
+		}
+	}
+}
+
+// servedCategories drops the categories that are not served
+func servedCategories(categories []models_menu.MenuCategory) []models_menu.MenuCategory {
+	served := []models_menu.MenuCategory{}
+	for _, category := range categories {
+		if category.IsActive {
+			served = append(served, category)
+		}
+	}
+	return served
+}
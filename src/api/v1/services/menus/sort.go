@@ -0,0 +1,158 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// ReorderMenuCategories sets the display order of the categories of a menu
+// @Summary Reorder categories
+// @Description Rewrite the positions of the categories of a menu from the order of the given IDs, which must list every category once
+// @Tags Menu Category
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param order body models_menu.ReorderMenuData true "Category IDs in display order"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/order [put]
+func ReorderMenuCategories(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.ReorderMenuData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var menu models_menu.Menu
+	if err := postgres.DB.First(&menu, "id = ? AND restaurant_id = ?", c.Param("menu_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+
+	reorder(c, &models_menu.MenuCategory{}, "menu_id", menu.ID, "category", input.IDs)
+}
+
+// ReorderMenuItems sets the display order of the items of a category
+// @Summary Reorder items
+// @Description Rewrite the positions of the items of a category from the order of the given IDs, which must list every item once
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param order body models_menu.ReorderMenuData true "Item IDs in display order"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/order [put]
+func ReorderMenuItems(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.ReorderMenuData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var category models_menu.MenuCategory
+	if err := postgres.DB.Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+		Where("menu_categories.id = ? AND menu_categories.menu_id = ? AND menus.restaurant_id = ?", c.Param("category_id"), c.Param("menu_id"), restaurantID).
+		First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	reorder(c, &models_menu.MenuItem{}, "category_id", category.ID, "item", input.IDs)
+}
+
+// ReorderMenuItemOptions sets the display order of the options of an item
+// @Summary Reorder item options
+// @Description Rewrite the positions of the options of an item from the order of the given IDs, which must list every option once
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param menu_id path string true "Menu ID"
+// @Param category_id path string true "Category ID"
+// @Param item_id path string true "Item ID"
+// @Param order body models_menu.ReorderMenuData true "Option IDs in display order"
+// @Router /api/v1/{restaurant_id}/menus/{menu_id}/categories/{category_id}/items/{item_id}/options/order [put]
+func ReorderMenuItemOptions(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.ReorderMenuData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var item models_menu.MenuItem
+	if err := postgres.DB.Joins("JOIN menus ON menus.id = menu_items.menu_id").
+		Where("menu_items.id = ? AND menu_items.category_id = ? AND menu_items.menu_id = ? AND menus.restaurant_id = ?", c.Param("item_id"), c.Param("category_id"), c.Param("menu_id"), restaurantID).
+		First(&item).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	reorder(c, &models_menu.MenuItemOption{}, "menu_item_id", item.ID, "option", input.IDs)
+}
+
+// reorder rewrites the positions of the children of a parent in one transaction and writes the response
+func reorder(c *gin.Context, model interface{}, parentColumn string, parentID uuid.UUID, name string, ids []uuid.UUID) {
+	tx := postgres.DB.Begin()
+
+	var existing []uuid.UUID
+	if err := tx.Model(model).Where(parentColumn+" = ?", parentID).Pluck("id", &existing).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + name + "s"})
+		return
+	}
+
+	children := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		children[id] = true
+	}
+	for _, id := range ids {
+		if !children[id] {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ids must list every %s exactly once", name)})
+			return
+		}
+		delete(children, id)
+	}
+	if len(children) > 0 {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ids must list every %s exactly once", name)})
+		return
+	}
+
+	for position, id := range ids {
+		if err := tx.Model(model).Where("id = ?", id).Update("position", position).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder " + name + "s"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order Updated Successfully", "ids": ids})
+}
+
+// nextPosition returns the position after the last child of a parent, so new records are shown last
+func nextPosition(db *gorm.DB, model interface{}, parentColumn string, parentID uuid.UUID) (int, error) {
+	var position int
+	err := db.Model(model).Where(parentColumn+" = ?", parentID).Select("COALESCE(MAX(position), -1) + 1").Scan(&position).Error
+	return position, err
+}
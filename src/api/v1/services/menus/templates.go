@@ -0,0 +1,180 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// GetMenuTemplates lists the menu templates
+// @Summary Get menu templates
+// @Description List the published menu templates, without their menus. Platform admins also see unpublished templates.
+// @Tags Menu Templates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param cuisine query string false "Only templates of this cuisine"
+// @Router /api/v1/menu-templates [get]
+func GetMenuTemplates(c *gin.Context) {
+	query := postgres.DB.Omit("menu").Order("name")
+	if role, _ := c.Get("role"); role != "admin" {
+		query = query.Where("is_published = ?", true)
+	}
+	if cuisine := c.Query("cuisine"); cuisine != "" {
+		query = query.Where("LOWER(cuisine) = LOWER(?)", cuisine)
+	}
+
+	var templates []models_menu.MenuTemplate
+	if err := query.Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Templates Found Successfully", "templates": templates})
+}
+
+// GetMenuTemplate returns a menu template with its menu
+// @Summary Get a menu template
+// @Tags Menu Templates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path string true "Template ID"
+// @Router /api/v1/menu-templates/{template_id} [get]
+func GetMenuTemplate(c *gin.Context) {
+	query := postgres.DB.Where("id = ?", c.Param("template_id"))
+	if role, _ := c.Get("role"); role != "admin" {
+		query = query.Where("is_published = ?", true)
+	}
+
+	var template models_menu.MenuTemplate
+	if err := query.First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Template Found Successfully", "template": template})
+}
+
+// CreateMenuTemplate makes a platform menu template from a menu of a restaurant
+// @Summary Create a menu template
+// @Description Save a copy of a restaurant menu with its schedules, categories, items, options and modifiers as a template. Kitchen stations are left out.
+// @Tags Menu Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template body models_menu.AddMenuTemplateData true "Template data"
+// @Router /api/v1/menu-templates [post]
+func CreateMenuTemplate(c *gin.Context) {
+	var input models_menu.AddMenuTemplateData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existing models_menu.MenuTemplate
+	if err := postgres.DB.Omit("menu").Where("name = ?", input.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A Menu Template with the same name already exists"})
+		return
+	}
+
+	source, err := loadMenuForClone(postgres.DB, input.RestaurantID.String(), input.MenuID.String())
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+		return
+	}
+	menu := copyMenu(source, uuid.Nil, source.Name, 0, false)
+
+	template := models_menu.MenuTemplate{
+		Name:        input.Name,
+		Description: input.Description,
+		Cuisine:     input.Cuisine,
+		Menu:        &menu,
+		IsPublished: input.IsPublished,
+		CreatedBy:   currentUserID(c),
+	}
+	if err := postgres.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Menu Template Created Successfully", "template": template})
+}
+
+// UpdateMenuTemplate changes the details of a menu template or publishes it
+// @Summary Update a menu template
+// @Description Rename a template, change its description or cuisine, or publish it with is_published. The menu of a template cannot be changed, create a new template instead.
+// @Tags Menu Templates
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path string true "Template ID"
+// @Param template body models_menu.UpdateMenuTemplateData true "Template data"
+// @Router /api/v1/menu-templates/{template_id} [put]
+func UpdateMenuTemplate(c *gin.Context) {
+	var input models_menu.UpdateMenuTemplateData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var template models_menu.MenuTemplate
+	if err := postgres.DB.Omit("menu").First(&template, "id = ?", c.Param("template_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu template not found"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != "" && input.Name != template.Name {
+		var existing models_menu.MenuTemplate
+		if err := postgres.DB.Omit("menu").Where("name = ? AND id <> ?", input.Name, template.ID).First(&existing).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A Menu Template with the same name already exists"})
+			return
+		}
+		updates["name"] = input.Name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.Cuisine != nil {
+		updates["cuisine"] = *input.Cuisine
+	}
+	if input.IsPublished != nil {
+		updates["is_published"] = *input.IsPublished
+	}
+
+	if len(updates) > 0 {
+		if err := postgres.DB.Model(&template).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update menu template"})
+			return
+		}
+		if err := postgres.DB.Omit("menu").First(&template, "id = ?", template.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu template"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Template Updated Successfully", "template": template})
+}
+
+// DeleteMenuTemplate deletes a menu template, menus created from it are kept
+// @Summary Delete a menu template
+// @Tags Menu Templates
+// @Produce json
+// @Security ApiKeyAuth
+// @Param template_id path string true "Template ID"
+// @Router /api/v1/menu-templates/{template_id} [delete]
+func DeleteMenuTemplate(c *gin.Context) {
+	result := postgres.DB.Delete(&models_menu.MenuTemplate{}, "id = ?", c.Param("template_id"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete menu template"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu template deleted successfully"})
+}
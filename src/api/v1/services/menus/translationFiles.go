@@ -0,0 +1,367 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// translationSource is a record that can be translated, with its text in the default language
+type translationSource struct {
+	Type   models_restaurant.TranslationEntity
+	Fields map[string]string
+}
+
+// ImportTranslations imports the translations of one language from a file
+// @Summary Import translations
+// @Description Import the translations of one language from a CSV, XLSX or JSON file in the layout of the export. Rows with an empty value are skipped.
+// @Tags Translations
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param locale path string true "Language, e.g. fr"
+// @Param file formData file false "Translation file"
+// @Param format query string false "File format (csv, xlsx or json), taken from the file name or content type when empty"
+// @Param dry_run query bool false "Only validate the file and report what would change"
+// @Router /api/v1/{restaurant_id}/translations/{locale}/import [post]
+func ImportTranslations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	locale, status, err := translationLocale(postgres.DB, restaurantID, c.Param("locale"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The file comes either as a multipart upload or as the raw body
+	var reader io.Reader = c.Request.Body
+	fileName, contentType := "", c.ContentType()
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+		fileName, contentType = header.Filename, header.Header.Get("Content-Type")
+	}
+
+	format, err := utils.MenuFileFormat(c.Query("format"), fileName, contentType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fileLocale, rows, err := utils.ParseTranslationFile(format, reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fileLocale != "" && utils.NormalizeLocale(fileLocale) != locale {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File is for language %q, not %q", fileLocale, locale)})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File has no rows"})
+		return
+	}
+	if len(rows) > maxMenuImportRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File has more than %d rows", maxMenuImportRows)})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	sources, _, err := translationSources(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+		return
+	}
+
+	if rowErrors := validateTranslationRows(rows, sources); len(rowErrors) > 0 {
+		if dryRun {
+			c.JSON(http.StatusOK, gin.H{"message": "Translation Import Validated", "valid": false, "rows": len(rows), "errors": rowErrors})
+		} else {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Translation file has invalid rows", "errors": rowErrors})
+		}
+		return
+	}
+
+	if dryRun {
+		summary, err := saveTranslations(postgres.DB, restaurantID, locale, rows, false, true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Translation Import Validated", "valid": true, "rows": len(rows), "errors": []models_restaurant.TranslationError{}, "summary": summary})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	summary, err := saveTranslations(tx, restaurantID, locale, rows, false, false)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translations Imported Successfully", "locale": locale, "rows": len(rows), "summary": summary})
+}
+
+// ExportTranslations exports the texts of a restaurant with their translations in one language
+// @Summary Export translations
+// @Description Export the name and description of the restaurant, categories and items with their translation in a language, to be filled in and imported back
+// @Tags Translations
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param locale path string true "Language, e.g. fr"
+// @Param format query string false "File format (csv, xlsx or json), defaults to json"
+// @Router /api/v1/{restaurant_id}/translations/{locale}/export [get]
+func ExportTranslations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	format := c.DefaultQuery("format", utils.MenuFormatJSON)
+	format, err := utils.MenuFileFormat(format, "", "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	locale, status, err := translationLocale(postgres.DB, restaurantID, c.Param("locale"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	sources, order, err := translationSources(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+		return
+	}
+
+	translations, err := loadTranslations(postgres.DB, restaurantID, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+
+	file := models_restaurant.TranslationFile{Locale: locale, Translations: []models_restaurant.TranslationRow{}}
+	for _, id := range order {
+		source := sources[id]
+		for _, field := range models_restaurant.TranslatedFields {
+			// Nothing to translate when the record has no text for the field
+			if source.Fields[field] == "" {
+				continue
+			}
+			file.Translations = append(file.Translations, models_restaurant.TranslationRow{
+				EntityType: source.Type,
+				EntityID:   id.String(),
+				Field:      field,
+				Source:     source.Fields[field],
+				Value:      translations[id][field],
+			})
+		}
+	}
+
+	body, err := utils.TranslationFileBuffer(format, file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export translations"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="translations-%s.%s"`, locale, format))
+	c.Data(http.StatusOK, utils.MenuFileContentType(format), body)
+}
+
+// translationLocale checks the language of a translation request, it can't be the default language of the restaurant
+func translationLocale(db *gorm.DB, restaurantID, locale string) (string, int, error) {
+	defaultLanguage, _, err := restaurantLanguages(db, restaurantID)
+	if err != nil {
+		return "", http.StatusNotFound, fmt.Errorf("restaurant not found")
+	}
+
+	normalized := utils.NormalizeLocale(locale)
+	if normalized == "" {
+		return "", http.StatusBadRequest, fmt.Errorf("invalid language %q", locale)
+	}
+	if normalized == defaultLanguage {
+		return "", http.StatusBadRequest, fmt.Errorf("%q is the default language of the restaurant, update the records themselves", normalized)
+	}
+	return normalized, http.StatusOK, nil
+}
+
+// translationSources loads the restaurant, its categories and its items with their text in the default language, in menu order
+func translationSources(db *gorm.DB, restaurantID string) (map[uuid.UUID]translationSource, []uuid.UUID, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.Select("id", "name", "description").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var categories []models_menu.MenuCategory
+	if err := db.Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+		Where("menus.restaurant_id = ?", restaurantID).
+		Order("menus.created_at, menu_categories.position, menu_categories.created_at").
+		Find(&categories).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var items []models_menu.MenuItem
+	if err := db.Joins("JOIN menu_categories ON menu_categories.id = menu_items.category_id").
+		Joins("JOIN menus ON menus.id = menu_categories.menu_id").
+		Where("menus.restaurant_id = ?", restaurantID).
+		Order("menu_items.position, menu_items.created_at").
+		Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+
+	text := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+
+	sources := make(map[uuid.UUID]translationSource, 1+len(categories)+len(items))
+	order := make([]uuid.UUID, 0, 1+len(categories)+len(items))
+	add := func(id uuid.UUID, entityType models_restaurant.TranslationEntity, name, description string) {
+		sources[id] = translationSource{Type: entityType, Fields: map[string]string{"name": name, "description": description}}
+		order = append(order, id)
+	}
+
+	add(restaurant.ID, models_restaurant.TranslationRestaurant, restaurant.Name, restaurant.Description)
+
+	itemsByCategory := make(map[uuid.UUID][]models_menu.MenuItem, len(categories))
+	for _, item := range items {
+		itemsByCategory[item.CategoryID] = append(itemsByCategory[item.CategoryID], item)
+	}
+	for _, category := range categories {
+		add(category.ID, models_restaurant.TranslationCategory, category.Name, text(category.Description))
+		for _, item := range itemsByCategory[category.ID] {
+			add(item.ID, models_restaurant.TranslationItem, item.Name, text(item.Description))
+		}
+	}
+	return sources, order, nil
+}
+
+// validateTranslationRows checks every row of a translation file against the records of the restaurant
+func validateTranslationRows(rows []models_restaurant.TranslationRow, sources map[uuid.UUID]translationSource) []models_restaurant.TranslationError {
+	rowErrors := []models_restaurant.TranslationError{}
+	fail := func(row int, field, message string) {
+		rowErrors = append(rowErrors, models_restaurant.TranslationError{Row: row, Field: field, Error: message})
+	}
+
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		switch row.EntityType {
+		case models_restaurant.TranslationRestaurant, models_restaurant.TranslationCategory, models_restaurant.TranslationItem:
+		default:
+			fail(row.Row, "entity_type", "must be restaurant, category or item")
+			continue
+		}
+
+		id, err := uuid.FromString(row.EntityID)
+		if err != nil {
+			fail(row.Row, "entity_id", "is not a valid id")
+			continue
+		}
+		if source, ok := sources[id]; !ok || source.Type != row.EntityType {
+			fail(row.Row, "entity_id", fmt.Sprintf("no %s with this id in the restaurant", row.EntityType))
+			continue
+		}
+
+		if row.Field != "name" && row.Field != "description" {
+			fail(row.Row, "field", "must be name or description")
+			continue
+		}
+
+		key := importKey(row.EntityID, row.Field)
+		if first, ok := seen[key]; ok {
+			fail(row.Row, "field", fmt.Sprintf("already translated on row %d", first))
+			continue
+		}
+		seen[key] = row.Row
+	}
+	return rowErrors
+}
+
+// saveTranslations writes translations of one language, matching existing ones by record and field.
+// Empty values remove the translation when removeEmpty is set and are skipped otherwise.
+// With dryRun nothing is written, only the summary is computed.
+func saveTranslations(db *gorm.DB, restaurantID, locale string, rows []models_restaurant.TranslationRow, removeEmpty, dryRun bool) (models_restaurant.TranslationSummary, error) {
+	var summary models_restaurant.TranslationSummary
+
+	var existing []models_restaurant.Translation
+	if err := db.Where("restaurant_id = ? AND locale = ?", restaurantID, locale).Find(&existing).Error; err != nil {
+		return summary, fmt.Errorf("failed to fetch translations")
+	}
+	translations := make(map[string]*models_restaurant.Translation, len(existing))
+	for i := range existing {
+		translations[importKey(existing[i].EntityID.String(), existing[i].Field)] = &existing[i]
+	}
+
+	for _, row := range rows {
+		key := importKey(row.EntityID, row.Field)
+		translation, found := translations[key]
+
+		switch {
+		case row.Value == "" && (!removeEmpty || !found):
+			summary.Skipped++
+		case row.Value == "":
+			if !dryRun {
+				if err := db.Delete(translation).Error; err != nil {
+					return summary, fmt.Errorf("failed to remove translation")
+				}
+			}
+			delete(translations, key)
+			summary.Removed++
+		case found && translation.Value == row.Value:
+			summary.Unchanged++
+		case found:
+			if !dryRun {
+				if err := db.Model(translation).Update("value", row.Value).Error; err != nil {
+					return summary, fmt.Errorf("failed to update translation")
+				}
+			}
+			translation.Value = row.Value
+			summary.Updated++
+		default:
+			translation = &models_restaurant.Translation{
+				ID:           uuid.Must(uuid.NewV4()),
+				RestaurantID: uuid.FromStringOrNil(restaurantID),
+				EntityType:   row.EntityType,
+				EntityID:     uuid.FromStringOrNil(row.EntityID),
+				Field:        row.Field,
+				Locale:       locale,
+				Value:        row.Value,
+			}
+			if !dryRun {
+				if err := db.Create(translation).Error; err != nil {
+					return summary, fmt.Errorf("failed to create translation")
+				}
+			}
+			translations[key] = translation
+			summary.Created++
+		}
+	}
+	return summary, nil
+}
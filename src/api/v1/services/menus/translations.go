@@ -0,0 +1,292 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	models_restaurant "dine-server/src/models/restaurants"
+	utils "dine-server/src/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Translations holds the text of one language by record ID and field
+type Translations map[uuid.UUID]map[string]string
+
+// Text returns the translation of a field, or the text in the default language when there is none
+func (t Translations) Text(id uuid.UUID, field, text string) string {
+	if value, ok := t[id][field]; ok {
+		return value
+	}
+	return text
+}
+
+// textPointer translates an optional field, keeping the original value untouched
+func (t Translations) textPointer(id uuid.UUID, field string, text *string) *string {
+	if value, ok := t[id][field]; ok {
+		return &value
+	}
+	return text
+}
+
+// MenuLanguage picks the language of a menu response from ?lang= and, when negotiate is set, the Accept-Language header.
+// It returns the translations of that language, nil when it is the default language of the restaurant.
+func MenuLanguage(c *gin.Context, db *gorm.DB, restaurantID string, negotiate bool) (string, Translations, error) {
+	defaultLanguage, locales, err := restaurantLanguages(db, restaurantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.DefaultLanguage, nil, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	acceptLanguage := ""
+	if negotiate {
+		acceptLanguage = c.GetHeader("Accept-Language")
+	}
+	language := utils.NegotiateLanguage(c.Query("lang"), acceptLanguage, append([]string{defaultLanguage}, locales...), defaultLanguage)
+	c.Header("Content-Language", language)
+
+	if language == defaultLanguage {
+		return language, nil, nil
+	}
+	translations, err := loadTranslations(db, restaurantID, language)
+	return language, translations, err
+}
+
+// TranslateMenus returns the menus with the names and descriptions of categories and items translated.
+// The menus given are not changed, they may be shared with the cache.
+func TranslateMenus(menus []models_menu.Menu, translations Translations) []models_menu.Menu {
+	if translations == nil {
+		return menus
+	}
+
+	translateItem := func(item models_menu.MenuItem) models_menu.MenuItem {
+		item.Name = translations.Text(item.ID, "name", item.Name)
+		item.Description = translations.textPointer(item.ID, "description", item.Description)
+		return item
+	}
+
+	translated := make([]models_menu.Menu, 0, len(menus))
+	for _, menu := range menus {
+		categories := make([]models_menu.MenuCategory, 0, len(menu.Categories))
+		for _, category := range menu.Categories {
+			category.Name = translations.Text(category.ID, "name", category.Name)
+			category.Description = translations.textPointer(category.ID, "description", category.Description)
+			items := make([]models_menu.MenuItem, 0, len(category.MenuItems))
+			for _, item := range category.MenuItems {
+				items = append(items, translateItem(item))
+			}
+			category.MenuItems = items
+			categories = append(categories, category)
+		}
+		menu.Categories = categories
+
+		combos := make([]models_menu.Combo, 0, len(menu.Combos))
+		for _, combo := range menu.Combos {
+			slots := make([]models_menu.ComboSlot, 0, len(combo.Slots))
+			for _, slot := range combo.Slots {
+				items := make([]models_menu.ComboSlotItem, 0, len(slot.Items))
+				for _, item := range slot.Items {
+					if item.MenuItem != nil {
+						menuItem := translateItem(*item.MenuItem)
+						item.MenuItem = &menuItem
+					}
+					items = append(items, item)
+				}
+				slot.Items = items
+				slots = append(slots, slot)
+			}
+			combo.Slots = slots
+			combos = append(combos, combo)
+		}
+		menu.Combos = combos
+
+		translated = append(translated, menu)
+	}
+	return translated
+}
+
+// TranslateRestaurant translates the name and description of a restaurant response
+func TranslateRestaurant(restaurant *models_restaurant.ResponseRestaurantData, translations Translations) {
+	restaurant.Name = translations.Text(restaurant.ID, "name", restaurant.Name)
+	restaurant.Description = translations.Text(restaurant.ID, "description", restaurant.Description)
+}
+
+// GetTranslations lists the languages of a restaurant, with the translations of one of them
+// @Summary List translations
+// @Description List the languages the restaurant is translated into with the number of translated fields, and the translations of ?locale= when given
+// @Tags Translations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param locale query string false "Language to list the translations of, e.g. fr"
+// @Router /api/v1/{restaurant_id}/translations [get]
+func GetTranslations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	defaultLanguage, _, err := restaurantLanguages(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	var languages []struct {
+		Locale string `json:"locale"`
+		Fields int    `json:"fields"`
+	}
+	if err := postgres.DB.Model(&models_restaurant.Translation{}).
+		Select("locale, COUNT(*) AS fields").
+		Where("restaurant_id = ?", restaurantID).
+		Group("locale").
+		Order("locale").
+		Scan(&languages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch languages"})
+		return
+	}
+
+	translations := []models_restaurant.Translation{}
+	if locale := utils.NormalizeLocale(c.Query("locale")); locale != "" {
+		if err := postgres.DB.Where("restaurant_id = ? AND locale = ?", restaurantID, locale).
+			Order("entity_type, entity_id, field").
+			Find(&translations).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Translations Found Successfully",
+		"default_language": defaultLanguage,
+		"languages":        languages,
+		"translations":     translations,
+	})
+}
+
+// SetTranslations adds, changes or removes translations of one language
+// @Summary Set translations
+// @Description Set the translation of the name or description of the restaurant, categories and items in a language. An empty value removes the translation.
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param locale path string true "Language, e.g. fr or pt-br"
+// @Param translations body models_restaurant.SetTranslationsData true "Translations"
+// @Router /api/v1/{restaurant_id}/translations/{locale} [put]
+func SetTranslations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.SetTranslationsData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	locale, status, err := translationLocale(postgres.DB, restaurantID, c.Param("locale"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	sources, _, err := translationSources(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+		return
+	}
+
+	rows := make([]models_restaurant.TranslationRow, 0, len(input.Translations))
+	for _, translation := range input.Translations {
+		if sources[translation.EntityID].Type != translation.EntityType {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No " + string(translation.EntityType) + " " + translation.EntityID.String() + " in this restaurant"})
+			return
+		}
+		rows = append(rows, models_restaurant.TranslationRow{
+			EntityType: translation.EntityType,
+			EntityID:   translation.EntityID.String(),
+			Field:      translation.Field,
+			Value:      translation.Value,
+		})
+	}
+
+	tx := postgres.DB.Begin()
+
+	summary, err := saveTranslations(tx, restaurantID, locale, rows, true, false)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translations Updated Successfully", "locale": locale, "summary": summary})
+}
+
+// DeleteTranslations removes a language from a restaurant
+// @Summary Delete a language
+// @Description Remove every translation of a language, guests asking for it get the default language
+// @Tags Translations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param locale path string true "Language, e.g. fr"
+// @Router /api/v1/{restaurant_id}/translations/{locale} [delete]
+func DeleteTranslations(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Delete(&models_restaurant.Translation{}, "restaurant_id = ? AND locale = ?", restaurantID, utils.NormalizeLocale(c.Param("locale")))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translations"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Language not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translations Deleted Successfully", "deleted": result.RowsAffected})
+}
+
+// restaurantLanguages returns the default language of a restaurant and the other languages it has translations in
+func restaurantLanguages(db *gorm.DB, restaurantID string) (string, []string, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.Select("id", "language").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return "", nil, err
+	}
+	defaultLanguage := restaurant.Language
+	if defaultLanguage == "" {
+		defaultLanguage = utils.DefaultLanguage
+	}
+
+	var locales []string
+	if err := db.Model(&models_restaurant.Translation{}).
+		Where("restaurant_id = ? AND locale <> ?", restaurantID, defaultLanguage).
+		Distinct().Order("locale").
+		Pluck("locale", &locales).Error; err != nil {
+		return "", nil, err
+	}
+	return defaultLanguage, locales, nil
+}
+
+// loadTranslations loads the translations of a restaurant in one language
+func loadTranslations(db *gorm.DB, restaurantID, locale string) (Translations, error) {
+	var rows []models_restaurant.Translation
+	if err := db.Where("restaurant_id = ? AND locale = ?", restaurantID, locale).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	translations := make(Translations, len(rows))
+	for _, row := range rows {
+		if translations[row.EntityID] == nil {
+			translations[row.EntityID] = make(map[string]string)
+		}
+		translations[row.EntityID][row.Field] = row.Value
+	}
+	return translations, nil
+}
@@ -0,0 +1,368 @@
+package services_menu
+
+import (
+	"crypto/sha256"
+	services_inventory "dine-server/src/api/v1/services/inventory"
+	"dine-server/src/config/cache"
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"dine-server/src/utils"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// menuTree is the cached representation of the menus guests see: the published version, or the live menus
+type menuTree struct {
+	Menus         []models_menu.Menu
+	LastModified  time.Time
+	VersionID     *uuid.UUID // Published version the menus come from, nil when nothing is published
+	NextPublishAt *time.Time // Next scheduled version, the tree is reloaded once it is due
+}
+
+// GetMenuTree retrieves every menu of a restaurant with its categories, items and options
+// @Summary Retrieve the full menu tree
+// @Description Retrieve menus, categories, items and options in one call. Guests only get what is published, served and available now.
+// @Description Staff get the draft they are editing, or the published version with version=published. Supports ETag revalidation.
+// @Tags Menu
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param at query string false "Evaluate schedules at this time (RFC 3339, YYYY-MM-DDTHH:MM or HH:MM)"
+// @Param version query string false "Staff only: draft (default) or published"
+// @Param diet query string false "Only items suitable for these diets, comma separated (vegetarian, vegan, jain, gluten_free, halal)"
+// @Param exclude_allergens query string false "Leave out items containing these allergens, comma separated (e.g. nuts,dairy)"
+// @Param lang query string false "Language of the names and descriptions, e.g. fr"
+// @Param Accept-Language header string false "Languages the guest reads, used when lang is not given"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Router /api/v1/{restaurant_id}/menus/tree [get]
+func GetMenuTree(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	at, err := menuTime(c, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := utils.ParseMenuItemFilter(c.QueryArray("diet"), c.QueryArray("exclude_allergens"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	manager := canManageMenus(c, restaurantID)
+
+	var tree menuTree
+	draft := manager && c.Query("version") != "published"
+	if draft {
+		menus, err := loadLiveMenus(postgres.DB, restaurantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+			return
+		}
+		if err := applyOutletOverrides(postgres.DB, restaurantID, menus); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu overrides"})
+			return
+		}
+		tree = menuTree{Menus: menus, LastModified: menusLastModified(menus)}
+	} else {
+		tree, err = loadMenuTree(postgres.DB, restaurantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+			return
+		}
+	}
+
+	menus := copyMenus(tree.Menus)
+
+	// The published menus show what ran out and the prices of the pricing rules, the draft keeps what staff set
+	if !draft {
+		stock, err := services_inventory.LoadStockState(postgres.DB, restaurantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock"})
+			return
+		}
+		menus = services_inventory.ApplyStock(menus, stock)
+
+		rules, err := LoadPricingRules(postgres.DB, restaurantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pricing rules"})
+			return
+		}
+		menus = ApplyPricing(menus, rules, at)
+	}
+
+	markServedMenus(menus, at)
+	if !manager {
+		menus = servedMenuTree(menus)
+	}
+	menus = FilterMenuItems(menus, filter)
+
+	// Staff edit the default language, they only get a translation when they ask for it with ?lang=
+	language, translations, err := MenuLanguage(c, postgres.DB, restaurantID, !manager)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+	menus = TranslateMenus(menus, translations)
+
+	body, err := json.Marshal(gin.H{"message": "Menu Tree Found Successfully", "menu_version_id": tree.VersionID, "language": language, "menus": menus})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode menus"})
+		return
+	}
+
+	// The body depends on the time and on the caller, so the ETag is taken from the body itself
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	if manager {
+		c.Header("Cache-Control", "private, no-cache")
+	} else {
+		c.Header("Cache-Control", "public, no-cache")
+	}
+	c.Header("Vary", "Authorization, Cookie, Accept-Language")
+	c.Header("ETag", etag)
+	if !tree.LastModified.IsZero() {
+		c.Header("Last-Modified", tree.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// PublishedMenus returns the menus guests order from, with the published version they come from.
+// Restaurants that never published a version use their live menus. The menus are shared and must not be modified.
+func PublishedMenus(db *gorm.DB, restaurantID string) ([]models_menu.Menu, *uuid.UUID, error) {
+	tree, err := loadMenuTree(db, restaurantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tree.Menus, tree.VersionID, nil
+}
+
+// ServedMenus returns the published menus served at the given time, with their served categories,
+// the items available and in stock and the prices of the pricing rules applying then
+func ServedMenus(db *gorm.DB, restaurantID string, at time.Time) ([]models_menu.Menu, error) {
+	tree, err := loadMenuTree(db, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := services_inventory.LoadStockState(db, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := LoadPricingRules(db, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	menus := ApplyPricing(services_inventory.ApplyStock(copyMenus(tree.Menus), stock), rules, at)
+	markServedMenus(menus, at)
+	return servedMenuTree(menus), nil
+}
+
+// loadMenuTree returns the menus guests see from the cache, loading them on a miss
+// or when a scheduled version is due
+func loadMenuTree(db *gorm.DB, restaurantID string) (menuTree, error) {
+	if cached, ok := cache.MenuTrees.Get(restaurantID); ok {
+		tree := cached.(menuTree)
+		if tree.NextPublishAt == nil || time.Now().Before(*tree.NextPublishAt) {
+			return tree, nil
+		}
+	}
+
+	if err := publishDueVersions(db, restaurantID); err != nil {
+		return menuTree{}, err
+	}
+
+	var tree menuTree
+
+	var version models_menu.MenuVersion
+	err := db.Where("restaurant_id = ? AND status = ?", restaurantID, models_menu.MenuVersionPublished).First(&version).Error
+	switch {
+	case err == nil:
+		tree.Menus = version.Menus
+		tree.VersionID = &version.ID
+		if version.PublishedAt != nil {
+			tree.LastModified = *version.PublishedAt
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		menus, err := loadLiveMenus(db, restaurantID)
+		if err != nil {
+			return menuTree{}, err
+		}
+		tree.Menus = menus
+		tree.LastModified = menusLastModified(menus)
+	default:
+		return menuTree{}, err
+	}
+
+	// Outlets change the prices and availability of the menus their brand shares
+	if err := applyOutletOverrides(db, restaurantID, tree.Menus); err != nil {
+		return menuTree{}, err
+	}
+
+	var next models_menu.MenuVersion
+	if err := db.Select("publish_at").
+		Where("restaurant_id = ? AND status = ?", restaurantID, models_menu.MenuVersionScheduled).
+		Order("publish_at").
+		Limit(1).
+		Find(&next).Error; err != nil {
+		return menuTree{}, err
+	}
+	tree.NextPublishAt = next.PublishAt
+
+	cache.MenuTrees.Set(restaurantID, tree)
+	return tree, nil
+}
+
+// loadLiveMenus loads the menus of a restaurant as staff edit them, with everything guests can order,
+// including the menus shared by its brand
+func loadLiveMenus(db *gorm.DB, restaurantID string) ([]models_menu.Menu, error) {
+	var menus []models_menu.Menu
+	err := db.
+		Preload("Schedules").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Order("menu_categories.position, menu_categories.created_at") }).
+		Preload("Categories.Schedules").
+		Preload("Categories.MenuItems", func(db *gorm.DB) *gorm.DB { return db.Order("menu_items.position, menu_items.created_at") }).
+		Preload("Categories.MenuItems.ItemOptions", func(db *gorm.DB) *gorm.DB { return db.Order("menu_item_options.position, menu_item_options.price") }).
+		Preload("Categories.MenuItems.ModifierGroups", func(db *gorm.DB) *gorm.DB { return db.Order("modifier_groups.created_at") }).
+		Preload("Categories.MenuItems.ModifierGroups.Modifiers", func(db *gorm.DB) *gorm.DB { return db.Order("modifiers.created_at") }).
+		Preload("Combos", func(db *gorm.DB) *gorm.DB { return db.Order("combos.created_at") }).
+		Preload("Combos.Slots", func(db *gorm.DB) *gorm.DB { return db.Order("combo_slots.position") }).
+		Preload("Combos.Slots.Items.MenuItem").
+		Scopes(func(db *gorm.DB) *gorm.DB { return outletMenus(db, restaurantID) }).
+		Order("created_at").
+		Find(&menus).Error
+	return menus, err
+}
+
+// menusLastModified returns the latest update of anything in the menus
+func menusLastModified(menus []models_menu.Menu) time.Time {
+	var lastModified time.Time
+	touch := func(t time.Time) {
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+	for _, menu := range menus {
+		touch(menu.UpdatedAt)
+		for _, schedule := range menu.Schedules {
+			touch(schedule.UpdatedAt)
+		}
+		for _, category := range menu.Categories {
+			touch(category.UpdatedAt)
+			for _, schedule := range category.Schedules {
+				touch(schedule.UpdatedAt)
+			}
+			for _, item := range category.MenuItems {
+				touch(item.UpdatedAt)
+				for _, option := range item.ItemOptions {
+					touch(option.UpdatedAt)
+				}
+				for _, group := range item.ModifierGroups {
+					touch(group.UpdatedAt)
+					for _, modifier := range group.Modifiers {
+						touch(modifier.UpdatedAt)
+					}
+				}
+			}
+		}
+		for _, combo := range menu.Combos {
+			touch(combo.UpdatedAt)
+			for _, slot := range combo.Slots {
+				touch(slot.UpdatedAt)
+			}
+		}
+	}
+	return lastModified
+}
+
+// copyMenus copies the menus and their categories so they can be marked and filtered
+// without touching the cached tree
+func copyMenus(menus []models_menu.Menu) []models_menu.Menu {
+	copied := make([]models_menu.Menu, len(menus))
+	copy(copied, menus)
+	for i := range copied {
+		copied[i].Categories = append([]models_menu.MenuCategory(nil), copied[i].Categories...)
+	}
+	return copied
+}
+
+// servedMenuTree keeps the served menus and categories and the available items
+func servedMenuTree(menus []models_menu.Menu) []models_menu.Menu {
+	served := []models_menu.Menu{}
+	for _, menu := range menus {
+		if !menu.IsActive {
+			continue
+		}
+
+		categories := []models_menu.MenuCategory{}
+		for _, category := range servedCategories(menu.Categories) {
+			items := []models_menu.MenuItem{}
+			for _, item := range category.MenuItems {
+				if item.IsAvailable {
+					items = append(items, item)
+				}
+			}
+			category.MenuItems = items
+			categories = append(categories, category)
+		}
+
+		menu.Categories = categories
+		menu.Combos = servedCombos(menu.Combos, categories)
+		served = append(served, menu)
+	}
+	return served
+}
+
+// servedCombos keeps the available combos listed under a served category, or under none,
+// with only the available items of each slot. A combo with an empty slot cannot be ordered and is dropped.
+func servedCombos(combos []models_menu.Combo, categories []models_menu.MenuCategory) []models_menu.Combo {
+	servedCategory := make(map[uuid.UUID]bool)
+	for _, category := range categories {
+		servedCategory[category.ID] = true
+	}
+
+	served := []models_menu.Combo{}
+	for _, combo := range combos {
+		if !combo.IsAvailable || (combo.CategoryID != nil && !servedCategory[*combo.CategoryID]) {
+			continue
+		}
+
+		slots := []models_menu.ComboSlot{}
+		for _, slot := range combo.Slots {
+			items := []models_menu.ComboSlotItem{}
+			for _, item := range slot.Items {
+				if item.MenuItem != nil && item.MenuItem.IsAvailable {
+					items = append(items, item)
+				}
+			}
+			if len(items) == 0 {
+				break
+			}
+			slot.Items = items
+			slots = append(slots, slot)
+		}
+		if len(slots) != len(combo.Slots) {
+			continue
+		}
+
+		combo.Slots = slots
+		served = append(served, combo)
+	}
+	return served
+}
@@ -0,0 +1,584 @@
+package services_menu
+
+import (
+	postgres "dine-server/src/config/database"
+	models_menu "dine-server/src/models/menu"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateMenuVersion saves the current menus as a new version, optionally publishing or scheduling it
+// @Summary Create a menu version
+// @Description Save a snapshot of every menu of the restaurant as a draft. Set publish to make it visible to guests now, or publish_at to schedule it.
+// @Tags Menu Versions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param version body models_menu.CreateMenuVersionData true "Version data"
+// @Router /api/v1/{restaurant_id}/menu-versions [post]
+func CreateMenuVersion(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.CreateMenuVersionData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.PublishAt != nil && !input.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at must be in the future"})
+		return
+	}
+
+	menus, err := loadLiveMenus(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+		return
+	}
+
+	// Drafts may hold anything, pure veg restaurants cannot publish non-veg items
+	if input.Publish || input.PublishAt != nil {
+		if nonVeg, err := pureVegViolations(postgres.DB, restaurantID, menus); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant"})
+			return
+		} else if len(nonVeg) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot publish non-veg items", "items": nonVeg})
+			return
+		}
+	}
+
+	version := models_menu.MenuVersion{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: uuid.FromStringOrNil(restaurantID),
+		Status:       models_menu.MenuVersionDraft,
+		Note:         input.Note,
+		Menus:        menus,
+		CreatedBy:    currentUserID(c),
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := createMenuVersion(tx, &version); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu version"})
+		return
+	}
+
+	switch {
+	case input.PublishAt != nil:
+		err = scheduleMenuVersion(tx, &version, *input.PublishAt)
+	case input.Publish:
+		err = publishMenuVersion(tx, &version, time.Now())
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish menu version"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	version.Menus = nil
+	c.JSON(http.StatusCreated, gin.H{"message": "Menu Version Created Successfully", "version": version})
+}
+
+// GetMenuVersions lists the menu versions of a restaurant, newest first
+// @Summary List menu versions
+// @Description List the menu versions of a restaurant without their content, newest first
+// @Tags Menu Versions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/menu-versions [get]
+func GetMenuVersions(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	if err := publishDueVersions(postgres.DB, restaurantID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish scheduled versions"})
+		return
+	}
+
+	var versions []models_menu.MenuVersion
+	if err := postgres.DB.Omit("menus").Where("restaurant_id = ?", restaurantID).Order("number DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Versions Found Successfully", "versions": versions})
+}
+
+// GetMenuVersion retrieves a menu version with its menus
+// @Summary Get a menu version
+// @Description Get a menu version with the menus it contains
+// @Tags Menu Versions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param version_id path string true "Version ID"
+// @Router /api/v1/{restaurant_id}/menu-versions/{version_id} [get]
+func GetMenuVersion(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var version models_menu.MenuVersion
+	if err := postgres.DB.First(&version, "id = ? AND restaurant_id = ?", c.Param("version_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Version Found Successfully", "version": version})
+}
+
+// PublishMenuVersion publishes a draft version now or schedules it
+// @Summary Publish a menu version
+// @Description Make a draft or scheduled version visible to guests now, or schedule it with publish_at
+// @Tags Menu Versions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param version_id path string true "Version ID"
+// @Param publish body models_menu.PublishMenuVersionData false "Publish data"
+// @Router /api/v1/{restaurant_id}/menu-versions/{version_id}/publish [post]
+func PublishMenuVersion(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.PublishMenuVersionData
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if input.PublishAt != nil && !input.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at must be in the future"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+
+	var version models_menu.MenuVersion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&version, "id = ? AND restaurant_id = ?", c.Param("version_id"), restaurantID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu version not found"})
+		return
+	}
+	if version.Status != models_menu.MenuVersionDraft && version.Status != models_menu.MenuVersionScheduled {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft and scheduled versions can be published, use rollback for older versions"})
+		return
+	}
+	if nonVeg, err := pureVegViolations(tx, restaurantID, version.Menus); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant"})
+		return
+	} else if len(nonVeg) > 0 {
+		tx.Rollback()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot publish non-veg items", "items": nonVeg})
+		return
+	}
+
+	var err error
+	if input.PublishAt != nil {
+		err = scheduleMenuVersion(tx, &version, *input.PublishAt)
+	} else {
+		err = publishMenuVersion(tx, &version, time.Now())
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish menu version"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	version.Menus = nil
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Version Published Successfully", "version": version})
+}
+
+// UnscheduleMenuVersion cancels the scheduled publication of a version
+// @Summary Cancel a scheduled publication
+// @Description Turn a scheduled version back into a draft
+// @Tags Menu Versions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param version_id path string true "Version ID"
+// @Router /api/v1/{restaurant_id}/menu-versions/{version_id}/schedule [delete]
+func UnscheduleMenuVersion(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Model(&models_menu.MenuVersion{}).
+		Where("id = ? AND restaurant_id = ? AND status = ?", c.Param("version_id"), restaurantID, models_menu.MenuVersionScheduled).
+		Updates(map[string]interface{}{"status": models_menu.MenuVersionDraft, "publish_at": nil})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel the scheduled publication"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled menu version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Version Unscheduled Successfully"})
+}
+
+// RollbackMenuVersion publishes the content of a previously published version again
+// @Summary Roll back to a menu version
+// @Description Publish the content of a previously published version as a new version. The menus staff edit are left unchanged.
+// @Tags Menu Versions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param version_id path string true "Version ID"
+// @Param rollback body models_menu.RollbackMenuVersionData false "Rollback data"
+// @Router /api/v1/{restaurant_id}/menu-versions/{version_id}/rollback [post]
+func RollbackMenuVersion(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_menu.RollbackMenuVersionData
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var previous models_menu.MenuVersion
+	if err := postgres.DB.First(&previous, "id = ? AND restaurant_id = ?", c.Param("version_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu version not found"})
+		return
+	}
+	if previous.PublishedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only previously published versions can be rolled back to"})
+		return
+	}
+	if previous.Status == models_menu.MenuVersionPublished {
+		c.JSON(http.StatusConflict, gin.H{"error": "This version is already published"})
+		return
+	}
+	if nonVeg, err := pureVegViolations(postgres.DB, restaurantID, previous.Menus); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant"})
+		return
+	} else if len(nonVeg) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "A pure veg restaurant cannot publish non-veg items", "items": nonVeg})
+		return
+	}
+
+	version := models_menu.MenuVersion{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: previous.RestaurantID,
+		Status:       models_menu.MenuVersionDraft,
+		Note:         input.Note,
+		Menus:        previous.Menus,
+		CreatedBy:    currentUserID(c),
+	}
+	if version.Note == "" {
+		version.Note = fmt.Sprintf("Rollback to version %d", previous.Number)
+	}
+
+	tx := postgres.DB.Begin()
+
+	if err := createMenuVersion(tx, &version); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu version"})
+		return
+	}
+	if err := publishMenuVersion(tx, &version, time.Now()); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish menu version"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	version.Menus = nil
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Version Rolled Back Successfully", "version": version})
+}
+
+// DiffMenuVersions compares two menu versions
+// @Summary Compare menu versions
+// @Description List what was added, removed and changed between two versions. Each side is a version ID, "draft" for the menus being edited or "published".
+// @Tags Menu Versions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param from query string false "Version to compare from, defaults to published"
+// @Param to query string false "Version to compare to, defaults to draft"
+// @Router /api/v1/{restaurant_id}/menu-versions/diff [get]
+func DiffMenuVersions(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	from, err := versionMenus(postgres.DB, restaurantID, c.DefaultQuery("from", "published"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := versionMenus(postgres.DB, restaurantID, c.DefaultQuery("to", "draft"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Menu Versions Compared Successfully", "changes": diffMenus(from, to)})
+}
+
+// createMenuVersion numbers a new version after the latest one of the restaurant and saves it
+func createMenuVersion(tx *gorm.DB, version *models_menu.MenuVersion) error {
+	var latest int
+	if err := tx.Model(&models_menu.MenuVersion{}).
+		Where("restaurant_id = ?", version.RestaurantID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+	version.Number = latest + 1
+	return tx.Create(version).Error
+}
+
+// publishMenuVersion makes a version the one guests see, archiving the previous one
+func publishMenuVersion(tx *gorm.DB, version *models_menu.MenuVersion, at time.Time) error {
+	if err := tx.Model(&models_menu.MenuVersion{}).
+		Where("restaurant_id = ? AND status = ? AND id <> ?", version.RestaurantID, models_menu.MenuVersionPublished, version.ID).
+		Update("status", models_menu.MenuVersionArchived).Error; err != nil {
+		return err
+	}
+
+	version.Status = models_menu.MenuVersionPublished
+	version.PublishAt = nil
+	version.PublishedAt = &at
+	return tx.Model(&models_menu.MenuVersion{}).Where("id = ?", version.ID).
+		Updates(map[string]interface{}{"status": version.Status, "publish_at": nil, "published_at": at}).Error
+}
+
+// scheduleMenuVersion sets a version to be published at the given time
+func scheduleMenuVersion(tx *gorm.DB, version *models_menu.MenuVersion, at time.Time) error {
+	version.Status = models_menu.MenuVersionScheduled
+	version.PublishAt = &at
+	return tx.Model(&models_menu.MenuVersion{}).Where("id = ?", version.ID).
+		Updates(map[string]interface{}{"status": version.Status, "publish_at": at}).Error
+}
+
+// publishDueVersions publishes the scheduled versions whose time has come.
+// When several are due, the latest one wins and the others are archived.
+func publishDueVersions(db *gorm.DB, restaurantID string) error {
+	var due []models_menu.MenuVersion
+	if err := db.Omit("menus").
+		Where("restaurant_id = ? AND status = ? AND publish_at <= ?", restaurantID, models_menu.MenuVersionScheduled, time.Now()).
+		Order("publish_at").
+		Find(&due).Error; err != nil || len(due) == 0 {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for i := range due {
+			if err := publishMenuVersion(tx, &due[i], *due[i].PublishAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// versionMenus returns the menus of a version ID, of the draft or of the published version
+func versionMenus(db *gorm.DB, restaurantID, reference string) ([]models_menu.Menu, error) {
+	switch reference {
+	case "draft":
+		return loadLiveMenus(db, restaurantID)
+	case "published":
+		if err := publishDueVersions(db, restaurantID); err != nil {
+			return nil, err
+		}
+		var version models_menu.MenuVersion
+		if err := db.First(&version, "restaurant_id = ? AND status = ?", restaurantID, models_menu.MenuVersionPublished).Error; err != nil {
+			return nil, fmt.Errorf("no version is published")
+		}
+		return version.Menus, nil
+	}
+
+	var version models_menu.MenuVersion
+	if err := db.First(&version, "id = ? AND restaurant_id = ?", reference, restaurantID).Error; err != nil {
+		return nil, fmt.Errorf("menu version %s not found", reference)
+	}
+	return version.Menus, nil
+}
+
+// currentUserID returns the ID of the authenticated user, if any
+func currentUserID(c *gin.Context) *uuid.UUID {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return nil
+	}
+	id, err := uuid.FromString(fmt.Sprint(userID))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// menuEntity is the comparable content of one record of a menu
+type menuEntity struct {
+	Type   string
+	Name   string
+	Fields map[string]interface{}
+}
+
+// flattenMenus lists every record of the menus by ID, in menu order
+func flattenMenus(menus []models_menu.Menu) ([]uuid.UUID, map[uuid.UUID]menuEntity) {
+	var order []uuid.UUID
+	entities := make(map[uuid.UUID]menuEntity)
+	add := func(id uuid.UUID, entity menuEntity) {
+		order = append(order, id)
+		entities[id] = entity
+	}
+	text := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	encode := func(value interface{}) string {
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+	schedules := func(schedules []models_menu.MenuSchedule) string {
+		var data []models_menu.MenuScheduleData
+		for _, schedule := range schedules {
+			data = append(data, models_menu.MenuScheduleData{Days: schedule.Days, StartTime: schedule.StartTime, EndTime: schedule.EndTime, StartDate: schedule.StartDate, EndDate: schedule.EndDate})
+		}
+		return encode(data)
+	}
+
+	for _, menu := range menus {
+		add(menu.ID, menuEntity{Type: "menu", Name: menu.Name, Fields: map[string]interface{}{
+			"name":      menu.Name,
+			"schedules": schedules(menu.Schedules),
+		}})
+		for _, category := range menu.Categories {
+			add(category.ID, menuEntity{Type: "category", Name: category.Name, Fields: map[string]interface{}{
+				"name":        category.Name,
+				"description": text(category.Description),
+				"image_url":   text(category.ImageURL),
+				"schedules":   schedules(category.Schedules),
+				"position":    category.Position,
+			}})
+			for _, item := range category.MenuItems {
+				add(item.ID, menuEntity{Type: "item", Name: item.Name, Fields: map[string]interface{}{
+					"name":          item.Name,
+					"category":      category.Name,
+					"description":   text(item.Description),
+					"image_url":     text(item.ImageURL),
+					"is_vegetarian": item.IsVegetarian,
+					"is_available":  item.IsAvailable,
+					"diets":         strings.Join(item.Diets, ", "),
+					"allergens":     strings.Join(item.Allergens, ", "),
+					"spice_level":   item.SpiceLevel,
+					"nutrition":     encode(item.Nutrition),
+					"position":      item.Position,
+				}})
+				for _, option := range item.ItemOptions {
+					add(option.ID, menuEntity{Type: "option", Name: item.Name + " - " + option.Name, Fields: map[string]interface{}{
+						"name":     option.Name,
+						"price":    option.Price,
+						"position": option.Position,
+					}})
+				}
+				for _, group := range item.ModifierGroups {
+					add(group.ID, menuEntity{Type: "modifier_group", Name: item.Name + " - " + group.Name, Fields: map[string]interface{}{
+						"name":       group.Name,
+						"min_select": group.MinSelect,
+						"max_select": group.MaxSelect,
+					}})
+					for _, modifier := range group.Modifiers {
+						add(modifier.ID, menuEntity{Type: "modifier", Name: group.Name + " - " + modifier.Name, Fields: map[string]interface{}{
+							"name":         modifier.Name,
+							"price":        modifier.Price,
+							"is_available": modifier.IsAvailable,
+						}})
+					}
+				}
+			}
+		}
+		for _, combo := range menu.Combos {
+			var slots []models_menu.AddComboSlotData
+			for _, slot := range combo.Slots {
+				slotData := models_menu.AddComboSlotData{Name: slot.Name}
+				for _, item := range slot.Items {
+					slotData.Items = append(slotData.Items, models_menu.AddComboSlotItemData{MenuItemID: item.MenuItemID, ItemOptionID: item.ItemOptionID, Upcharge: item.Upcharge, IsDefault: item.IsDefault})
+				}
+				slots = append(slots, slotData)
+			}
+			add(combo.ID, menuEntity{Type: "combo", Name: combo.Name, Fields: map[string]interface{}{
+				"name":         combo.Name,
+				"description":  text(combo.Description),
+				"price":        combo.Price,
+				"is_available": combo.IsAvailable,
+				"slots":        encode(slots),
+			}})
+		}
+	}
+	return order, entities
+}
+
+// diffMenus lists the records added, removed and changed between two sets of menus
+func diffMenus(from, to []models_menu.Menu) []models_menu.MenuChange {
+	fromOrder, fromEntities := flattenMenus(from)
+	toOrder, toEntities := flattenMenus(to)
+
+	changes := []models_menu.MenuChange{}
+	for _, id := range toOrder {
+		after := toEntities[id]
+		before, existed := fromEntities[id]
+		if !existed {
+			changes = append(changes, models_menu.MenuChange{Type: after.Type, ID: id, Name: after.Name, Change: "added"})
+			continue
+		}
+
+		var fields []models_menu.MenuFieldChange
+		for _, field := range sortedFields(after.Fields) {
+			if !reflect.DeepEqual(before.Fields[field], after.Fields[field]) {
+				fields = append(fields, models_menu.MenuFieldChange{Field: field, From: before.Fields[field], To: after.Fields[field]})
+			}
+		}
+		if len(fields) > 0 {
+			changes = append(changes, models_menu.MenuChange{Type: after.Type, ID: id, Name: after.Name, Change: "changed", Fields: fields})
+		}
+	}
+	for _, id := range fromOrder {
+		if _, kept := toEntities[id]; !kept {
+			before := fromEntities[id]
+			changes = append(changes, models_menu.MenuChange{Type: before.Type, ID: id, Name: before.Name, Change: "removed"})
+		}
+	}
+	return changes
+}
+
+// sortedFields returns the field names of an entity in a stable order
+func sortedFields(fields map[string]interface{}) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,113 @@
+package services_orders
+
+import (
+	services_inventory "dine-server/src/api/v1/services/inventory"
+	services_menu "dine-server/src/api/v1/services/menus"
+	models_menu "dine-server/src/models/menu"
+	"dine-server/src/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// orderCatalog indexes the published menus of a restaurant, orders are priced against it
+type orderCatalog struct {
+	VersionID  *uuid.UUID
+	menus      map[uuid.UUID]*models_menu.Menu
+	categories map[uuid.UUID]*models_menu.MenuCategory
+	items      map[uuid.UUID]*models_menu.MenuItem
+	combos     map[uuid.UUID]*models_menu.Combo
+	stock      services_inventory.StockState // What ran out, the menus are shared and left as published
+	pricing    []models_menu.PricingRule     // Happy hours and surcharges, applied when an item is priced
+}
+
+// loadOrderCatalog indexes the menus guests currently order from
+func loadOrderCatalog(db *gorm.DB, restaurantID uuid.UUID) (orderCatalog, error) {
+	menus, versionID, err := services_menu.PublishedMenus(db, restaurantID.String())
+	if err != nil {
+		return orderCatalog{}, fmt.Errorf("failed to fetch menus")
+	}
+	stock, err := services_inventory.LoadStockState(db, restaurantID.String())
+	if err != nil {
+		return orderCatalog{}, fmt.Errorf("failed to fetch stock")
+	}
+	pricing, err := services_menu.LoadPricingRules(db, restaurantID.String())
+	if err != nil {
+		return orderCatalog{}, fmt.Errorf("failed to fetch pricing rules")
+	}
+
+	catalog := orderCatalog{
+		VersionID:  versionID,
+		menus:      make(map[uuid.UUID]*models_menu.Menu),
+		categories: make(map[uuid.UUID]*models_menu.MenuCategory),
+		items:      make(map[uuid.UUID]*models_menu.MenuItem),
+		combos:     make(map[uuid.UUID]*models_menu.Combo),
+		stock:      stock,
+		pricing:    pricing,
+	}
+	for i := range menus {
+		menu := &menus[i]
+		catalog.menus[menu.ID] = menu
+		for j := range menu.Categories {
+			category := &menu.Categories[j]
+			catalog.categories[category.ID] = category
+			for k := range category.MenuItems {
+				catalog.items[category.MenuItems[k].ID] = &category.MenuItems[k]
+			}
+		}
+		for j := range menu.Combos {
+			catalog.combos[menu.Combos[j].ID] = &menu.Combos[j]
+		}
+	}
+	return catalog, nil
+}
+
+// available reports whether an item can be ordered: available on the menu, in stock and not 86'd
+func (catalog orderCatalog) available(item *models_menu.MenuItem) bool {
+	return item.IsAvailable && catalog.stock.ItemAvailable(item.ID)
+}
+
+// servedAt reports whether a menu, and the category when given, are both served at t
+func (catalog orderCatalog) servedAt(menuID uuid.UUID, categoryID *uuid.UUID, t time.Time) bool {
+	menu, ok := catalog.menus[menuID]
+	if !ok || !utils.MenuServedAt(menu.Schedules, t) {
+		return false
+	}
+	if categoryID == nil {
+		return true
+	}
+	category, ok := catalog.categories[*categoryID]
+	return ok && utils.MenuServedAt(category.Schedules, t)
+}
+
+// option returns an option of an item in stock, or its cheapest option in stock when no ID is given
+func (catalog orderCatalog) option(item *models_menu.MenuItem, optionID *uuid.UUID) (models_menu.MenuItemOption, bool) {
+	var cheapest *models_menu.MenuItemOption
+	for i := range item.ItemOptions {
+		option := &item.ItemOptions[i]
+		if !catalog.stock.OptionAvailable(option.ID) {
+			continue
+		}
+		if optionID != nil && option.ID == *optionID {
+			return *option, true
+		}
+		if cheapest == nil || option.Price < cheapest.Price {
+			cheapest = option
+		}
+	}
+	if optionID == nil && cheapest != nil {
+		return *cheapest, true
+	}
+	return models_menu.MenuItemOption{}, false
+}
+
+// price returns what an option of an item costs at t with the pricing rule applying, if any
+func (catalog orderCatalog) price(item *models_menu.MenuItem, option models_menu.MenuItemOption, t time.Time) (float64, *models_menu.PricingRule) {
+	rule := utils.PricingRuleFor(catalog.pricing, item.ID, item.CategoryID, t)
+	if rule == nil {
+		return option.Price, nil
+	}
+	return utils.AdjustPrice(option.Price, *rule), rule
+}
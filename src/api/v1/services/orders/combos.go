@@ -0,0 +1,153 @@
+package services_orders
+
+import (
+	models_menu "dine-server/src/models/menu"
+	models_order "dine-server/src/models/orders"
+	"dine-server/src/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// resolveCombo validates an ordered combo and explodes it into order items.
+// The bundle price, upcharges and modifiers are shared between the items in proportion
+// to their regular price, so the items always add up to the price of the combo.
+func resolveCombo(catalog orderCatalog, orderID uuid.UUID, input models_order.CreateOrderCombo, at time.Time) (models_order.OrderCombo, []models_order.OrderItem, error) {
+	combo, ok := catalog.combos[input.ComboID]
+	if !ok {
+		return models_order.OrderCombo{}, nil, fmt.Errorf("combo not found")
+	}
+	if !combo.IsAvailable {
+		return models_order.OrderCombo{}, nil, fmt.Errorf("%s is not available", combo.Name)
+	}
+
+	// The combo follows the schedules of its menu and of the category it is listed under
+	if !catalog.servedAt(combo.MenuID, combo.CategoryID, at) {
+		return models_order.OrderCombo{}, nil, fmt.Errorf("%s is not served at this time", combo.Name)
+	}
+
+	selections := make(map[uuid.UUID]models_order.CreateComboSelection)
+	for _, selection := range input.Selections {
+		if _, ok := selections[selection.SlotID]; ok {
+			return models_order.OrderCombo{}, nil, fmt.Errorf("slot %s of %s is selected more than once", selection.SlotID, combo.Name)
+		}
+		selections[selection.SlotID] = selection
+	}
+
+	orderComboID := uuid.Must(uuid.NewV4())
+	unitPrice := combo.Price
+	var items []models_order.OrderItem
+	var weights []float64
+	for _, slot := range combo.Slots {
+		selection, chosen := selections[slot.ID]
+		delete(selections, slot.ID)
+
+		slotItem, err := comboSlotItem(slot, selection, chosen)
+		if err != nil {
+			return models_order.OrderCombo{}, nil, fmt.Errorf("%s of %s: %s", slot.Name, combo.Name, err.Error())
+		}
+		menuItem, ok := catalog.items[slotItem.MenuItemID]
+		if !ok || !catalog.available(menuItem) {
+			return models_order.OrderCombo{}, nil, fmt.Errorf("%s of %s is not available", slot.Name, combo.Name)
+		}
+
+		// Combos serve the configured option of the item, or its cheapest one
+		itemOption, ok := catalog.option(menuItem, slotItem.ItemOptionID)
+		if !ok {
+			return models_order.OrderCombo{}, nil, fmt.Errorf("%s has no option to serve in %s", menuItem.Name, combo.Name)
+		}
+
+		orderItemID := uuid.Must(uuid.NewV4())
+		modifiers, modifiersPrice, err := resolveModifiers(*menuItem, orderItemID, selection.ModifierIDs)
+		if err != nil {
+			return models_order.OrderCombo{}, nil, err
+		}
+
+		unitPrice += slotItem.Upcharge + modifiersPrice
+		weights = append(weights, itemOption.Price+modifiersPrice)
+		items = append(items, models_order.OrderItem{
+			ID:             orderItemID,
+			OrderID:        orderID,
+			MenuItemID:     menuItem.ID,
+			MenuName:       menuItem.Name,
+			Quantity:       input.Quantity,
+			ItemOptionID:   itemOption.ID,
+			ItemOptionName: itemOption.Name,
+			Modifiers:      modifiers,
+			OrderComboID:   &orderComboID,
+		})
+	}
+
+	for slotID := range selections {
+		return models_order.OrderCombo{}, nil, fmt.Errorf("slot %s does not belong to %s", slotID, combo.Name)
+	}
+	if len(items) == 0 {
+		return models_order.OrderCombo{}, nil, fmt.Errorf("%s has no items", combo.Name)
+	}
+
+	unitPrice = utils.RoundAmount(unitPrice)
+	for i, share := range comboShares(unitPrice, weights) {
+		items[i].Price = share
+		items[i].Subtotal = utils.RoundAmount(share * float64(input.Quantity))
+	}
+
+	orderCombo := models_order.OrderCombo{
+		ID:       orderComboID,
+		OrderID:  orderID,
+		ComboID:  combo.ID,
+		Name:     combo.Name,
+		Quantity: input.Quantity,
+		Price:    unitPrice,
+		Subtotal: utils.RoundAmount(unitPrice * float64(input.Quantity)),
+	}
+
+	return orderCombo, items, nil
+}
+
+// comboSlotItem returns the item filling a slot: the selected one, or the default when nothing was selected
+func comboSlotItem(slot models_menu.ComboSlot, selection models_order.CreateComboSelection, chosen bool) (models_menu.ComboSlotItem, error) {
+	if chosen {
+		for _, item := range slot.Items {
+			if item.MenuItemID == selection.MenuItemID {
+				return item, nil
+			}
+		}
+		return models_menu.ComboSlotItem{}, fmt.Errorf("item %s cannot be chosen", selection.MenuItemID)
+	}
+
+	if len(slot.Items) == 1 {
+		return slot.Items[0], nil
+	}
+	for _, item := range slot.Items {
+		if item.IsDefault {
+			return item, nil
+		}
+	}
+	return models_menu.ComboSlotItem{}, fmt.Errorf("an item must be chosen")
+}
+
+// comboShares splits the price of one combo between its items by weight.
+// Rounding differences go to the last item so the shares add up to the price.
+func comboShares(price float64, weights []float64) []float64 {
+	var totalWeight float64
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	shares := make([]float64, len(weights))
+	var allocated float64
+	for i, weight := range weights {
+		if i == len(weights)-1 {
+			shares[i] = utils.RoundAmount(price - allocated)
+			break
+		}
+		if totalWeight > 0 {
+			shares[i] = utils.RoundAmount(price * weight / totalWeight)
+		} else {
+			shares[i] = utils.RoundAmount(price / float64(len(weights)))
+		}
+		allocated += shares[i]
+	}
+	return shares
+}
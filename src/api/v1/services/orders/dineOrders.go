@@ -35,16 +35,35 @@ func CreateDineOrder(c *gin.Context) error {
 
 	}
 
-	var Restaurant models_restaurant.Restaurant
-	if err := postgres.DB.Where("id = ? AND admin_id = ?", input.RestaurantID, restaurant_admin_id).First(&Restaurant).Error; err != nil {
-		return err
-	}
-
 	var Plan models_plan.Plan
 	if err := postgres.DB.First(&Plan, "id = ?", input.PlanID).Error; err != nil {
 		return fmt.Errorf("plan not found")
 	}
 
+	// A brand plan covers every outlet of the brand, the plan must allow that many
+	var AdminID uuid.UUID
+	if input.BrandID != nil {
+		var Brand models_restaurant.Brand
+		if err := postgres.DB.Where("id = ? AND admin_id = ?", input.BrandID, restaurant_admin_id).First(&Brand).Error; err != nil {
+			return fmt.Errorf("brand not found")
+		}
+		var outlets int64
+		if err := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("brand_id = ?", Brand.ID).Count(&outlets).Error; err != nil {
+			return fmt.Errorf("failed to count brand outlets")
+		}
+		if int(outlets) > Plan.MaxOutlets {
+			return fmt.Errorf("the plan covers %d outlets but the brand has %d", Plan.MaxOutlets, outlets)
+		}
+		AdminID = Brand.AdminID
+		input.RestaurantID = nil
+	} else {
+		var Restaurant models_restaurant.Restaurant
+		if err := postgres.DB.Where("id = ? AND admin_id = ?", input.RestaurantID, restaurant_admin_id).First(&Restaurant).Error; err != nil {
+			return err
+		}
+		AdminID = Restaurant.AdminID
+	}
+
 	var PromoCode models_promoCode.DinePromoCode
 	var DiscountAmount float64
 	if input.PromoCode != "" {
@@ -100,10 +119,11 @@ func CreateDineOrder(c *gin.Context) error {
 
 	var DineOrder = models_order.DineOrder{
 		RestaurantID:      input.RestaurantID,
+		BrandID:           input.BrandID,
 		PlanID:            input.PlanID,
 		PromoCode:         input.PromoCode,
 		Duration:          input.Duration,
-		RestaurantAdminID: Restaurant.AdminID,
+		RestaurantAdminID: AdminID,
 		Amount:            Plan.Price,
 		DiscountAmount:    DiscountAmount,
 		Status:            "pending",
@@ -0,0 +1,178 @@
+package services_orders
+
+import (
+	"crypto/hmac"
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/sms"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	guestOTPAge         = 5 * time.Minute
+	guestOTPMaxAttempts = 5
+	guestTokenAge       = 2 * time.Hour
+)
+
+// Per phone and per table limits, on top of the per IP limits applied to the routes
+var (
+	guestOTPPhoneLimiter = utils.NewRateLimiter(3, 10*time.Minute)
+	guestOrderLimiter    = utils.NewRateLimiter(5, 10*time.Minute)
+)
+
+// SendGuestOTP sends a one-time code to a guest's phone
+// @Summary Send a guest OTP
+// @Description Send a one-time code to the phone of a guest who wants to order without scanning a table QR code
+// @Tags Restaurant Orders
+// @Accept json
+// @Produce json
+// @Param otp body models_order.SendGuestOTPData true "Phone to verify"
+// @Router /api/v1/orders/restaurant/otp/send [post]
+func SendGuestOTP(c *gin.Context) {
+	var input models_order.SendGuestOTPData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "name").First(&restaurant, "id = ?", input.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if ok, retryAfter := guestOTPPhoneLimiter.Allow(input.Phone); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many codes requested for this phone, please try again later"})
+		return
+	}
+
+	code, err := utils.GenerateOTP(6)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code"})
+		return
+	}
+	codeHash, err := utils.HashOTP(code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Only the latest code of a phone is valid
+	if err := tx.Where("restaurant_id = ? AND phone = ? AND verified_at IS NULL", restaurant.ID, input.Phone).
+		Delete(&models_order.GuestVerification{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code"})
+		return
+	}
+
+	verification := models_order.GuestVerification{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: restaurant.ID,
+		Phone:        input.Phone,
+		CodeHash:     codeHash,
+		ExpiresAt:    time.Now().Add(guestOTPAge),
+	}
+	if err := tx.Create(&verification).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code"})
+		return
+	}
+
+	if err := sms.Client.Send(input.Phone, fmt.Sprintf("%s is your %s ordering code. It expires in %d minutes.", code, restaurant.Name, int(guestOTPAge.Minutes()))); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send code"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "OTP sent successfully", "expires_at": verification.ExpiresAt})
+}
+
+// VerifyGuestOTP exchanges a one-time code for a guest token
+// @Summary Verify a guest OTP
+// @Description Verify the code sent to a guest's phone and return the guest token used to place orders
+// @Tags Restaurant Orders
+// @Accept json
+// @Produce json
+// @Param otp body models_order.VerifyGuestOTPData true "Code to verify"
+// @Router /api/v1/orders/restaurant/otp/verify [post]
+func VerifyGuestOTP(c *gin.Context) {
+	var input models_order.VerifyGuestOTPData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var verification models_order.GuestVerification
+	if err := postgres.DB.Where("restaurant_id = ? AND phone = ? AND verified_at IS NULL AND expires_at > ?", input.RestaurantID, input.Phone, time.Now()).
+		Order("created_at DESC").First(&verification).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Code expired or not found, please request a new one"})
+		return
+	}
+
+	// Count the attempt before checking the code, in one statement so parallel guesses cannot pass the limit
+	result := postgres.DB.Model(&models_order.GuestVerification{}).
+		Where("id = ? AND attempts < ?", verification.ID, guestOTPMaxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, please request a new code"})
+		return
+	}
+
+	codeHash, err := utils.HashOTP(input.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+		return
+	}
+
+	if !hmac.Equal([]byte(codeHash), []byte(verification.CodeHash)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	// A code verifies once, even when sent by parallel requests
+	result = postgres.DB.Model(&models_order.GuestVerification{}).
+		Where("id = ? AND verified_at IS NULL", verification.ID).
+		Update("verified_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Code already used, please request a new one"})
+		return
+	}
+
+	guestToken, err := utils.GenerateGuestToken(verification.RestaurantID, verification.Phone, guestTokenAge)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate guest token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Phone verified successfully", "guest_token": guestToken})
+}
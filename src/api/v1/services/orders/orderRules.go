@@ -0,0 +1,161 @@
+package services_orders
+
+import (
+	postgres "dine-server/src/config/database"
+	models_order "dine-server/src/models/orders"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetOrderRules retrieves the auto-reject rules of a restaurant
+// @Summary Get order rules
+// @Description Get the rules used to automatically reject suspicious guest orders
+// @Tags Restaurant Orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/order-rules [get]
+func GetOrderRules(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	rules, err := loadOrderRules(postgres.DB, restaurantUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// UpdateOrderRules updates the auto-reject rules of a restaurant
+// @Summary Update order rules
+// @Description Update the rules used to automatically reject suspicious guest orders. A limit of 0 disables the rule.
+// @Tags Restaurant Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param rules body models_order.UpdateOrderRulesData true "Order rules"
+// @Router /api/v1/{restaurant_id}/order-rules [put]
+func UpdateOrderRules(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var input models_order.UpdateOrderRulesData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, err := loadOrderRules(postgres.DB, restaurantUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order rules"})
+		return
+	}
+
+	if input.MaxOrderTotal != nil {
+		rules.MaxOrderTotal = *input.MaxOrderTotal
+	}
+	if input.MaxItemQuantity != nil {
+		rules.MaxItemQuantity = *input.MaxItemQuantity
+	}
+	if input.MaxOrderItems != nil {
+		rules.MaxOrderItems = *input.MaxOrderItems
+	}
+	if input.MaxOpenOrdersPerPhone != nil {
+		rules.MaxOpenOrdersPerPhone = *input.MaxOpenOrdersPerPhone
+	}
+	if input.OnlinePaymentAbove != nil {
+		rules.OnlinePaymentAbove = *input.OnlinePaymentAbove
+	}
+	if input.BlockedPhones != nil {
+		rules.BlockedPhones = input.BlockedPhones
+	}
+
+	if err := postgres.DB.Save(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order rules updated successfully", "rules": rules})
+}
+
+// loadOrderRules returns the rules of a restaurant, or disabled rules when none are set
+func loadOrderRules(db *gorm.DB, restaurantID uuid.UUID) (models_order.RestaurantOrderRules, error) {
+	var rules models_order.RestaurantOrderRules
+	err := db.Where("restaurant_id = ?", restaurantID).First(&rules).Error
+	if err == gorm.ErrRecordNotFound {
+		return models_order.RestaurantOrderRules{
+			ID:            uuid.Must(uuid.NewV4()),
+			RestaurantID:  restaurantID,
+			BlockedPhones: []string{},
+		}, nil
+	}
+	return rules, err
+}
+
+// checkOrderRules returns why an order breaks the restaurant's rules, or an empty string
+func checkOrderRules(tx *gorm.DB, restaurantID uuid.UUID, input models_order.CreateOrder, total float64) (string, error) {
+	rules, err := loadOrderRules(tx, restaurantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch order rules")
+	}
+
+	for _, phone := range rules.BlockedPhones {
+		if phone == input.CustomerPhone {
+			return "this phone number is not allowed to order", nil
+		}
+	}
+
+	if rules.MaxOrderTotal > 0 && total > rules.MaxOrderTotal {
+		return fmt.Sprintf("order total exceeds %.2f", rules.MaxOrderTotal), nil
+	}
+	if rules.MaxOrderItems > 0 && len(input.Items)+len(input.Combos) > rules.MaxOrderItems {
+		return fmt.Sprintf("order has more than %d items", rules.MaxOrderItems), nil
+	}
+	if rules.MaxItemQuantity > 0 {
+		for _, item := range input.Items {
+			if item.Quantity > rules.MaxItemQuantity {
+				return fmt.Sprintf("quantity of an item exceeds %d", rules.MaxItemQuantity), nil
+			}
+		}
+		for _, combo := range input.Combos {
+			if combo.Quantity > rules.MaxItemQuantity {
+				return fmt.Sprintf("quantity of a combo exceeds %d", rules.MaxItemQuantity), nil
+			}
+		}
+	}
+	if rules.OnlinePaymentAbove > 0 && total > rules.OnlinePaymentAbove && input.PaymentType == "onsite" {
+		return fmt.Sprintf("orders above %.2f must be paid online", rules.OnlinePaymentAbove), nil
+	}
+
+	if rules.MaxOpenOrdersPerPhone > 0 {
+		var openOrders int64
+		if err := tx.Model(&models_order.Order{}).
+			Where("restaurant_id = ? AND customer_phone = ? AND status NOT IN ?", restaurantID, input.CustomerPhone,
+				[]models_order.OrderStatus{models_order.OrderStatusCompleted, models_order.OrderStatusCancelled}).
+			Count(&openOrders).Error; err != nil {
+			return "", fmt.Errorf("failed to count open orders")
+		}
+		if int(openOrders) >= rules.MaxOpenOrdersPerPhone {
+			return fmt.Sprintf("this phone already has %d open orders", openOrders), nil
+		}
+	}
+
+	return "", nil
+}
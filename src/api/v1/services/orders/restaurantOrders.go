@@ -1,15 +1,27 @@
 package services_orders
 
 import (
+	services_inventory "dine-server/src/api/v1/services/inventory"
+	services_kitchen "dine-server/src/api/v1/services/kitchen"
+	services_restaurant "dine-server/src/api/v1/services/restaurants"
 	postgres "dine-server/src/config/database"
+	models_kitchen "dine-server/src/models/kitchen"
 	models_menu "dine-server/src/models/menu"
 	models_order "dine-server/src/models/orders"
+	"dine-server/src/utils"
 
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // @BasePath /api/v1
@@ -30,42 +42,247 @@ func CreateOrder(c *gin.Context) {
 		return
 	}
 
-	// Calculate totals
+	if len(input.Items) == 0 && len(input.Combos) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order must contain at least one item or combo"})
+		return
+	}
+
+	// Guests order either from a table QR code or after verifying their phone.
+	// The restaurant is taken from the token so it cannot be forged.
+	// Orders are limited per verified phone, or per table since the phone given at a table is not verified.
+	var restaurantID uuid.UUID
+	var limitKey string
+	switch {
+	case input.TableToken != "":
+		tokenRestaurantID, tableID, _, err := utils.ParseTableToken(input.TableToken)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid table token"})
+			return
+		}
+		restaurantID = tokenRestaurantID
+		limitKey = "table|" + tableID.String()
+	case input.GuestToken != "":
+		tokenRestaurantID, phone, err := utils.ParseGuestToken(input.GuestToken)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if phone != input.CustomerPhone {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Customer phone does not match the verified phone"})
+			return
+		}
+		restaurantID = tokenRestaurantID
+		limitKey = "phone|" + restaurantID.String() + "|" + phone
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "A table QR code or a verified phone is required to order"})
+		return
+	}
+
+	if input.RestaurantID != uuid.Nil && input.RestaurantID != restaurantID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant does not match the token"})
+		return
+	}
+	input.RestaurantID = restaurantID
+
+	if ok, retryAfter := guestOrderLimiter.Allow(limitKey); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many orders, please try again later"})
+		return
+	}
+
+	// Orders are only accepted within opening hours, outside closures and while not paused
+	_, schedule, err := services_restaurant.RestaurantSchedule(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if now := time.Now(); !schedule.AcceptsOrdersAt(now) {
+		if schedule.Paused {
+			c.JSON(http.StatusConflict, gin.H{"error": "Restaurant is not taking orders right now"})
+		} else {
+			c.JSON(http.StatusConflict, gin.H{"error": "Restaurant is closed", "next_opening_at": schedule.NextOpeningAt(now)})
+		}
+		return
+	}
+
+	// Start a transaction
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	orderID := uuid.Must(uuid.NewV4())
+
+	// Dine-in orders are bound to the table whose QR code was scanned
+	// and added to the running tab of that table
+	var tableID, sessionID *uuid.UUID
+	if models_order.OrderType(input.OrderType) == models_order.OrderTypeDineIn {
+		table, err := services_restaurant.ResolveTableToken(tx, input.RestaurantID, input.TableToken)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tableID = &table.ID
+
+		session, err := OpenSessionForTable(tx, table)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		sessionID = &session.ID
+	} else if input.TableToken != "" {
+		if _, err := services_restaurant.ResolveTableToken(tx, input.RestaurantID, input.TableToken); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Orders are priced against the published menus, not against unpublished edits
+	catalog, err := loadOrderCatalog(postgres.DB, input.RestaurantID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().In(schedule.Location)
+
+	// Resolve the ordered items and calculate totals
 	var subtotal, tax, serviceFee, total float64
+	var orderItems []models_order.OrderItem
 	for _, item := range input.Items {
-		var menuItem models_menu.MenuItem
-		if err := postgres.DB.First(&menuItem, "id = ?", item.MenuItemID).Error; err != nil {
+		// Only items from this restaurant's menus can be ordered
+		menuItem, ok := catalog.items[item.MenuItemID]
+		if !ok {
+			tx.Rollback()
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item not found"})
 			return
 		}
+		if !catalog.available(menuItem) {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": menuItem.Name + " is not available"})
+			return
+		}
 
-		var menuItemOptions []models_menu.MenuItemOption
-		if err := postgres.DB.Where("menu_item_id = ?", item.MenuItemID).Find(&menuItemOptions).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item options not found"})
+		// The item's menu and category must be served right now
+		if !catalog.servedAt(menuItem.MenuID, &menuItem.CategoryID, now) {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": menuItem.Name + " is not served at this time"})
 			return
 		}
 
-		var itemOption models_menu.MenuItemOption
-		for _, option := range menuItemOptions {
-			itemOptionUUID := *item.ItemOptionID
+		if item.ItemOptionID == nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item option is required"})
+			return
+		}
 
-			if option.ID == itemOptionUUID {
-				itemOption = option
-				break
+		itemOption, ok := catalog.option(menuItem, item.ItemOptionID)
+		if !ok {
+			tx.Rollback()
+			if !catalog.stock.OptionAvailable(*item.ItemOptionID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": menuItem.Name + " is not available in this option"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item option not found"})
 			}
+			return
+		}
+
+		orderItemID := uuid.Must(uuid.NewV4())
+
+		// Chosen modifiers must follow the selection rules of the item's modifier groups
+		modifiers, modifiersPrice, err := resolveModifiers(*menuItem, orderItemID, item.ModifierIDs)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		itemTotal := float64(item.Quantity) * itemOption.Price
+		// Happy hours and surcharges change the option price, not the modifiers
+		optionPrice, rule := catalog.price(menuItem, itemOption, now)
+
+		unitPrice := optionPrice + modifiersPrice
+		itemTotal := float64(item.Quantity) * unitPrice
 		subtotal += itemTotal
+
+		orderItem := models_order.OrderItem{
+			ID:             orderItemID,
+			OrderID:        orderID,
+			MenuItemID:     item.MenuItemID,
+			MenuName:       menuItem.Name,
+			Quantity:       item.Quantity,
+			Price:          unitPrice,
+			Subtotal:       itemTotal,
+			ItemOptionID:   itemOption.ID,
+			ItemOptionName: itemOption.Name,
+			Modifiers:      modifiers,
+		}
+		if rule != nil {
+			orderItem.PricingRuleID = &rule.ID
+			orderItem.PricingRule = rule.Name
+			orderItem.PriceChange = utils.RoundAmount(optionPrice - itemOption.Price)
+		}
+		orderItems = append(orderItems, orderItem)
+	}
+
+	// Combos are exploded into their items, priced with their share of the bundle
+	var orderCombos []models_order.OrderCombo
+	for _, item := range input.Combos {
+		orderCombo, comboItems, err := resolveCombo(catalog, orderID, item, now)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for _, comboItem := range comboItems {
+			subtotal += comboItem.Subtotal
+		}
+		orderCombos = append(orderCombos, orderCombo)
+		orderItems = append(orderItems, comboItems...)
+	}
+
+	// Items of the published version may have been deleted since, they cannot be ordered until the next publication
+	if err := checkItemsExist(tx, orderItems); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Take the ordered portions from the counted stock, another order may have taken the last ones
+	if err := services_inventory.ReserveStock(tx, input.RestaurantID, orderID, orderItems); err != nil {
+		tx.Rollback()
+		var outOfStock *services_inventory.OutOfStockError
+		if errors.As(err, &outOfStock) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
 	}
 
 	tax = subtotal * 0.1         // Example: 10% tax
 	serviceFee = subtotal * 0.05 // Example: 5% service fee
 	total = subtotal + tax + serviceFee
 
+	// Reject orders breaking the restaurant's rules before anything is written
+	reason, err := checkOrderRules(tx, input.RestaurantID, input, total)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if reason != "" {
+		tx.Rollback()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Order rejected: " + reason})
+		return
+	}
+
 	// Create the Order
 	order := models_order.Order{
-		ID:            uuid.Must(uuid.NewV4()),
+		ID:            orderID,
 		RestaurantID:  input.RestaurantID,
 		CustomerEmail: input.CustomerEmail,
 		CustomerName:  input.CustomerName,
@@ -73,6 +290,9 @@ func CreateOrder(c *gin.Context) {
 		PaymentType:   input.PaymentType,
 		Status:        models_order.OrderStatusPending,
 		OrderType:     models_order.OrderType(input.OrderType),
+		TableID:       tableID,
+		SessionID:     sessionID,
+		MenuVersionID: catalog.VersionID,
 		SubTotal:      subtotal,
 		Tax:           tax,
 		ServiceFee:    serviceFee,
@@ -82,41 +302,59 @@ func CreateOrder(c *gin.Context) {
 		UpdatedAt:     time.Now(),
 	}
 
-	if err := postgres.DB.Create(&order).Error; err != nil {
+	if err := tx.Create(&order).Error; err != nil {
+		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order"})
 		return
 	}
 
-	// Create OrderItems
-	for _, item := range input.Items {
-
-		var itemOption models_menu.MenuItemOption
-
-		if err := postgres.DB.First(&itemOption, "id = ?", item.ItemOptionID).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Menu item option not found"})
+	if len(orderCombos) > 0 {
+		if err := tx.Create(&orderCombos).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order combos"})
 			return
 		}
+	}
 
-		orderItem := models_order.OrderItem{
-			ID:             uuid.Must(uuid.NewV4()),
-			OrderID:        order.ID,
-			MenuItemID:     item.MenuItemID,
-			Quantity:       item.Quantity,
-			Price:          itemOption.Price,
-			Subtotal:       float64(item.Quantity) * itemOption.Price,
-			ItemOptionID:   itemOption.ID,
-			ItemOptionName: itemOption.Name,
-		}
-		if err := postgres.DB.Create(&orderItem).Error; err != nil {
+	// Create OrderItems
+	for i := range orderItems {
+		if err := tx.Create(&orderItems[i]).Error; err != nil {
+			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order items"})
 			return
 		}
 	}
 
+	// Split the order into kitchen tickets, one per preparation station
+	tickets, err := services_kitchen.CreateOrderTickets(tx, order, orderItems)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if order.TableID != nil {
+		if err := services_restaurant.RefreshTableStatus(tx, *order.TableID); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	order.OrderItems = orderItems
+	order.Combos = orderCombos
+
 	c.JSON(http.StatusCreated, gin.H{
-		"order":  order,
-		"items":  input.Items,
-		"status": "Order created successfully",
+		"order":   order,
+		"items":   input.Items,
+		"tickets": tickets,
+		"status":  "Order created successfully",
 	})
 }
 
@@ -136,19 +374,24 @@ func GetOrder(c *gin.Context) {
 	}
 
 	var order models_order.Order
-	if err := postgres.DB.Preload("OrderItems.MenuItem").
-		Preload("OrderItems.SelectedOptions").
+	if err := postgres.DB.Preload("OrderItems.Modifiers").Preload("Combos").
 		First(&order, "id = ?", orderID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, order)
+	var tickets []models_kitchen.KitchenTicket
+	if err := postgres.DB.Preload("Items").Where("order_id = ?", order.ID).Order("created_at").Find(&tickets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch kitchen tickets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order, "tickets": tickets})
 }
 
 // UpdateOrderStatus updates the status of an order
 // @Summary Update order status
-// @Description Update the status of a specific order
+// @Description Move an order to a later status or cancel it. Orders never move back, and completed or cancelled orders cannot change.
 // @Tags Restaurant Orders
 // @Accept json
 // @Produce json
@@ -163,7 +406,7 @@ func UpdateOrderStatus(c *gin.Context) {
 	}
 
 	var statusUpdate struct {
-		Status models_order.OrderStatus `json:"status" binding:"required"`
+		Status models_order.OrderStatus `json:"status" binding:"required,oneof=PENDING CONFIRMED PREPARING READY COMPLETED CANCELLED"`
 	}
 
 	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
@@ -171,17 +414,37 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	var order models_order.Order
-	if err := postgres.DB.First(&order, "id = ?", orderID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
 		return
 	}
 
-	// Update order status
-	if err := postgres.DB.Model(&order).Updates(map[string]interface{}{
-		"status": statusUpdate.Status,
-	}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
+	order, ok := lockOpenOrder(c, tx, orderID)
+	if !ok {
+		return
+	}
+	if !order.Status.CanMoveTo(statusUpdate.Status) {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order cannot move from " + string(order.Status) + " to " + string(statusUpdate.Status)})
+		return
+	}
+
+	// Cancelling goes through the same steps as CancelOrder
+	if statusUpdate.Status == models_order.OrderStatusCancelled {
+		if err := cancelOrder(tx, &order); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else if err := advanceOrder(tx, &order, statusUpdate.Status); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
 		return
 	}
 
@@ -215,7 +478,7 @@ func ListOrders(c *gin.Context) {
 	}
 
 	var orders []models_order.Order
-	if err := query.Preload("OrderItems.MenuItem").Find(&orders).Error; err != nil {
+	if err := query.Preload("OrderItems.MenuItem").Preload("OrderItems.Modifiers").Preload("Combos").Find(&orders).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
 	}
@@ -238,19 +501,25 @@ func CancelOrder(c *gin.Context) {
 		return
 	}
 
-	var order models_order.Order
-	if err := postgres.DB.First(&order, "id = ?", orderID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	order, ok := lockOpenOrder(c, tx, orderID)
+	if !ok {
 		return
 	}
 
-	if order.Status == models_order.OrderStatusCompleted {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot cancel completed order"})
+	if err := cancelOrder(tx, &order); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := postgres.DB.Model(&order).Update("status", models_order.OrderStatusCancelled).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel order"})
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
 		return
 	}
 
@@ -259,3 +528,162 @@ func CancelOrder(c *gin.Context) {
 		"order":   order,
 	})
 }
+
+// lockOpenOrder loads and locks an order whose status can still change. It rolls back and responds itself
+// when the order cannot be found or is already completed or cancelled.
+func lockOpenOrder(c *gin.Context, tx *gorm.DB, orderID uuid.UUID) (models_order.Order, bool) {
+	var order models_order.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", orderID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return order, false
+	}
+
+	if order.Status == models_order.OrderStatusCompleted || order.Status == models_order.OrderStatusCancelled {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Order is already " + strings.ToLower(string(order.Status)) + " and its status cannot change"})
+		return order, false
+	}
+	return order, true
+}
+
+// advanceOrder moves an order to a new status. Its kitchen tickets are closed once it is ready,
+// and its ingredients are taken from the stock once it is completed.
+func advanceOrder(tx *gorm.DB, order *models_order.Order, status models_order.OrderStatus) error {
+	updates := map[string]interface{}{
+		"status": status,
+	}
+	if status == models_order.OrderStatusCompleted && order.CompletedAt == nil {
+		updates["completed_at"] = time.Now()
+	}
+	if err := tx.Model(order).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update order status")
+	}
+
+	if status == models_order.OrderStatusReady || status == models_order.OrderStatusCompleted {
+		if err := services_kitchen.CompleteOrderTickets(tx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	// Ingredients are taken from the stock once the order is completed
+	if status == models_order.OrderStatusCompleted {
+		if err := services_inventory.ConsumeIngredients(tx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	// Free the table once its last open order is closed
+	if order.TableID != nil {
+		if err := services_restaurant.RefreshTableStatus(tx, *order.TableID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelOrder cancels an order, removes its kitchen tickets, puts its portions back in stock and frees its table
+func cancelOrder(tx *gorm.DB, order *models_order.Order) error {
+	if err := tx.Model(order).Update("status", models_order.OrderStatusCancelled).Error; err != nil {
+		return fmt.Errorf("failed to cancel order")
+	}
+
+	if err := services_kitchen.CancelOrderTickets(tx, order.ID); err != nil {
+		return err
+	}
+
+	// Portions of a cancelled order go back to the stock
+	if err := services_inventory.RestockOrder(tx, order.ID); err != nil {
+		return err
+	}
+
+	// Free the table once its last open order is closed
+	if order.TableID != nil {
+		if err := services_restaurant.RefreshTableStatus(tx, *order.TableID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkItemsExist makes sure every ordered item still exists in the menus
+func checkItemsExist(tx *gorm.DB, orderItems []models_order.OrderItem) error {
+	ids := make(map[uuid.UUID]string)
+	for _, item := range orderItems {
+		ids[item.MenuItemID] = item.MenuName
+	}
+	var menuItemIDs []uuid.UUID
+	for id := range ids {
+		menuItemIDs = append(menuItemIDs, id)
+	}
+
+	var existing []uuid.UUID
+	if err := tx.Model(&models_menu.MenuItem{}).Where("id IN ?", menuItemIDs).Pluck("id", &existing).Error; err != nil {
+		return fmt.Errorf("failed to fetch menu items")
+	}
+	for _, id := range existing {
+		delete(ids, id)
+	}
+	for _, name := range ids {
+		return fmt.Errorf("%s is no longer on the menu", name)
+	}
+	return nil
+}
+
+// resolveModifiers validates the modifiers chosen for an order item against the item's modifier groups
+// and returns their snapshots with the price they add to one unit of the item.
+func resolveModifiers(menuItem models_menu.MenuItem, orderItemID uuid.UUID, modifierIDs []uuid.UUID) ([]models_order.OrderItemModifier, float64, error) {
+	groups := menuItem.ModifierGroups
+
+	type choice struct {
+		group    *models_menu.ModifierGroup
+		modifier models_menu.Modifier
+	}
+	choices := make(map[uuid.UUID]choice)
+	for i := range groups {
+		for _, modifier := range groups[i].Modifiers {
+			choices[modifier.ID] = choice{group: &groups[i], modifier: modifier}
+		}
+	}
+
+	var snapshots []models_order.OrderItemModifier
+	var price float64
+	selected := make(map[uuid.UUID]int)
+	seen := make(map[uuid.UUID]bool)
+	for _, modifierID := range modifierIDs {
+		chosen, ok := choices[modifierID]
+		if !ok {
+			return nil, 0, fmt.Errorf("modifier %s does not belong to %s", modifierID, menuItem.Name)
+		}
+		if seen[modifierID] {
+			return nil, 0, fmt.Errorf("%s is selected more than once", chosen.modifier.Name)
+		}
+		if !chosen.modifier.IsAvailable {
+			return nil, 0, fmt.Errorf("%s is not available", chosen.modifier.Name)
+		}
+		seen[modifierID] = true
+		selected[chosen.group.ID]++
+		price += chosen.modifier.Price
+
+		snapshots = append(snapshots, models_order.OrderItemModifier{
+			ID:          uuid.Must(uuid.NewV4()),
+			OrderItemID: orderItemID,
+			ModifierID:  chosen.modifier.ID,
+			GroupName:   chosen.group.Name,
+			Name:        chosen.modifier.Name,
+			Price:       chosen.modifier.Price,
+		})
+	}
+
+	for _, group := range groups {
+		count := selected[group.ID]
+		if count < group.MinSelect {
+			return nil, 0, fmt.Errorf("choose at least %d of %s for %s", group.MinSelect, group.Name, menuItem.Name)
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return nil, 0, fmt.Errorf("choose at most %d of %s for %s", group.MaxSelect, group.Name, menuItem.Name)
+		}
+	}
+
+	return snapshots, price, nil
+}
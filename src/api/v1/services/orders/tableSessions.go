@@ -0,0 +1,665 @@
+package services_orders
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	services_restaurant "dine-server/src/api/v1/services/restaurants"
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/env"
+	"dine-server/src/config/payments"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OpenTableSession opens a running tab on a table
+// @Summary Open a table session
+// @Description Open a running tab on a table. Dine-in orders placed at the table are added to it.
+// @Tags Table Sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session body models_order.OpenSessionData true "Session data"
+// @Router /api/v1/{restaurant_id}/sessions [post]
+func OpenTableSession(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var input models_order.OpenSessionData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", input.TableID, restaurantUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	session, err := OpenSessionForTable(tx, table)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.CustomerName != "" || input.CustomerPhone != "" {
+		if err := tx.Model(&session).Updates(models_order.TableSession{
+			CustomerName:  input.CustomerName,
+			CustomerPhone: input.CustomerPhone,
+		}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Session opened successfully", "session": session})
+}
+
+// ListTableSessions retrieves the table sessions of a restaurant
+// @Summary List table sessions
+// @Description List the table sessions of a restaurant
+// @Tags Table Sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param status query string false "Session Status Filter (open, closed, settled)"
+// @Param table_id query string false "Table ID"
+// @Router /api/v1/{restaurant_id}/sessions [get]
+func ListTableSessions(c *gin.Context) {
+	query := postgres.DB.Where("restaurant_id = ?", c.Param("restaurant_id"))
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if tableID := c.Query("table_id"); tableID != "" {
+		query = query.Where("table_id = ?", tableID)
+	}
+
+	var sessions []models_order.TableSession
+	if err := query.Order("created_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions Found Successfully", "sessions": sessions})
+}
+
+// GetTableSession retrieves a table session with its orders and payments
+// @Summary Get table session details
+// @Description Get a table session with its orders, items and bill payments
+// @Tags Table Sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session_id path string true "Session ID"
+// @Router /api/v1/{restaurant_id}/sessions/{session_id} [get]
+func GetTableSession(c *gin.Context) {
+	var session models_order.TableSession
+	if err := postgres.DB.
+		Preload("Orders", "status <> ?", models_order.OrderStatusCancelled).
+		Preload("Orders.OrderItems.Modifiers").
+		Preload("Orders.Combos").
+		Preload("Payments").
+		First(&session, "id = ? AND restaurant_id = ?", c.Param("session_id"), c.Param("restaurant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+// CloseTableSession closes the tab of a table into a single bill
+// @Summary Close a table session
+// @Description Stop accepting orders on the session and total its orders into one bill
+// @Tags Table Sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session_id path string true "Session ID"
+// @Router /api/v1/{restaurant_id}/sessions/{session_id}/close [post]
+func CloseTableSession(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var session models_order.TableSession
+	if err := postgres.DB.Preload("Orders", "status <> ?", models_order.OrderStatusCancelled).
+		First(&session, "id = ? AND restaurant_id = ?", c.Param("session_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if session.Status != models_order.SessionStatusOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session is already closed"})
+		return
+	}
+	if len(session.Orders) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Session has no orders"})
+		return
+	}
+
+	var subtotal, tax, serviceFee, total float64
+	for _, order := range session.Orders {
+		subtotal += order.SubTotal
+		tax += order.Tax
+		serviceFee += order.ServiceFee
+		total += order.Total
+	}
+
+	now := time.Now()
+	session.Status = models_order.SessionStatusClosed
+	session.SubTotal = utils.RoundAmount(subtotal)
+	session.Tax = utils.RoundAmount(tax)
+	session.ServiceFee = utils.RoundAmount(serviceFee)
+	session.Total = utils.RoundAmount(total)
+	session.ClosedAt = &now
+
+	if err := postgres.DB.Model(&session).Select("status", "sub_total", "tax", "service_fee", "total", "closed_at").Updates(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session closed successfully", "session": session})
+}
+
+// SplitTableSessionBill splits the bill of a closed session into separate payments
+// @Summary Split a table session bill
+// @Description Split the bill equally (parts), by item (items) or by custom amounts (amounts). Re-splitting replaces the pending payments.
+// @Tags Table Sessions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session_id path string true "Session ID"
+// @Param split body models_order.SplitBillData true "Split data"
+// @Router /api/v1/{restaurant_id}/sessions/{session_id}/split [post]
+func SplitTableSessionBill(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_order.SplitBillData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var session models_order.TableSession
+	if err := postgres.DB.
+		Preload("Orders", "status <> ?", models_order.OrderStatusCancelled).
+		Preload("Orders.OrderItems.Modifiers").
+		Preload("Orders.Combos").
+		Preload("Payments").
+		First(&session, "id = ? AND restaurant_id = ?", c.Param("session_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if session.Status != models_order.SessionStatusClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only a closed session can be split"})
+		return
+	}
+	for _, payment := range session.Payments {
+		if payment.Status == "successful" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Bill has already been partially paid"})
+			return
+		}
+	}
+
+	sessionPayments, err := splitBill(session, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if err := tx.Where("session_id = ?", session.ID).Delete(&models_order.SessionPayment{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replace previous split"})
+		return
+	}
+	if err := tx.Create(&sessionPayments).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bill payments"})
+		return
+	}
+	if err := tx.Model(&session).Update("split_mode", input.Mode).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bill split successfully", "payments": sessionPayments})
+}
+
+// PaySessionPaymentOnsite records a bill share paid at the counter
+// @Summary Pay a bill share onsite
+// @Description Record a bill share as paid in cash or card at the restaurant
+// @Tags Table Sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session_id path string true "Session ID"
+// @Param payment_id path string true "Session Payment ID"
+// @Router /api/v1/{restaurant_id}/sessions/{session_id}/payments/{payment_id}/onsite [post]
+func PaySessionPaymentOnsite(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	session, payment, err := findSessionPayment(restaurantID, c.Param("session_id"), c.Param("payment_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if payment.Status == "successful" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payment already completed"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if !lockSessionPayment(c, tx, &session, &payment) {
+		return
+	}
+
+	now := time.Now()
+	if err := tx.Model(&payment).Updates(map[string]interface{}{
+		"payment_type": "onsite",
+		"status":       "successful",
+		"paid_at":      now,
+	}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment status"})
+		return
+	}
+
+	settled, err := settleSessionIfPaid(tx, session)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment recorded successfully", "session_settled": settled})
+}
+
+// PaySessionPaymentOnline creates a payment link for a bill share
+// @Summary Pay a bill share online
+// @Description Create a Razorpay payment link for a bill share
+// @Tags Table Sessions
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param session_id path string true "Session ID"
+// @Param payment_id path string true "Session Payment ID"
+// @Router /api/v1/{restaurant_id}/sessions/{session_id}/payments/{payment_id}/online [post]
+func PaySessionPaymentOnline(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	_, payment, err := findSessionPayment(restaurantID, c.Param("session_id"), c.Param("payment_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if payment.Status == "successful" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payment already completed"})
+		return
+	}
+
+	var callbackURL string
+	if env.AppVar["ENVIRONMENT"] != "development" {
+		callbackURL = "https://" + env.AppVar["SERVER_HOST"] + "/api/v1/" + restaurantID + "/sessions/payments/callback"
+	} else {
+		callbackURL = "http://localhost:8080/api/v1/" + restaurantID + "/sessions/payments/callback"
+	}
+
+	params := map[string]interface{}{
+		"amount":          math.Round(payment.Amount * 100), // Amount in smallest currency unit (paise for INR)
+		"currency":        "INR",
+		"reference_id":    payment.ID.String(),
+		"description":     "Bill payment " + payment.Label,
+		"callback_url":    callbackURL,
+		"callback_method": "get",
+	}
+	paymentLink, err := payments.RazorpayClient.PaymentLink.Create(params, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment link"})
+		return
+	}
+
+	shortURL, _ := paymentLink["short_url"].(string)
+	if err := postgres.DB.Model(&payment).Updates(map[string]interface{}{
+		"payment_type":   "online",
+		"transaction_id": paymentLink["id"],
+		"payment_link":   shortURL,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment_link": shortURL})
+}
+
+// SessionPaymentCallback handles the Razorpay callback of a bill share payment link
+// @Summary Razorpay bill payment callback
+// @Description Handle the Razorpay callback of a bill share payment
+// @Tags Table Sessions
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/sessions/payments/callback [get]
+func SessionPaymentCallback(c *gin.Context) {
+	paymentID := c.Query("razorpay_payment_id")
+	paymentLinkID := c.Query("razorpay_payment_link_id")
+	paymentReferenceID := c.Query("razorpay_payment_link_reference_id")
+	paymentStatus := c.Query("razorpay_payment_link_status")
+	signature := c.Query("razorpay_signature")
+
+	// Verify the signature
+	expectedSignature := hmac.New(sha256.New, []byte(env.PaymentsVar["RAZORPAY_SECRET_KEY"]))
+	expectedSignature.Write([]byte(paymentLinkID + "|" + paymentReferenceID + "|" + paymentStatus + "|" + paymentID))
+	computedSignature := hex.EncodeToString(expectedSignature.Sum(nil))
+
+	if !hmac.Equal([]byte(computedSignature), []byte(signature)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "signature mismatch"})
+		return
+	}
+
+	var payment models_order.SessionPayment
+	if err := postgres.DB.First(&payment, "id = ? AND transaction_id = ?", paymentReferenceID, paymentLinkID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		return
+	}
+
+	var session models_order.TableSession
+	if err := postgres.DB.First(&session, "id = ? AND restaurant_id = ?", payment.SessionID, c.Param("restaurant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if payment.Status == "successful" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Payment already completed"})
+		return
+	}
+
+	if paymentStatus != "paid" {
+		postgres.DB.Model(&payment).Update("status", "failed")
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "payment failed"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if !lockSessionPayment(c, tx, &session, &payment) {
+		return
+	}
+
+	if err := tx.Model(&payment).Updates(map[string]interface{}{
+		"status":  "successful",
+		"paid_at": time.Now(),
+	}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment status"})
+		return
+	}
+
+	settled, err := settleSessionIfPaid(tx, session)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment verified successfully", "session_settled": settled})
+}
+
+// OpenSessionForTable returns the open session of a table, opening one when there is none.
+// Orders are refused while the bill of the table is being settled.
+func OpenSessionForTable(tx *gorm.DB, table models_restaurant.RestaurantTable) (models_order.TableSession, error) {
+	var session models_order.TableSession
+	if err := tx.Where("table_id = ? AND status <> ?", table.ID, models_order.SessionStatusSettled).
+		Order("created_at DESC").First(&session).Error; err == nil {
+		if session.Status == models_order.SessionStatusClosed {
+			return session, fmt.Errorf("the bill of this table has been requested")
+		}
+		return session, nil
+	}
+
+	session = models_order.TableSession{
+		ID:           uuid.Must(uuid.NewV4()),
+		RestaurantID: table.RestaurantID,
+		TableID:      table.ID,
+		Status:       models_order.SessionStatusOpen,
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		return session, fmt.Errorf("failed to open table session")
+	}
+
+	if err := services_restaurant.RefreshTableStatus(tx, table.ID); err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+// lockSessionPayment locks the session and one of its bill shares, so that concurrent payments settle the session
+// once. The session must still be awaiting payment and the share unpaid once the locks are held.
+func lockSessionPayment(c *gin.Context, tx *gorm.DB, session *models_order.TableSession, payment *models_order.SessionPayment) bool {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(session, "id = ?", session.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return false
+	}
+	if session.Status != models_order.SessionStatusClosed {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": "Session is not awaiting payment"})
+		return false
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(payment, "id = ?", payment.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		return false
+	}
+	if payment.Status == "successful" {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, gin.H{"error": "Payment already completed"})
+		return false
+	}
+	return true
+}
+
+// settleSessionIfPaid settles the session once every share of its bill is paid:
+// its orders are completed and the table is freed. The caller holds the lock on the session.
+func settleSessionIfPaid(tx *gorm.DB, session models_order.TableSession) (bool, error) {
+	var pending int64
+	if err := tx.Model(&models_order.SessionPayment{}).
+		Where("session_id = ? AND status <> ?", session.ID, "successful").
+		Count(&pending).Error; err != nil {
+		return false, fmt.Errorf("failed to count pending payments")
+	}
+	if pending > 0 {
+		return false, nil
+	}
+
+	if err := tx.Model(&session).Updates(map[string]interface{}{
+		"status":     models_order.SessionStatusSettled,
+		"settled_at": time.Now(),
+	}).Error; err != nil {
+		return false, fmt.Errorf("failed to settle session")
+	}
+
+	var orders []models_order.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("session_id = ? AND status NOT IN ?", session.ID, []models_order.OrderStatus{models_order.OrderStatusCancelled, models_order.OrderStatusCompleted}).
+		Find(&orders).Error; err != nil {
+		return false, fmt.Errorf("failed to fetch session orders")
+	}
+
+	// Each order is completed like one closed by the staff
+	for i := range orders {
+		if err := advanceOrder(tx, &orders[i], models_order.OrderStatusCompleted); err != nil {
+			return false, err
+		}
+	}
+
+	if err := services_restaurant.RefreshTableStatus(tx, session.TableID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// findSessionPayment loads a bill share of a closed session of the restaurant
+func findSessionPayment(restaurantID, sessionID, paymentID string) (models_order.TableSession, models_order.SessionPayment, error) {
+	var session models_order.TableSession
+	var payment models_order.SessionPayment
+
+	if err := postgres.DB.First(&session, "id = ? AND restaurant_id = ?", sessionID, restaurantID).Error; err != nil {
+		return session, payment, fmt.Errorf("session not found")
+	}
+	if session.Status != models_order.SessionStatusClosed {
+		return session, payment, fmt.Errorf("session is not awaiting payment")
+	}
+	if err := postgres.DB.First(&payment, "id = ? AND session_id = ?", paymentID, session.ID).Error; err != nil {
+		return session, payment, fmt.Errorf("payment not found")
+	}
+
+	return session, payment, nil
+}
+
+// splitBill computes the payments of a split bill. Rounding differences go to the last share.
+func splitBill(session models_order.TableSession, input models_order.SplitBillData) ([]models_order.SessionPayment, error) {
+	var sessionPayments []models_order.SessionPayment
+	newPayment := func(label string, amount float64, orderItemIDs []uuid.UUID) {
+		if label == "" {
+			label = fmt.Sprintf("Share %d", len(sessionPayments)+1)
+		}
+		sessionPayments = append(sessionPayments, models_order.SessionPayment{
+			ID:           uuid.Must(uuid.NewV4()),
+			SessionID:    session.ID,
+			Label:        label,
+			Amount:       amount,
+			OrderItemIDs: orderItemIDs,
+			Status:       "pending",
+		})
+	}
+
+	switch input.Mode {
+	case models_order.SplitModeEqual:
+		parts := input.Parts
+		if parts == 0 {
+			parts = 1
+		}
+		share := math.Floor(session.Total/float64(parts)*100) / 100
+		for i := 0; i < parts-1; i++ {
+			newPayment("", share, nil)
+		}
+		newPayment("", utils.RoundAmount(session.Total-share*float64(parts-1)), nil)
+
+	case models_order.SplitModeItem:
+		// Every item carries its share of the tax and service fee of its order
+		itemShare := make(map[uuid.UUID]float64)
+		for _, order := range session.Orders {
+			ratio := 1.0
+			if order.SubTotal > 0 {
+				ratio = order.Total / order.SubTotal
+			}
+			for _, item := range order.OrderItems {
+				itemShare[item.ID] = item.Subtotal * ratio
+			}
+		}
+
+		assigned := make(map[uuid.UUID]bool)
+		var allocated float64
+		for i, group := range input.Items {
+			var amount float64
+			for _, itemID := range group.OrderItemIDs {
+				share, ok := itemShare[itemID]
+				if !ok {
+					return nil, fmt.Errorf("order item %s is not part of this session", itemID)
+				}
+				if assigned[itemID] {
+					return nil, fmt.Errorf("order item %s is assigned more than once", itemID)
+				}
+				assigned[itemID] = true
+				amount += share
+			}
+			amount = utils.RoundAmount(amount)
+			if i == len(input.Items)-1 {
+				amount = utils.RoundAmount(session.Total - allocated)
+			}
+			allocated += amount
+			newPayment(group.Label, amount, group.OrderItemIDs)
+		}
+		if len(assigned) != len(itemShare) {
+			return nil, fmt.Errorf("every order item must be assigned to a share")
+		}
+
+	case models_order.SplitModeCustom:
+		var sum float64
+		for _, amount := range input.Amounts {
+			sum += amount.Amount
+			newPayment(amount.Label, utils.RoundAmount(amount.Amount), nil)
+		}
+		if math.Abs(sum-session.Total) > 0.009 {
+			return nil, fmt.Errorf("amounts add up to %.2f but the bill is %.2f", sum, session.Total)
+		}
+	}
+
+	if len(sessionPayments) == 0 {
+		return nil, fmt.Errorf("nothing to split")
+	}
+
+	return sessionPayments, nil
+}
@@ -138,6 +138,7 @@ func GetAllPlans(c *gin.Context) {
 		planResponse.Price = plan.Price
 		planResponse.IsActive = plan.IsActive
 		planResponse.TrialPeriod = plan.TrialPeriod
+		planResponse.MaxOutlets = plan.MaxOutlets
 		planResponse.CreatedAt = plan.CreatedAt
 		planResponse.UpdatedAt = plan.UpdatedAt
 
@@ -190,6 +191,7 @@ func GetPlans(c *gin.Context) {
 		planResponse.Price = plan.Price
 		planResponse.IsActive = plan.IsActive
 		planResponse.TrialPeriod = plan.TrialPeriod
+		planResponse.MaxOutlets = plan.MaxOutlets
 		planResponse.CreatedAt = plan.CreatedAt
 		planResponse.UpdatedAt = plan.UpdatedAt
 
@@ -236,6 +238,7 @@ func GetPlanByID(c *gin.Context) {
 	planResponse.Price = Plan.Price
 	planResponse.IsActive = Plan.IsActive
 	planResponse.TrialPeriod = Plan.TrialPeriod
+	planResponse.MaxOutlets = Plan.MaxOutlets
 	planResponse.CreatedAt = Plan.CreatedAt
 	planResponse.UpdatedAt = Plan.UpdatedAt
 
@@ -295,6 +298,9 @@ func UpdatePlan(c *gin.Context) {
 	if input.TrialPeriod != Plan.TrialPeriod {
 		Plan.TrialPeriod = input.TrialPeriod
 	}
+	if input.MaxOutlets != 0 {
+		Plan.MaxOutlets = input.MaxOutlets
+	}
 
 	if err := postgres.DB.Save(&Plan).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Plan"})
@@ -1,6 +1,7 @@
 package services
 
 import (
+	services_images "dine-server/src/api/v1/services/images"
 	postgres "dine-server/src/config/database"
 	models_common "dine-server/src/models/Common"
 	models_restaurant "dine-server/src/models/restaurants"
@@ -32,7 +33,24 @@ func GetAllRestaurants(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"restaurants": utils.RestaurantResponse(restaurant)})
+	var restaurantIDs []uuid.UUID
+	for _, r := range restaurant {
+		restaurantIDs = append(restaurantIDs, r.ID)
+	}
+	counts := tableCounts(restaurantIDs)
+	schedules, err := restaurantSchedules(postgres.DB, restaurant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve opening hours"})
+		return
+	}
+
+	response := utils.RestaurantResponse(restaurant)
+	for i := range response {
+		response[i].NumberOfTables = counts[response[i].ID]
+		applySchedule(&response[i], restaurant[i], schedules[restaurant[i].ID])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"restaurants": response})
 }
 
 // GetRestaurants godoc
@@ -51,16 +69,28 @@ func GetRestaurants(c *gin.Context) {
 		return
 	}
 
-	// Fetch restaurants associated with the user ID
-	if err := postgres.DB.Where("admin_id = ?", userId).Find(&restaurantDatas).Error; err != nil {
+	// Fetch the restaurants the user owns or works at
+	if err := postgres.DB.Where("admin_id = ? OR id IN (SELECT restaurant_id FROM staff_members WHERE user_id = ?)", userId, userId).
+		Find(&restaurantDatas).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve restaurants"})
 		return
 	}
 
+	var restaurantIDs []uuid.UUID
+	for _, restaurant := range restaurantDatas {
+		restaurantIDs = append(restaurantIDs, restaurant.ID)
+	}
+	counts := tableCounts(restaurantIDs)
+	schedules, err := restaurantSchedules(postgres.DB, restaurantDatas)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve opening hours"})
+		return
+	}
+
 	// Map fetched data to ResponseRestaurant structs
 	var response []models_restaurant.ResponseRestaurantData
 	for _, restaurant := range restaurantDatas {
-		response = append(response, models_restaurant.ResponseRestaurantData{
+		restaurantResponse := models_restaurant.ResponseRestaurantData{
 			ID:             restaurant.ID,
 			Name:           restaurant.Name,
 			Description:    restaurant.Description,
@@ -73,7 +103,11 @@ func GetRestaurants(c *gin.Context) {
 			IsActive:       restaurant.IsActive,
 			HasParking:     restaurant.HasParking,
 			HasPickup:      restaurant.HasPickup,
-		})
+			Language:       restaurant.Language,
+			NumberOfTables: counts[restaurant.ID],
+		}
+		applySchedule(&restaurantResponse, restaurant, schedules[restaurant.ID])
+		response = append(response, restaurantResponse)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"restaurants": response})
@@ -90,27 +124,11 @@ func GetRestaurantByID(c *gin.Context) {
 	id := c.Param("id")
 	var restaurantData models_restaurant.Restaurant
 
-	// Extract userID and role from context
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+	// Staff access is checked by the route
+	if err := postgres.DB.Where("id = ?", id).First(&restaurantData).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant ID not found"})
 		return
 	}
-	role, _ := c.Get("role")
-
-	// Restaurant Admin access check
-	if role == "restaurant_admin" {
-		if err := postgres.DB.Where("id = ? AND admin_id = ?", id, userID).First(&restaurantData).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-	} else {
-		// General access
-		if err := postgres.DB.Where("id = ?", id).First(&restaurantData).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant ID not found"})
-			return
-		}
-	}
 
 	// Map data to ResponseRestaurant struct
 	response := models_restaurant.ResponseRestaurantData{
@@ -126,7 +144,16 @@ func GetRestaurantByID(c *gin.Context) {
 		IsActive:       restaurantData.IsActive,
 		HasParking:     restaurantData.HasParking,
 		HasPickup:      restaurantData.HasPickup,
+		Language:       restaurantData.Language,
+		NumberOfTables: tableCounts([]uuid.UUID{restaurantData.ID})[restaurantData.ID],
+	}
+
+	schedules, err := restaurantSchedules(postgres.DB, []models_restaurant.Restaurant{restaurantData})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve opening hours"})
+		return
 	}
+	applySchedule(&response, restaurantData, schedules[restaurantData.ID])
 
 	c.JSON(http.StatusOK, gin.H{"restaurant": response})
 }
@@ -232,22 +259,6 @@ func UpdateRestaurant(c *gin.Context) {
 		return
 	}
 
-	// Role-based authorization
-	role, _ := c.Get("role")
-	if role != "admin" && role != "restaurant_admin" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	// Check if the restaurant_admin is authorized for this restaurant
-	if role == "restaurant_admin" {
-		userID, _ := c.Get("userID")
-		if restaurant.AdminID.String() != userID {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			return
-		}
-	}
-
 	// Bind request data to UpdateRestaurantData schema
 	var resturantData models_restaurant.UpdateRestaurantData
 	if err := c.ShouldBindJSON(&resturantData); err != nil {
@@ -255,6 +266,12 @@ func UpdateRestaurant(c *gin.Context) {
 		return
 	}
 
+	language := utils.NormalizeLocale(resturantData.Language)
+	if resturantData.Language != "" && language == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language, use a code like en or pt-br"})
+		return
+	}
+
 	// Dynamically map fields from UpdateRestaurantData to Restaurant
 	updates := models_restaurant.Restaurant{
 		Name:           resturantData.Name,
@@ -267,6 +284,7 @@ func UpdateRestaurant(c *gin.Context) {
 		IsActive:       resturantData.IsActive,
 		HasParking:     resturantData.HasParking,
 		HasPickup:      resturantData.HasPickup,
+		Language:       language,
 	}
 
 	// Perform updates only on the fields that are non-zero values
@@ -274,6 +292,17 @@ func UpdateRestaurant(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update restaurant"})
 		return
 	}
+	if resturantData.BannerImageUrl != "" || resturantData.LogoImageUrl != "" {
+		services_images.CleanupImagesLater(id)
+	}
+
+	// Create the missing tables when the number of tables was raised
+	if resturantData.NumberOfTables > 0 {
+		if err := ensureTables(restaurant.ID, resturantData.NumberOfTables); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create restaurant tables"})
+			return
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Restaurant updated successfully",
@@ -291,20 +320,11 @@ func UpdateRestaurant(c *gin.Context) {
 func DeleteRestaurant(c *gin.Context) {
 
 	id := c.Param("id")
-	role, _ := c.Get("role")
 
+	// Only the owner and platform admins get here, the route checks it
 	var restaurant models_restaurant.Restaurant
-	query := postgres.DB.Where("id = ?", id)
-	if role == "restaurant_admin" {
-		userId, _ := c.Get("userID")
-		query = query.Where("admin_id = ?", userId)
-	}
-	if err := query.Delete(&restaurant).Error; err != nil {
-		if role == "restaurant_admin" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid restaurant ID"})
-		}
+	if err := postgres.DB.Where("id = ?", id).Delete(&restaurant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid restaurant ID"})
 		return
 	}
 
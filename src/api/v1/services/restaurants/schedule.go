@@ -0,0 +1,309 @@
+package services
+
+import (
+	postgres "dine-server/src/config/database"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSchedule godoc
+// @Summary Retrieve the opening schedule of a restaurant
+// @Description Retrieve the weekly opening hours, upcoming closures and whether the restaurant is open now
+// @Tags Restaurant Schedule
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/schedule [get]
+func GetSchedule(c *gin.Context) {
+	restaurant, schedule, err := RestaurantSchedule(postgres.DB, c.Param("restaurant_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedule": scheduleResponse(restaurant, schedule)})
+}
+
+// UpdateSchedule godoc
+// @Summary Update the opening schedule of a restaurant
+// @Description Update the timezone and replace the weekly opening hours of a restaurant. A day can have several shifts.
+// @Tags Restaurant Schedule
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param schedule body models_restaurant.UpdateScheduleData true "Schedule data"
+// @Router /api/v1/{restaurant_id}/schedule [put]
+func UpdateSchedule(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.UpdateScheduleData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	if input.Timezone != "" {
+		if err := tx.Model(&restaurant).Update("timezone", input.Timezone).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update timezone"})
+			return
+		}
+	}
+
+	if input.Hours != nil {
+		if err := tx.Where("restaurant_id = ?", restaurant.ID).Delete(&models_restaurant.OpeningHour{}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update opening hours"})
+			return
+		}
+
+		var hours []models_restaurant.OpeningHour
+		for _, hour := range *input.Hours {
+			if hour.OpensAt == hour.ClosesAt {
+				tx.Rollback()
+				c.JSON(http.StatusBadRequest, gin.H{"error": "A shift cannot open and close at the same time"})
+				return
+			}
+			hours = append(hours, models_restaurant.OpeningHour{
+				RestaurantID: restaurant.ID,
+				DayOfWeek:    *hour.DayOfWeek,
+				OpensAt:      hour.OpensAt,
+				ClosesAt:     hour.ClosesAt,
+			})
+		}
+		if len(hours) > 0 {
+			if err := tx.Create(&hours).Error; err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update opening hours"})
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	restaurant, schedule, err := RestaurantSchedule(postgres.DB, restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule updated successfully", "schedule": scheduleResponse(restaurant, schedule)})
+}
+
+// AddClosure godoc
+// @Summary Add a closure to a restaurant
+// @Description Close a restaurant for one or more whole days, e.g. a holiday
+// @Tags Restaurant Schedule
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param closure body models_restaurant.AddClosureData true "Closure data"
+// @Router /api/v1/{restaurant_id}/schedule/closures [post]
+func AddClosure(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var input models_restaurant.AddClosureData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.EndDate == "" {
+		input.EndDate = input.StartDate
+	}
+	if input.EndDate < input.StartDate {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End date must not be before start date"})
+		return
+	}
+
+	closure := models_restaurant.RestaurantClosure{
+		RestaurantID: restaurantUUID,
+		StartDate:    input.StartDate,
+		EndDate:      input.EndDate,
+		Reason:       input.Reason,
+	}
+	if err := postgres.DB.Create(&closure).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add closure"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Closure added successfully", "closure": closure})
+}
+
+// DeleteClosure godoc
+// @Summary Delete a closure of a restaurant
+// @Description Delete a closure of a restaurant by ID
+// @Tags Restaurant Schedule
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param closure_id path string true "Closure ID"
+// @Router /api/v1/{restaurant_id}/schedule/closures/{closure_id} [delete]
+func DeleteClosure(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	result := postgres.DB.Where("id = ? AND restaurant_id = ?", c.Param("closure_id"), restaurantID).Delete(&models_restaurant.RestaurantClosure{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete closure"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Closure not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Closure deleted successfully"})
+}
+
+// PauseOrders godoc
+// @Summary Pause or resume orders
+// @Description Manually stop or restart taking orders, regardless of opening hours
+// @Tags Restaurant Schedule
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param pause body models_restaurant.PauseOrdersData true "Pause data"
+// @Router /api/v1/{restaurant_id}/schedule/pause [put]
+func PauseOrders(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.PauseOrdersData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("id = ?", restaurantID).Update("orders_paused", *input.Paused)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update restaurant"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	if *input.Paused {
+		c.JSON(http.StatusOK, gin.H{"message": "Orders paused successfully"})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"message": "Orders resumed successfully"})
+	}
+}
+
+// RestaurantSchedule loads a restaurant with its opening hours and current or upcoming closures
+func RestaurantSchedule(db *gorm.DB, restaurantID interface{}) (models_restaurant.Restaurant, utils.OpeningSchedule, error) {
+	var restaurant models_restaurant.Restaurant
+	if err := db.First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		return restaurant, utils.OpeningSchedule{}, err
+	}
+
+	schedules, err := restaurantSchedules(db, []models_restaurant.Restaurant{restaurant})
+	if err != nil {
+		return restaurant, utils.OpeningSchedule{}, err
+	}
+
+	return restaurant, schedules[restaurant.ID], nil
+}
+
+// restaurantSchedules loads the schedules of several restaurants at once
+func restaurantSchedules(db *gorm.DB, restaurants []models_restaurant.Restaurant) (map[uuid.UUID]utils.OpeningSchedule, error) {
+	schedules := make(map[uuid.UUID]utils.OpeningSchedule)
+	if len(restaurants) == 0 {
+		return schedules, nil
+	}
+
+	var restaurantIDs []uuid.UUID
+	for _, restaurant := range restaurants {
+		restaurantIDs = append(restaurantIDs, restaurant.ID)
+	}
+
+	var hours []models_restaurant.OpeningHour
+	if err := db.Where("restaurant_id IN ?", restaurantIDs).Order("day_of_week, opens_at").Find(&hours).Error; err != nil {
+		return nil, err
+	}
+
+	// Closures that ended before yesterday can no longer affect opening times
+	since := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+	var closures []models_restaurant.RestaurantClosure
+	if err := db.Where("restaurant_id IN ? AND end_date >= ?", restaurantIDs, since).Order("start_date").Find(&closures).Error; err != nil {
+		return nil, err
+	}
+
+	hoursByRestaurant := make(map[uuid.UUID][]models_restaurant.OpeningHour)
+	for _, hour := range hours {
+		hoursByRestaurant[hour.RestaurantID] = append(hoursByRestaurant[hour.RestaurantID], hour)
+	}
+	closuresByRestaurant := make(map[uuid.UUID][]models_restaurant.RestaurantClosure)
+	for _, closure := range closures {
+		closuresByRestaurant[closure.RestaurantID] = append(closuresByRestaurant[closure.RestaurantID], closure)
+	}
+
+	for _, restaurant := range restaurants {
+		schedules[restaurant.ID] = utils.NewOpeningSchedule(restaurant, hoursByRestaurant[restaurant.ID], closuresByRestaurant[restaurant.ID])
+	}
+	return schedules, nil
+}
+
+// applySchedule fills the opening fields of a restaurant response
+func applySchedule(response *models_restaurant.ResponseRestaurantData, restaurant models_restaurant.Restaurant, schedule utils.OpeningSchedule) {
+	now := time.Now()
+	response.Timezone = schedule.Location.String()
+	response.OrdersPaused = restaurant.OrdersPaused
+	response.IsOpenNow = schedule.AcceptsOrdersAt(now)
+	response.NextOpeningAt = schedule.NextOpeningAt(now)
+}
+
+func scheduleResponse(restaurant models_restaurant.Restaurant, schedule utils.OpeningSchedule) models_restaurant.ScheduleResponse {
+	now := time.Now()
+	response := models_restaurant.ScheduleResponse{
+		Timezone:      schedule.Location.String(),
+		OrdersPaused:  restaurant.OrdersPaused,
+		IsOpenNow:     schedule.AcceptsOrdersAt(now),
+		NextOpeningAt: schedule.NextOpeningAt(now),
+		Hours:         schedule.Hours,
+		Closures:      schedule.Closures,
+	}
+	if response.Hours == nil {
+		response.Hours = []models_restaurant.OpeningHour{}
+	}
+	if response.Closures == nil {
+		response.Closures = []models_restaurant.RestaurantClosure{}
+	}
+	return response
+}
@@ -0,0 +1,514 @@
+package services
+
+import (
+	services_menu "dine-server/src/api/v1/services/menus"
+	postgres "dine-server/src/config/database"
+	models_order "dine-server/src/models/orders"
+	models_restaurant "dine-server/src/models/restaurants"
+	"dine-server/src/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	qrcode "github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// CreateTable godoc
+// @Summary Create a new table
+// @Description Create a dine-in table for a restaurant
+// @Tags Restaurant Tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table body models_restaurant.AddTableData true "Table data"
+// @Router /api/v1/{restaurant_id}/tables [post]
+func CreateTable(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	restaurantUUID, err := uuid.FromString(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	var input models_restaurant.AddTableData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existingTable models_restaurant.RestaurantTable
+	if err := postgres.DB.Where("number = ? AND restaurant_id = ?", input.Number, restaurantUUID).First(&existingTable).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "This Restaurant has a Table with the same number"})
+		return
+	}
+
+	table := models_restaurant.RestaurantTable{
+		RestaurantID: restaurantUUID,
+		Number:       input.Number,
+		Capacity:     input.Capacity,
+		Area:         input.Area,
+		Status:       models_restaurant.TableStatusFree,
+		TokenVersion: 1,
+	}
+	if table.Capacity == 0 {
+		table.Capacity = 2
+	}
+
+	if err := postgres.DB.Create(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create table"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Table created successfully", "table": table})
+}
+
+// GetTables godoc
+// @Summary Retrieve all tables of a restaurant
+// @Description Retrieve all tables of a restaurant, supports ?status= and ?area=
+// @Tags Restaurant Tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param status query string false "Table Status Filter"
+// @Param area query string false "Area Filter"
+// @Router /api/v1/{restaurant_id}/tables [get]
+func GetTables(c *gin.Context) {
+	query := postgres.DB.Where("restaurant_id = ?", c.Param("restaurant_id"))
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if area := c.Query("area"); area != "" {
+		query = query.Where("area = ?", area)
+	}
+
+	var tables []models_restaurant.RestaurantTable
+	if err := query.Order("area, number").Find(&tables).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tables Found Successfully", "tables": tables})
+}
+
+// GetTableByID godoc
+// @Summary Retrieve a table by ID
+// @Description Retrieve a table by ID
+// @Tags Restaurant Tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Router /api/v1/{restaurant_id}/tables/{table_id} [get]
+func GetTableByID(c *gin.Context) {
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), c.Param("restaurant_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Table Found Successfully", "table": table})
+}
+
+// UpdateTable godoc
+// @Summary Update a table
+// @Description Update the number, capacity or area of a table
+// @Tags Restaurant Tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Param table body models_restaurant.UpdateTableData true "Table data"
+// @Router /api/v1/{restaurant_id}/tables/{table_id} [put]
+func UpdateTable(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	var input models_restaurant.UpdateTableData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Number != "" && input.Number != table.Number {
+		var existingTable models_restaurant.RestaurantTable
+		if err := postgres.DB.Where("number = ? AND restaurant_id = ?", input.Number, restaurantID).First(&existingTable).Error; err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "This Restaurant has a Table with the same number"})
+			return
+		}
+		table.Number = input.Number
+	}
+	if input.Capacity != 0 {
+		table.Capacity = input.Capacity
+	}
+	if input.Area != "" {
+		table.Area = input.Area
+	}
+
+	if err := postgres.DB.Save(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Table Updated Successfully", "table": table})
+}
+
+// UpdateTableStatus godoc
+// @Summary Update table status
+// @Description Manually mark a table as free, occupied, reserved or inactive
+// @Tags Restaurant Tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Param status body models_restaurant.UpdateTableStatusData true "New Status"
+// @Router /api/v1/{restaurant_id}/tables/{table_id}/status [put]
+func UpdateTableStatus(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.UpdateTableStatusData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	table.Status = input.Status
+	if err := postgres.DB.Model(&table).Update("status", table.Status).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update table status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Table status updated successfully", "table": table})
+}
+
+// DeleteTable godoc
+// @Summary Delete a table
+// @Description Delete a table by ID. Occupied tables cannot be deleted.
+// @Tags Restaurant Tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Router /api/v1/{restaurant_id}/tables/{table_id} [delete]
+func DeleteTable(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	if table.Status == models_restaurant.TableStatusOccupied {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete an occupied table"})
+		return
+	}
+
+	if err := postgres.DB.Delete(&table).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete table"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Table deleted successfully"})
+}
+
+// GetTableQR godoc
+// @Summary Get the QR code of a table
+// @Description Get the signed token and public menu URL of a table. Use ?format=png to download the QR image.
+// @Tags Restaurant Tables
+// @Produce json
+// @Produce png
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Param format query string false "json (default) or png"
+// @Param size query int false "PNG size in pixels (default 256)"
+// @Router /api/v1/{restaurant_id}/tables/{table_id}/qr [get]
+func GetTableQR(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	qr, err := tableQR(table)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate table token"})
+		return
+	}
+
+	if c.Query("format") == "png" {
+		size, err := strconv.Atoi(c.DefaultQuery("size", "256"))
+		if err != nil || size < 64 || size > 2048 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size"})
+			return
+		}
+
+		png, err := qrcode.Encode(qr.MenuURL, qrcode.Medium, size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"table-%s.png\"", table.Number))
+		c.Data(http.StatusOK, "image/png", png)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"qr": qr})
+}
+
+// RotateTableQR godoc
+// @Summary Rotate the QR code of a table
+// @Description Issue a new token for a table. Previously printed QR codes stop working.
+// @Tags Restaurant Tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param table_id path string true "Table ID"
+// @Router /api/v1/{restaurant_id}/tables/{table_id}/qr/rotate [post]
+func RotateTableQR(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var table models_restaurant.RestaurantTable
+	if err := postgres.DB.First(&table, "id = ? AND restaurant_id = ?", c.Param("table_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		return
+	}
+
+	table.TokenVersion++
+	if err := postgres.DB.Model(&table).Update("token_version", table.TokenVersion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate table token"})
+		return
+	}
+
+	qr, err := tableQR(table)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate table token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Table QR code rotated successfully", "qr": qr})
+}
+
+// ScanTable godoc
+// @Summary Open the menu of a table
+// @Description Resolve a table QR token into the restaurant, the table and its menus
+// @Tags Restaurant Tables
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param token query string true "Table token"
+// @Param diet query string false "Only items suitable for these diets, comma separated (vegetarian, vegan, jain, gluten_free, halal)"
+// @Param exclude_allergens query string false "Leave out items containing these allergens, comma separated (e.g. nuts,dairy)"
+// @Param lang query string false "Language of the names and descriptions, e.g. fr"
+// @Param Accept-Language header string false "Languages the guest reads, used when lang is not given"
+// @Router /api/v1/{restaurant_id}/tables/scan [get]
+func ScanTable(c *gin.Context) {
+	restaurantUUID, err := uuid.FromString(c.Param("restaurant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID format"})
+		return
+	}
+
+	filter, err := utils.ParseMenuItemFilter(c.QueryArray("diet"), c.QueryArray("exclude_allergens"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	table, err := ResolveTableToken(postgres.DB, restaurantUUID, c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.First(&restaurant, "id = ? AND is_active = ?", restaurantUUID, true).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	// Only show the published menus being served right now
+	now := time.Now().In(utils.RestaurantLocation(restaurant))
+	servedMenus, err := services_menu.ServedMenus(postgres.DB, restaurantUUID.String(), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus"})
+		return
+	}
+
+	language, translations, err := services_menu.MenuLanguage(c, postgres.DB, restaurantUUID.String(), true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+
+	response := utils.RestaurantResponse([]models_restaurant.Restaurant{restaurant})[0]
+	services_menu.TranslateRestaurant(&response, translations)
+
+	c.JSON(http.StatusOK, gin.H{
+		"restaurant": response,
+		"language":   language,
+		"table": gin.H{
+			"id":     table.ID,
+			"number": table.Number,
+			"area":   table.Area,
+		},
+		"menus": services_menu.TranslateMenus(services_menu.FilterMenuItems(servedMenus, filter), translations),
+	})
+}
+
+// tableQR builds the QR payload of a table
+func tableQR(table models_restaurant.RestaurantTable) (models_restaurant.TableQRResponse, error) {
+	token, err := utils.GenerateTableToken(table.RestaurantID, table.ID, table.TokenVersion)
+	if err != nil {
+		return models_restaurant.TableQRResponse{}, err
+	}
+
+	return models_restaurant.TableQRResponse{
+		TableID: table.ID,
+		Number:  table.Number,
+		Token:   token,
+		MenuURL: utils.ClientURL(fmt.Sprintf("/menu/%s?table=%s", table.RestaurantID, token)),
+	}, nil
+}
+
+// ResolveTableToken validates a table QR token for a restaurant and returns the table it points to
+func ResolveTableToken(db *gorm.DB, restaurantID uuid.UUID, token string) (models_restaurant.RestaurantTable, error) {
+	var table models_restaurant.RestaurantTable
+
+	if token == "" {
+		return table, errors.New("table token is required")
+	}
+
+	tokenRestaurantID, tableID, version, err := utils.ParseTableToken(token)
+	if err != nil || tokenRestaurantID != restaurantID {
+		return table, errors.New("invalid table token")
+	}
+
+	if err := db.First(&table, "id = ? AND restaurant_id = ?", tableID, restaurantID).Error; err != nil {
+		return table, errors.New("table not found")
+	}
+
+	if table.TokenVersion != version {
+		return table, errors.New("table QR code has expired")
+	}
+	if table.Status == models_restaurant.TableStatusInactive {
+		return table, errors.New("table is not in service")
+	}
+
+	return table, nil
+}
+
+// RefreshTableStatus marks a table occupied while it has open orders or an unsettled session and frees it afterwards
+func RefreshTableStatus(tx *gorm.DB, tableID uuid.UUID) error {
+	var table models_restaurant.RestaurantTable
+	if err := tx.First(&table, "id = ?", tableID).Error; err != nil {
+		return fmt.Errorf("table not found")
+	}
+
+	var openOrders int64
+	if err := tx.Model(&models_order.Order{}).
+		Where("table_id = ? AND status NOT IN ?", tableID, []models_order.OrderStatus{models_order.OrderStatusCompleted, models_order.OrderStatusCancelled}).
+		Count(&openOrders).Error; err != nil {
+		return fmt.Errorf("failed to count open orders")
+	}
+
+	// A table stays occupied until the tab of its session is settled
+	var openSessions int64
+	if err := tx.Model(&models_order.TableSession{}).
+		Where("table_id = ? AND status <> ?", tableID, models_order.SessionStatusSettled).
+		Count(&openSessions).Error; err != nil {
+		return fmt.Errorf("failed to count open sessions")
+	}
+
+	status := table.Status
+	if openOrders > 0 || openSessions > 0 {
+		status = models_restaurant.TableStatusOccupied
+	} else if table.Status == models_restaurant.TableStatusOccupied {
+		status = models_restaurant.TableStatusFree
+	}
+
+	if status != table.Status {
+		if err := tx.Model(&table).Update("status", status).Error; err != nil {
+			return fmt.Errorf("failed to update table status")
+		}
+	}
+
+	return nil
+}
+
+// tableCounts returns the number of tables of each of the given restaurants
+func tableCounts(restaurantIDs []uuid.UUID) map[uuid.UUID]int {
+	counts := make(map[uuid.UUID]int)
+	if len(restaurantIDs) == 0 {
+		return counts
+	}
+
+	var rows []struct {
+		RestaurantID uuid.UUID
+		Count        int
+	}
+	postgres.DB.Model(&models_restaurant.RestaurantTable{}).
+		Select("restaurant_id, count(*) AS count").
+		Where("restaurant_id IN ?", restaurantIDs).
+		Group("restaurant_id").
+		Scan(&rows)
+
+	for _, row := range rows {
+		counts[row.RestaurantID] = row.Count
+	}
+	return counts
+}
+
+// ensureTables creates numbered tables until the restaurant has at least n of them
+func ensureTables(restaurantID uuid.UUID, n int) error {
+	var tables []models_restaurant.RestaurantTable
+	if err := postgres.DB.Where("restaurant_id = ?", restaurantID).Find(&tables).Error; err != nil {
+		return err
+	}
+
+	existingNumbers := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		existingNumbers[table.Number] = true
+	}
+
+	for number := 1; len(tables) < n; number++ {
+		if existingNumbers[strconv.Itoa(number)] {
+			continue
+		}
+		table := models_restaurant.RestaurantTable{
+			RestaurantID: restaurantID,
+			Number:       strconv.Itoa(number),
+			Capacity:     2,
+			Status:       models_restaurant.TableStatusFree,
+			TokenVersion: 1,
+		}
+		if err := postgres.DB.Create(&table).Error; err != nil {
+			return err
+		}
+		tables = append(tables, table)
+	}
+
+	return nil
+}
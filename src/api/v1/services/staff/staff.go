@@ -0,0 +1,419 @@
+package services_staff
+
+import (
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/mail"
+	"dine-server/src/config/sms"
+	models_restaurant "dine-server/src/models/restaurants"
+	models_user "dine-server/src/models/users"
+	"dine-server/src/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const invitationAge = 7 * 24 * time.Hour
+
+// GetStaff lists the owner and staff of a restaurant
+// @Summary Get restaurant staff
+// @Tags Staff
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/staff [get]
+func GetStaff(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "admin_id", "created_at").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	var owner models_user.User
+	if err := postgres.DB.First(&owner, "id = ?", restaurant.AdminID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant owner"})
+		return
+	}
+
+	var members []models_restaurant.StaffMemberResponse
+	if err := postgres.DB.Table("staff_members").
+		Select("staff_members.id, staff_members.user_id, users.name, users.email, users.phone, staff_members.role, staff_members.created_at").
+		Joins("JOIN users ON users.id = staff_members.user_id").
+		Where("staff_members.restaurant_id = ?", restaurantID).
+		Order("staff_members.created_at").
+		Scan(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch staff"})
+		return
+	}
+
+	staff := append([]models_restaurant.StaffMemberResponse{{
+		UserID:    owner.ID,
+		Name:      owner.Name,
+		Email:     owner.Email,
+		Phone:     owner.Phone,
+		Role:      models_restaurant.StaffRoleOwner,
+		CreatedAt: restaurant.CreatedAt,
+	}}, members...)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff Found Successfully", "staff": staff})
+}
+
+// UpdateStaffMember changes the role of a member of the staff
+// @Summary Change a staff role
+// @Description Owners change any role, managers only change cashiers, waiters and kitchen staff.
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param member_id path string true "Member ID"
+// @Param member body models_restaurant.UpdateStaffMemberData true "Role"
+// @Router /api/v1/{restaurant_id}/staff/{member_id} [put]
+func UpdateStaffMember(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.UpdateStaffMemberData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var member models_restaurant.StaffMember
+	if err := postgres.DB.First(&member, "id = ? AND restaurant_id = ?", c.Param("member_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Staff member not found"})
+		return
+	}
+
+	role, ok := callerRole(c, restaurantID)
+	if !ok {
+		return
+	}
+	if !role.Manages(member.Role) || !role.Manages(input.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("A %s cannot change a %s into a %s", role, member.Role, input.Role)})
+		return
+	}
+
+	if err := postgres.DB.Model(&member).Update("role", input.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update staff member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff Member Updated Successfully", "member": member})
+}
+
+// RemoveStaffMember removes a member from the staff, they lose access to the restaurant
+// @Summary Remove a staff member
+// @Tags Staff
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param member_id path string true "Member ID"
+// @Router /api/v1/{restaurant_id}/staff/{member_id} [delete]
+func RemoveStaffMember(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var member models_restaurant.StaffMember
+	if err := postgres.DB.First(&member, "id = ? AND restaurant_id = ?", c.Param("member_id"), restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Staff member not found"})
+		return
+	}
+
+	role, ok := callerRole(c, restaurantID)
+	if !ok {
+		return
+	}
+	if !role.Manages(member.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("A %s cannot remove a %s", role, member.Role)})
+		return
+	}
+
+	if err := postgres.DB.Delete(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove staff member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff member removed successfully"})
+}
+
+// InviteStaff invites someone to the staff by email or SMS
+// @Summary Invite a staff member
+// @Description Send an invitation link to an email or a phone. It is accepted by a signed in user with that email or phone and expires after 7 days.
+// @Description Inviting the same email or phone again replaces the pending invitation.
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param invitation body models_restaurant.InviteStaffData true "Invitation data"
+// @Router /api/v1/{restaurant_id}/staff/invitations [post]
+func InviteStaff(c *gin.Context) {
+	restaurantID := c.Param("restaurant_id")
+
+	var input models_restaurant.InviteStaffData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	input.Phone = strings.TrimSpace(input.Phone)
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "name", "admin_id").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	role, ok := callerRole(c, restaurantID)
+	if !ok {
+		return
+	}
+	if !role.Manages(input.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("A %s cannot invite a %s", role, input.Role)})
+		return
+	}
+
+	// Nobody already part of the staff can be invited
+	var existing int64
+	if err := postgres.DB.Model(&models_user.User{}).
+		Where("(users.id = ? OR users.id IN (SELECT user_id FROM staff_members WHERE restaurant_id = ?))", restaurant.AdminID, restaurant.ID).
+		Where(contactQuery("users", input.Email, input.Phone)).
+		Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check staff"})
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This user is already part of the staff"})
+		return
+	}
+
+	token, err := utils.GenerateSecretToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invitation"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	invitedBy, _ := uuid.FromString(fmt.Sprint(userID))
+	invitation := models_restaurant.StaffInvitation{
+		RestaurantID: restaurant.ID,
+		Email:        input.Email,
+		Phone:        input.Phone,
+		Role:         input.Role,
+		TokenHash:    utils.HashSecretToken(token),
+		InvitedBy:    invitedBy,
+		ExpiresAt:    time.Now().Add(invitationAge),
+	}
+
+	tx := postgres.DB.Begin()
+
+	// Only the latest invitation of an email or phone is valid
+	if err := tx.Where("restaurant_id = ? AND accepted_at IS NULL", restaurant.ID).
+		Where(contactQuery("staff_invitations", input.Email, input.Phone)).
+		Delete(&models_restaurant.StaffInvitation{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+	if err := tx.Create(&invitation).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+
+	link := utils.ClientURL("/invitations/accept?token=" + token)
+	message := fmt.Sprintf("You are invited to join %s as %s. Accept the invitation within %d days: %s", restaurant.Name, input.Role, int(invitationAge.Hours()/24), link)
+	if input.Email != "" {
+		err = mail.Client.Send(input.Email, "Join "+restaurant.Name, message)
+	} else {
+		err = sms.Client.Send(input.Phone, message)
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send invitation"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Invitation Sent Successfully", "invitation": invitation})
+}
+
+// GetStaffInvitations lists the pending invitations of a restaurant
+// @Summary Get staff invitations
+// @Tags Staff
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Router /api/v1/{restaurant_id}/staff/invitations [get]
+func GetStaffInvitations(c *gin.Context) {
+	var invitations []models_restaurant.StaffInvitation
+	if err := postgres.DB.Where("restaurant_id = ? AND accepted_at IS NULL AND expires_at > ?", c.Param("restaurant_id"), time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitations Found Successfully", "invitations": invitations})
+}
+
+// RevokeStaffInvitation cancels a pending invitation
+// @Summary Revoke a staff invitation
+// @Tags Staff
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Router /api/v1/{restaurant_id}/staff/invitations/{invitation_id} [delete]
+func RevokeStaffInvitation(c *gin.Context) {
+	result := postgres.DB.Where("id = ? AND restaurant_id = ? AND accepted_at IS NULL", c.Param("invitation_id"), c.Param("restaurant_id")).
+		Delete(&models_restaurant.StaffInvitation{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// AcceptStaffInvitation joins the staff of a restaurant with the token of an invitation
+// @Summary Accept a staff invitation
+// @Description The signed in user must have verified the email or phone the invitation was sent to. A member of the staff gets the role of the invitation.
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param invitation body models_restaurant.AcceptInvitationData true "Invitation token"
+// @Router /api/v1/invitations/accept [post]
+func AcceptStaffInvitation(c *gin.Context) {
+	var input models_restaurant.AcceptInvitationData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var invitation models_restaurant.StaffInvitation
+	if err := postgres.DB.Where("token_hash = ? AND accepted_at IS NULL", utils.HashSecretToken(input.Token)).First(&invitation).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		c.JSON(http.StatusGone, gin.H{"error": "The invitation expired, ask for a new one"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	var user models_user.User
+	if err := postgres.DB.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+	// Only a verified email or phone proves the user is the one invited
+	emailMatches := invitation.Email != "" && strings.EqualFold(invitation.Email, user.Email)
+	phoneMatches := invitation.Phone != "" && invitation.Phone == user.Phone
+	if !emailMatches && !phoneMatches {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invitation was sent to someone else"})
+		return
+	}
+	if !(emailMatches && user.VerifiedEmail) && !(phoneMatches && user.VerifiedPhone) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Verify the email or phone this invitation was sent to before accepting it"})
+		return
+	}
+
+	var restaurant models_restaurant.Restaurant
+	if err := postgres.DB.Select("id", "name", "admin_id").First(&restaurant, "id = ?", invitation.RestaurantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if restaurant.AdminID == user.ID {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already own this restaurant"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Accepting only succeeds once, even when the token is sent by parallel requests
+	result := tx.Model(&models_restaurant.StaffInvitation{}).Where("id = ? AND accepted_at IS NULL", invitation.ID).
+		Updates(map[string]interface{}{"accepted_at": time.Now(), "accepted_by": user.ID})
+	if result.Error != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	var member models_restaurant.StaffMember
+	err := tx.Where("restaurant_id = ? AND user_id = ?", restaurant.ID, user.ID).First(&member).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch staff member"})
+		return
+	}
+	member.RestaurantID = restaurant.ID
+	member.UserID = user.ID
+	member.Role = invitation.Role
+	member.InvitedBy = invitation.InvitedBy
+	if err := tx.Save(&member).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join the staff"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation Accepted Successfully", "restaurant_id": restaurant.ID, "restaurant_name": restaurant.Name, "member": member})
+}
+
+// callerRole returns the role of the user of the request in the restaurant, platform admins act as owners.
+// It responds itself when the role cannot be found.
+func callerRole(c *gin.Context, restaurantID string) (models_restaurant.StaffRole, bool) {
+	if role, _ := c.Get("role"); role == "admin" {
+		return models_restaurant.StaffRoleOwner, true
+	}
+
+	userID, _ := c.Get("userID")
+	role, err := utils.StaffRole(restaurantID, fmt.Sprint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch your role"})
+		return "", false
+	}
+	return role, true
+}
+
+// contactQuery matches the rows of a table with the email or the phone given
+func contactQuery(table, email, phone string) *gorm.DB {
+	query := postgres.DB
+	switch {
+	case email != "" && phone != "":
+		return query.Where("LOWER("+table+".email) = ? OR "+table+".phone = ?", email, phone)
+	case email != "":
+		return query.Where("LOWER("+table+".email) = ?", email)
+	default:
+		return query.Where(table+".phone = ?", phone)
+	}
+}
@@ -79,6 +79,7 @@ func CreateSubscription(c *gin.Context) (gin.H, error) {
 		UserID:       order.RestaurantAdminID,
 		OrderID:      order.ID,
 		RestaurantID: order.RestaurantID,
+		BrandID:      order.BrandID,
 		PlanID:       order.PlanID,
 		Plan:         plan,
 		StartDate:    time.Now(),
@@ -92,7 +93,17 @@ func CreateSubscription(c *gin.Context) (gin.H, error) {
 		return nil, fmt.Errorf("failed to create subscription")
 	}
 
-	if err := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("id = ?", order.RestaurantID).Update("subscription_id", subscription.ID).Error; err != nil {
+	// A brand subscription covers the brand and each of its outlets
+	if order.BrandID != nil {
+		if err := postgres.DB.Model(&models_restaurant.Brand{}).Where("id = ?", order.BrandID).Update("subscription_id", subscription.ID).Error; err != nil {
+
+			return nil, fmt.Errorf("failed to update brand subscription ID")
+		}
+		if err := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("brand_id = ?", order.BrandID).Update("subscription_id", subscription.ID).Error; err != nil {
+
+			return nil, fmt.Errorf("failed to update restaurant subscription ID")
+		}
+	} else if err := postgres.DB.Model(&models_restaurant.Restaurant{}).Where("id = ?", order.RestaurantID).Update("subscription_id", subscription.ID).Error; err != nil {
 
 		return nil, fmt.Errorf("failed to update restaurant subscription ID")
 	}
@@ -7,6 +7,7 @@ import (
 	models_user "dine-server/src/models/users"
 	utils "dine-server/src/utils"
 	"encoding/json"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm/clause"
 )
 
 // @BasePath /api/v1
@@ -33,6 +35,10 @@ func RegisterUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
 		return
 	}
+	if err := utils.ValidatePasswordStrength(userData.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
 
 	// Generate a new UUID for the user
 	newUUID, err := uuid.NewV4()
@@ -68,30 +74,22 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT tokens
-	accessToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "ACCESS")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
-		return
+	// The user can ask for a new link if this one does not arrive
+	if _, err := sendVerification(user, models_user.VerificationEmail); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
 	}
 
-	refreshToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "REFRESH")
+	// Sign the user in on this device, setting the token cookies
+	tokens, err := startSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
 		return
 	}
 
-	// Set tokens as cookies
-	c.SetCookie("access_token", accessToken, 3600, "/", "", false, true)
-	c.SetCookie("refresh_token", refreshToken, 3600, "/", "", false, true)
-
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User created successfully",
 		"user":    user,
-		"tokens": gin.H{
-			"access":  accessToken,
-			"refresh": refreshToken,
-		},
+		"tokens":  tokens,
 	})
 }
 
@@ -129,30 +127,17 @@ func LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT tokens
-	accessToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "ACCESS")
+	// Sign the user in on this device, setting the token cookies
+	tokens, err := startSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
 		return
 	}
 
-	refreshToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "REFRESH")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
-		return
-	}
-
-	// Set tokens as cookies
-	c.SetCookie("access_token", accessToken, 3600, "/", "", false, true)
-	c.SetCookie("refresh_token", refreshToken, 3600, "/", "", false, true)
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successful",
 		"user":    user,
-		"tokens": gin.H{
-			"access":  accessToken,
-			"refresh": refreshToken,
-		},
+		"tokens":  tokens,
 	})
 }
 
@@ -264,22 +249,13 @@ func GoogleCallback(c *gin.Context) {
 		}
 	}
 
-	accessToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "ACCESS")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	refreshToken, err := utils.GenerateToken(models_user.UserJwt{ID: user.ID.String(), Role: user.Role}, "REFRESH")
+	// Sign the user in on this device, setting the token cookies
+	tokens, err := startSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
 		return
 	}
 
-	// Set tokens as cookies
-	c.SetCookie("access_token", accessToken, 3600, "/", "", false, true)
-	c.SetCookie("refresh_token", refreshToken, 3600, "/", "", false, true)
-
 	message := "Login successful"
 	if newAccount {
 		message = "Account created successfully"
@@ -288,17 +264,15 @@ func GoogleCallback(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": message,
 		"user":    user,
-		"tokens": gin.H{
-			"access":  accessToken,
-			"refresh": refreshToken,
-		},
+		"tokens":  tokens,
 	})
 
 }
 
-// RefreshToken generates a new access token using a valid refresh token
+// RefreshToken rotates the refresh token of a session and generates a new access token.
+// A refresh token that was already rotated means it was copied, the session is then revoked for every holder.
 // @Summary Refresh Access Token
-// @Description Generate a new access token using a refresh token
+// @Description Generate new access and refresh tokens using a refresh token. Each refresh token can be used once.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -312,38 +286,104 @@ func RefreshToken(c *gin.Context) {
 	}
 
 	// Validate and extract claims from the refresh token
-	userID, role, err := utils.ValidateAndExtractToken(refreshToken, "REFRESH")
+	claims, err := utils.ValidateToken(refreshToken, "REFRESH")
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 		return
 	}
 
-	// Generate a new access token
-	accessToken, err := utils.GenerateToken(models_user.UserJwt{ID: userID, Role: role}, "ACCESS")
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Lock the session so two refreshes cannot both rotate it
+	var session models_user.UserSession
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, "id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", claims.SessionID, claims.ID, time.Now()).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	if session.RefreshHash != utils.HashSecretToken(refreshToken) {
+		if err := revokeSessions(tx.Where("id = ?", session.ID), claims.ID, models_user.SessionReused).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+			return
+		}
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+			return
+		}
+
+		log.Printf("Refresh token of session %s of user %s was reused, session revoked", session.ID, claims.ID)
+		clearTokenCookies(c)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token was already used, the session was signed out, please log in again"})
+		return
+	}
+
+	// The role is read again so a changed role takes effect on refresh
+	var user models_user.User
+	if err := tx.First(&user, "id = ?", session.UserID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	accessToken, newRefreshToken, err := signSession(c, &session, user.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
+		return
+	}
+	if err := tx.Save(&session).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate refresh token"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
 		return
 	}
 
-	// Set the new access token as a cookie
-	c.SetCookie("access_token", accessToken, 3600, "/", "", false, true)
+	setTokenCookies(c, accessToken, newRefreshToken)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Access token refreshed successfully",
 		"access":  accessToken,
+		"refresh": newRefreshToken,
 	})
 }
 
-// LogoutUser clears user authentication cookies
+// LogoutUser ends the session of the request and clears user authentication cookies
 // @Summary Logout a user
-// @Description Clear user authentication cookies
+// @Description Revoke the session of the request and clear user authentication cookies
 // @Tags Auth
 // @Produce json
 // @Router /api/v1/auth/logout [post]
 func LogoutUser(c *gin.Context) {
+	// The session is known from the access token, or the refresh token once the access token expired
+	sessionID := c.GetString("sessionID")
+	if sessionID == "" {
+		if refreshToken, err := c.Cookie("refresh_token"); err == nil && refreshToken != "" {
+			if claims, err := utils.ValidateToken(refreshToken, "REFRESH"); err == nil {
+				sessionID = claims.SessionID
+			}
+		}
+	}
+
+	if sessionID != "" {
+		if err := postgres.DB.Model(&models_user.UserSession{}).Where("id = ? AND revoked_at IS NULL", sessionID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": models_user.SessionLogout}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end session"})
+			return
+		}
+	}
+
 	// Clear authentication cookies
-	c.SetCookie("access_token", "", -1, "/", "", false, true)
-	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+	clearTokenCookies(c)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logged out successfully",
@@ -0,0 +1,258 @@
+package services_user
+
+import (
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/mail"
+	models_user "dine-server/src/models/users"
+	"dine-server/src/utils"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const passwordResetAge = time.Hour
+
+// Per email limit on reset links, on top of the per IP limits applied to the routes
+var passwordResetLimiter = utils.NewRateLimiter(3, time.Hour)
+
+// ForgotPassword emails a link to reset the password
+// @Summary Request a password reset
+// @Description Email a single-use link to reset the password. The response is the same whether the email has an account or not.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param email body models_user.ForgotPasswordData true "Email of the account"
+// @Router /api/v1/auth/password/forgot [post]
+func ForgotPassword(c *gin.Context) {
+	var input models_user.ForgotPasswordData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Who has an account is not revealed, every request gets the same answer
+	response := gin.H{"message": "If an account exists for this email, a reset link has been sent"}
+
+	if ok, _ := passwordResetLimiter.Allow(strings.ToLower(input.Email)); !ok {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	var user models_user.User
+	if err := postgres.DB.Where("email = ? AND signup_source = ?", input.Email, "website").First(&user).Error; err != nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := utils.GenerateSecretToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate reset link"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Only the latest link is valid
+	if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models_user.PasswordReset{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate reset link"})
+		return
+	}
+
+	reset := models_user.PasswordReset{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserID:    user.ID,
+		TokenHash: utils.HashSecretToken(token),
+		ExpiresAt: time.Now().Add(passwordResetAge),
+	}
+	if err := tx.Create(&reset).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate reset link"})
+		return
+	}
+
+	link := utils.ClientURL("/reset-password?token=" + token)
+	message := fmt.Sprintf("Hi %s, reset your password within %d minutes: %s\nIf you did not ask for it, you can ignore this email.", user.Name, int(passwordResetAge.Minutes()), link)
+	if err := mail.Client.Send(user.Email, "Reset your password", message); err != nil {
+		// Answered like any other request, an error would tell the email has an account
+		tx.Rollback()
+		log.Printf("Failed to send password reset link to user %s: %v", user.ID, err)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password with the token of a reset link and signs out every session
+// @Summary Reset a password
+// @Description Set a new password with the token of the emailed link. The link works once and every session of the user is signed out.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param reset body models_user.ResetPasswordData true "Token and new password"
+// @Router /api/v1/auth/password/reset [post]
+func ResetPassword(c *gin.Context) {
+	var input models_user.ResetPasswordData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := utils.ValidatePasswordStrength(input.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Lock the link so two requests cannot both use it
+	now := time.Now()
+	var reset models_user.PasswordReset
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashSecretToken(input.Token), now).
+		First(&reset).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Link expired or already used, please request a new one"})
+		return
+	}
+	if err := tx.Model(&reset).Update("used_at", now).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	var user models_user.User
+	if err := tx.First(&user, "id = ?", reset.UserID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := setPassword(tx, &user, hashedPassword, ""); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+	if err := tx.Where("user_id = ? AND used_at IS NULL", user.ID).Delete(&models_user.PasswordReset{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	notifyPasswordChanged(user)
+
+	// The browser that reset the password is signed out like the others
+	clearTokenCookies(c)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password Reset Successfully, please log in again"})
+}
+
+// ChangePassword sets a new password after checking the current one.
+// The other sessions of the user are signed out, this one stays signed in.
+// @Summary Change the password
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param password body models_user.ChangePasswordData true "Current and new password"
+// @Router /api/v1/auth/password/change [post]
+func ChangePassword(c *gin.Context) {
+	var input models_user.ChangePasswordData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.SignupSource != "website" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Your account signs in with " + user.SignupSource + " and has no password"})
+		return
+	}
+	if !utils.CheckPassword(user.Password, input.CurrentPassword) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+	if input.NewPassword == input.CurrentPassword {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "New password must be different from the current one"})
+		return
+	}
+	if err := utils.ValidatePasswordStrength(input.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		return setPassword(tx, &user, hashedPassword, c.GetString("sessionID"))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change password"})
+		return
+	}
+
+	notifyPasswordChanged(user)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password Changed Successfully, your other sessions were signed out"})
+}
+
+// setPassword saves a hashed password and signs the user out of every session but the one to keep
+func setPassword(db *gorm.DB, user *models_user.User, hashedPassword string, keepSessionID string) error {
+	changedAt := time.Now()
+	user.Password = hashedPassword
+	user.PasswordChangedAt = &changedAt
+	if err := db.Model(user).Updates(map[string]interface{}{"password": hashedPassword, "password_changed_at": changedAt}).Error; err != nil {
+		return err
+	}
+
+	query := db
+	if keepSessionID != "" {
+		query = db.Where("id <> ?", keepSessionID)
+	}
+	return revokeSessions(query, user.ID.String(), models_user.SessionPassword).Error
+}
+
+// notifyPasswordChanged tells the user their password changed, in case it was not them
+func notifyPasswordChanged(user models_user.User) {
+	message := fmt.Sprintf("Hi %s, the password of your account was changed and your other sessions were signed out.\nIf it was not you, reset your password now: %s", user.Name, utils.ClientURL("/forgot-password"))
+	if err := mail.Client.Send(user.Email, "Your password was changed", message); err != nil {
+		log.Printf("Failed to send password change notice to user %s: %v", user.ID, err)
+	}
+}
@@ -0,0 +1,144 @@
+package services_user
+
+import (
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/env"
+	models_user "dine-server/src/models/users"
+	"dine-server/src/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// GetSessions lists the devices the user is signed in on
+// @Summary List active sessions
+// @Description List the devices the user is signed in on, the session of the request is marked as current.
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Router /api/v1/auth/sessions [get]
+func GetSessions(c *gin.Context) {
+	var sessions []models_user.UserSession
+	if err := postgres.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", c.GetString("userID"), time.Now()).
+		Order("last_used_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == c.GetString("sessionID")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions Found Successfully", "sessions": sessions})
+}
+
+// RevokeSession signs the user out of one of their sessions
+// @Summary Revoke a session
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Session ID"
+// @Router /api/v1/auth/sessions/{id} [delete]
+func RevokeSession(c *gin.Context) {
+	result := revokeSessions(postgres.DB.Where("id = ?", c.Param("id")), c.GetString("userID"), models_user.SessionRevoked)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if c.Param("id") == c.GetString("sessionID") {
+		clearTokenCookies(c)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeOtherSessions signs the user out of every session but the one of the request
+// @Summary Revoke the other sessions
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Router /api/v1/auth/sessions [delete]
+func RevokeOtherSessions(c *gin.Context) {
+	result := revokeSessions(postgres.DB.Where("id <> ?", c.GetString("sessionID")), c.GetString("userID"), models_user.SessionRevoked)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Other sessions revoked successfully", "revoked": result.RowsAffected})
+}
+
+// startSession signs the user in on the device of the request, sets the token cookies and returns the tokens
+func startSession(c *gin.Context, user models_user.User) (gin.H, error) {
+	session := models_user.UserSession{
+		ID:     uuid.Must(uuid.NewV4()),
+		UserID: user.ID,
+		Device: utils.DeviceName(c.Request.UserAgent()),
+	}
+
+	accessToken, refreshToken, err := signSession(c, &session, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if err := postgres.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	setTokenCookies(c, accessToken, refreshToken)
+	return gin.H{"access": accessToken, "refresh": refreshToken}, nil
+}
+
+// signSession generates new tokens for a session and records the hash of the refresh token and the device
+// it was issued to. The caller saves the session, only the latest refresh token of a session is accepted.
+func signSession(c *gin.Context, session *models_user.UserSession, role string) (string, string, error) {
+	claims := models_user.UserJwt{ID: session.UserID.String(), Role: role, SessionID: session.ID.String()}
+	accessToken, err := utils.GenerateToken(claims, "ACCESS")
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := utils.GenerateToken(claims, "REFRESH")
+	if err != nil {
+		return "", "", err
+	}
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session.RefreshHash = utils.HashSecretToken(refreshToken)
+	session.IP = c.ClientIP()
+	session.UserAgent = userAgent
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(time.Duration(utils.ParseDuration(env.AuthVar["REFRESH_TOKEN_AGE"], 86400)) * time.Second)
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeSessions ends the active sessions of the user matched by the query
+func revokeSessions(query *gorm.DB, userID string, reason models_user.SessionRevokedReason) *gorm.DB {
+	return query.Model(&models_user.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason})
+}
+
+// setTokenCookies sets the token cookies for as long as the tokens are valid
+func setTokenCookies(c *gin.Context, accessToken, refreshToken string) {
+	c.SetCookie("access_token", accessToken, int(utils.ParseDuration(env.AuthVar["ACCESS_TOKEN_AGE"], 3600)), "/", "", false, true)
+	c.SetCookie("refresh_token", refreshToken, int(utils.ParseDuration(env.AuthVar["REFRESH_TOKEN_AGE"], 86400)), "/", "", false, true)
+}
+
+// clearTokenCookies removes the token cookies
+func clearTokenCookies(c *gin.Context) {
+	c.SetCookie("access_token", "", -1, "/", "", false, true)
+	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+}
@@ -4,7 +4,6 @@ package services_user
 import (
 	postgres "dine-server/src/config/database"
 	models_user "dine-server/src/models/users"
-	utils "dine-server/src/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -131,7 +130,7 @@ func GetUser(c *gin.Context) {
 
 // UpdateUser updates the user information
 // @Summary Update user information
-// @Description Update the user information
+// @Description Update the name, email or phone of the user, the password is changed with /auth/password/change
 // @Tags User
 // @Produce json
 // @Param user body models_user.UpdateUserDataByUser true "User data"
@@ -156,34 +155,32 @@ func UpdateUserByUser(c *gin.Context) {
 		return
 	}
 
-	if input.Password != "" {
-		hashedPassword, err := utils.HashPassword(input.Password)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
-			return
-		}
-		input.Password = hashedPassword
-	}
-
-	if input.Password != "" {
-		user.Password = input.Password
-	}
-
+	// The profile and the verified flags are saved together, a new email or phone has to be verified again
+	updates := map[string]interface{}{}
 	if input.Name != "" {
 		user.Name = input.Name
+		updates["name"] = input.Name
 	}
 
-	if input.Email != "" {
+	if input.Email != "" && input.Email != user.Email {
 		user.Email = input.Email
+		user.VerifiedEmail = false
+		updates["email"] = input.Email
+		updates["verified_email"] = false
 	}
 
-	if input.Phone != "" {
+	if input.Phone != "" && input.Phone != user.Phone {
 		user.Phone = input.Phone
+		user.VerifiedPhone = false
+		updates["phone"] = input.Phone
+		updates["verified_phone"] = false
 	}
 
-	if err := postgres.DB.Model(&user).Updates(input).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
-		return
+	if len(updates) > 0 {
+		if err := postgres.DB.Model(&user).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,285 @@
+package services_user
+
+import (
+	"crypto/hmac"
+	postgres "dine-server/src/config/database"
+	"dine-server/src/config/mail"
+	"dine-server/src/config/sms"
+	models_user "dine-server/src/models/users"
+	"dine-server/src/utils"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	emailVerificationAge   = 24 * time.Hour
+	phoneVerificationAge   = 10 * time.Minute
+	phoneVerifyMaxAttempts = 5
+)
+
+// Per user limits on sending codes, on top of the per IP limits applied to the routes
+var (
+	emailVerificationLimiter = utils.NewRateLimiter(3, time.Hour)
+	phoneVerificationLimiter = utils.NewRateLimiter(3, 10*time.Minute)
+)
+
+// SendEmailVerification emails the user a link to verify their email
+// @Summary Send an email verification link
+// @Description Email a link to verify the email of the user. Sending again replaces the previous link.
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Router /api/v1/auth/verify/email/send [post]
+func SendEmailVerification(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.VerifiedEmail {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already verified"})
+		return
+	}
+	if !allowVerification(c, emailVerificationLimiter, user.ID) {
+		return
+	}
+
+	verification, err := sendVerification(user, models_user.VerificationEmail)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification Email Sent Successfully", "expires_at": verification.ExpiresAt})
+}
+
+// VerifyEmail marks the email of a user as verified with the token of the link sent to it
+// @Summary Verify an email
+// @Description Verify an email with the token of the link sent to it. The link can be opened without being signed in.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verification body models_user.VerifyEmailData true "Token of the link"
+// @Router /api/v1/auth/verify/email [post]
+func VerifyEmail(c *gin.Context) {
+	var input models_user.VerifyEmailData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var verification models_user.UserVerification
+	if err := postgres.DB.Where("channel = ? AND code_hash = ? AND verified_at IS NULL AND expires_at > ?",
+		models_user.VerificationEmail, utils.HashSecretToken(input.Token), time.Now()).First(&verification).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Link expired or not found, please request a new one"})
+		return
+	}
+
+	completeVerification(c, verification)
+}
+
+// SendPhoneVerification texts the user a code to verify their phone
+// @Summary Send a phone verification code
+// @Description Text a one-time code to the phone of the user. Sending again replaces the previous code.
+// @Tags Auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Router /api/v1/auth/verify/phone/send [post]
+func SendPhoneVerification(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.VerifiedPhone {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Phone already verified"})
+		return
+	}
+	if user.Phone == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Add a phone to your account first"})
+		return
+	}
+	if !allowVerification(c, phoneVerificationLimiter, user.ID) {
+		return
+	}
+
+	verification, err := sendVerification(user, models_user.VerificationPhone)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send verification code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification Code Sent Successfully", "expires_at": verification.ExpiresAt})
+}
+
+// VerifyPhone marks the phone of the user as verified with the code texted to it
+// @Summary Verify a phone
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param verification body models_user.VerifyPhoneData true "Code texted to the phone"
+// @Router /api/v1/auth/verify/phone [post]
+func VerifyPhone(c *gin.Context) {
+	var input models_user.VerifyPhoneData
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	var verification models_user.UserVerification
+	if err := postgres.DB.Where("user_id = ? AND channel = ? AND verified_at IS NULL AND expires_at > ?", userID, models_user.VerificationPhone, time.Now()).
+		Order("created_at DESC").First(&verification).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code expired or not found, please request a new one"})
+		return
+	}
+
+	// Count the attempt before checking the code, in one statement so parallel guesses cannot pass the limit
+	result := postgres.DB.Model(&models_user.UserVerification{}).
+		Where("id = ? AND attempts < ?", verification.ID, phoneVerifyMaxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, please request a new code"})
+		return
+	}
+
+	codeHash, err := utils.HashOTP(input.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify code"})
+		return
+	}
+	if !hmac.Equal([]byte(codeHash), []byte(verification.CodeHash)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	completeVerification(c, verification)
+}
+
+// sendVerification sends a new code to the email or phone of the user, replacing the pending one
+func sendVerification(user models_user.User, channel models_user.VerificationChannel) (models_user.UserVerification, error) {
+	verification := models_user.UserVerification{
+		ID:      uuid.Must(uuid.NewV4()),
+		UserID:  user.ID,
+		Channel: channel,
+	}
+
+	var code string
+	var err error
+	if channel == models_user.VerificationEmail {
+		if code, err = utils.GenerateSecretToken(); err != nil {
+			return verification, err
+		}
+		verification.Target = user.Email
+		verification.CodeHash = utils.HashSecretToken(code)
+		verification.ExpiresAt = time.Now().Add(emailVerificationAge)
+	} else {
+		if code, err = utils.GenerateOTP(6); err != nil {
+			return verification, err
+		}
+		verification.Target = user.Phone
+		if verification.CodeHash, err = utils.HashOTP(code); err != nil {
+			return verification, err
+		}
+		verification.ExpiresAt = time.Now().Add(phoneVerificationAge)
+	}
+
+	tx := postgres.DB.Begin()
+	if tx.Error != nil {
+		return verification, tx.Error
+	}
+
+	// Only the latest code of a channel is valid
+	if err := tx.Where("user_id = ? AND channel = ? AND verified_at IS NULL", user.ID, channel).
+		Delete(&models_user.UserVerification{}).Error; err != nil {
+		tx.Rollback()
+		return verification, err
+	}
+	if err := tx.Create(&verification).Error; err != nil {
+		tx.Rollback()
+		return verification, err
+	}
+
+	if channel == models_user.VerificationEmail {
+		link := utils.ClientURL("/verify-email?token=" + code)
+		err = mail.Client.Send(user.Email, "Verify your email", fmt.Sprintf("Hi %s, please verify your email within %d hours: %s", user.Name, int(emailVerificationAge.Hours()), link))
+	} else {
+		err = sms.Client.Send(user.Phone, fmt.Sprintf("%s is your Dine verification code. It expires in %d minutes.", code, int(phoneVerificationAge.Minutes())))
+	}
+	if err != nil {
+		tx.Rollback()
+		return verification, err
+	}
+
+	return verification, tx.Commit().Error
+}
+
+// completeVerification marks the verification and the email or phone of its user as verified.
+// It responds itself, refusing codes sent to an email or phone the user has since changed.
+func completeVerification(c *gin.Context, verification models_user.UserVerification) {
+	var user models_user.User
+	if err := postgres.DB.First(&user, "id = ?", verification.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	column := "verified_email"
+	target := user.Email
+	if verification.Channel == models_user.VerificationPhone {
+		column = "verified_phone"
+		target = user.Phone
+	}
+	if target != verification.Target {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The " + string(verification.Channel) + " of the account changed, please request a new code"})
+		return
+	}
+
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&verification).Update("verified_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update(column, true).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify " + string(verification.Channel)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verified Successfully", "user": user})
+}
+
+// currentUser loads the user of the request, it responds itself when the user cannot be found
+func currentUser(c *gin.Context) (models_user.User, bool) {
+	var user models_user.User
+	userID, _ := c.Get("userID")
+	if err := postgres.DB.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		}
+		return user, false
+	}
+	return user, true
+}
+
+// allowVerification applies the per user limit on sending codes, it responds itself when the limit is reached
+func allowVerification(c *gin.Context, limiter *utils.RateLimiter, userID uuid.UUID) bool {
+	if ok, retryAfter := limiter.Allow(userID.String()); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many codes requested, please try again later"})
+		return false
+	}
+	return true
+}
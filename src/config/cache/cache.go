@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is a small in-memory key/value cache with expiry.
+// Entries live in the process, so each server instance keeps its own copy.
+type Store struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry
+}
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// New creates a store whose entries expire after ttl
+func New(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, entries: make(map[string]entry)}
+}
+
+// Get returns the value stored under key if it has not expired
+func (s *Store) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key
+func (s *Store) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = entry{value: value, expiresAt: now.Add(s.ttl)}
+}
+
+// Delete removes key from the store
+func (s *Store) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// MenuTrees caches the full menu tree of each restaurant, keyed by restaurant ID.
+// Every handler writing menus, categories, items or options must delete the restaurant's entry.
+var MenuTrees = New(10 * time.Minute)
@@ -3,6 +3,8 @@ package postgres
 import (
 	"dine-server/src/config/env" // Adjust to the actual path
 	models_common "dine-server/src/models/Common"
+	models_inventory "dine-server/src/models/inventory"
+	models_kitchen "dine-server/src/models/kitchen"
 	models_menu "dine-server/src/models/menu"
 	models_order "dine-server/src/models/orders"
 	models_payment "dine-server/src/models/payments"
@@ -24,19 +26,47 @@ var DB *gorm.DB
 type (
 	Plan                  = models_plan.Plan
 	User                  = models_user.User
+	UserVerification      = models_user.UserVerification
+	PasswordReset         = models_user.PasswordReset
+	UserSession           = models_user.UserSession
 	Restaurant            = models_restaurant.Restaurant
 	RestaurantBankAccount = models_restaurant.RestaurantBankAccount
+	RestaurantTable       = models_restaurant.RestaurantTable
+	OpeningHour           = models_restaurant.OpeningHour
+	RestaurantClosure     = models_restaurant.RestaurantClosure
+	Translation           = models_restaurant.Translation
+	Image                 = models_restaurant.Image
+	Brand                 = models_restaurant.Brand
+	StaffMember           = models_restaurant.StaffMember
+	StaffInvitation       = models_restaurant.StaffInvitation
 	Menu                  = models_menu.Menu
 	MenuItem              = models_menu.MenuItem
 	MenuCategory          = models_menu.MenuCategory
+	MenuSchedule          = models_menu.MenuSchedule
+	MenuVersion           = models_menu.MenuVersion
+	ModifierGroup         = models_menu.ModifierGroup
+	Modifier              = models_menu.Modifier
+	Combo                 = models_menu.Combo
+	ComboSlot             = models_menu.ComboSlot
+	ComboSlotItem         = models_menu.ComboSlotItem
+	PricingRule           = models_menu.PricingRule
+	MenuTemplate          = models_menu.MenuTemplate
+	MenuOverride          = models_menu.MenuOverride
 
 	MenuItemOption  = models_menu.MenuItemOption
 	RestaurantOrder = models_order.Order
+	TableSession    = models_order.TableSession
+	SessionPayment  = models_order.SessionPayment
 	DinePayment     = models_payment.DinePayment
 
+	GuestVerification    = models_order.GuestVerification
+	RestaurantOrderRules = models_order.RestaurantOrderRules
+
 	DineOrder           = models_order.DineOrder
 	Subscription        = models_subscription.Subscription
 	RestaurantOrderItem = models_order.OrderItem
+	OrderItemModifier   = models_order.OrderItemModifier
+	OrderCombo          = models_order.OrderCombo
 
 	PlanFeature            = models_plan.PlanFeature
 	PlanFeatureAssociation = models_plan.PlanFeatureAssociation
@@ -44,6 +74,22 @@ type (
 	RestaurantsCount = models_common.RestaurantsCount
 
 	DinePromoCode = models_promoCode.DinePromoCode
+
+	KitchenStation    = models_kitchen.Station
+	KitchenTicket     = models_kitchen.KitchenTicket
+	KitchenTicketItem = models_kitchen.KitchenTicketItem
+
+	ItemStock       = models_inventory.ItemStock
+	StockMovement   = models_inventory.StockMovement
+	StockAlert      = models_inventory.StockAlert
+	EightySixedItem = models_inventory.EightySixedItem
+
+	Ingredient          = models_inventory.Ingredient
+	RecipeLine          = models_inventory.RecipeLine
+	Supplier            = models_inventory.Supplier
+	PurchaseReceipt     = models_inventory.PurchaseReceipt
+	PurchaseReceiptLine = models_inventory.PurchaseReceiptLine
+	IngredientMovement  = models_inventory.IngredientMovement
 )
 
 // InitDB initializes the PostgreSQL database connection and runs migrations.
@@ -91,21 +137,61 @@ func ensureUUIDExtension() error {
 func migrateModels() error {
 	return DB.AutoMigrate(
 		&User{},
+		&UserVerification{},
+		&PasswordReset{},
+		&UserSession{},
 		&Plan{},
 		&PlanFeature{},
 		&PlanFeatureAssociation{},
 		&Restaurant{},
+		&Brand{},
+		&StaffMember{},
+		&StaffInvitation{},
 		&Menu{},
 		&MenuCategory{},
 		&MenuItem{},
+		&MenuSchedule{},
+		&MenuVersion{},
+		&ModifierGroup{},
+		&Modifier{},
+		&Combo{},
+		&ComboSlot{},
+		&ComboSlotItem{},
+		&PricingRule{},
+		&MenuTemplate{},
+		&MenuOverride{},
 		&DineOrder{},
 		&MenuItemOption{},
 		&RestaurantOrder{},
 		&RestaurantOrderItem{},
+		&OrderItemModifier{},
+		&OrderCombo{},
 		&DinePayment{},
 		&Subscription{},
 		&RestaurantsCount{},
 		&RestaurantBankAccount{},
+		&RestaurantTable{},
+		&OpeningHour{},
+		&RestaurantClosure{},
+		&Translation{},
+		&Image{},
 		&DinePromoCode{},
+		&KitchenStation{},
+		&KitchenTicket{},
+		&KitchenTicketItem{},
+		&TableSession{},
+		&SessionPayment{},
+		&GuestVerification{},
+		&RestaurantOrderRules{},
+		&ItemStock{},
+		&StockMovement{},
+		&Ingredient{},
+		&StockAlert{},
+		&EightySixedItem{},
+		&RecipeLine{},
+		&Supplier{},
+		&PurchaseReceipt{},
+		&PurchaseReceiptLine{},
+		&IngredientMovement{},
 	)
 }
@@ -10,4 +10,6 @@ var AppVar = map[string]string{
 	"REFRESH_TOKEN_SECRET": GetEnv("REFRESH_TOKEN_SECRET"),
 	"REFRESH_TOKEN_AGE":    GetEnv("REFRESH_TOKEN_AGE"),
 	"CLIENT_HOST" :         GetEnv("CLIENT_HOST"),
+	"TABLE_TOKEN_SECRET":   GetEnv("TABLE_TOKEN_SECRET"),
+	"GUEST_TOKEN_SECRET":   GetEnv("GUEST_TOKEN_SECRET"),
 }
@@ -12,6 +12,9 @@ var AuthVar = map[string]string{
 	"REFRESH_TOKEN_AGE":    GetEnv("REFRESH_TOKEN_AGE"),
 	"GOOGLE_CLIENT_ID":     GetEnv("GOOGLE_CLIENT_ID"),
 	"GOOGLE_CLIENT_SECRET": GetEnv("GOOGLE_CLIENT_SECRET"),
+	// "true" to require a verified email or phone before creating restaurants or buying plans
+	"REQUIRE_VERIFIED_EMAIL": GetEnv("REQUIRE_VERIFIED_EMAIL"),
+	"REQUIRE_VERIFIED_PHONE": GetEnv("REQUIRE_VERIFIED_PHONE"),
 }
 
 var (
@@ -0,0 +1,14 @@
+package env
+
+var MailVar = map[string]string{
+	"MAIL_DRIVER":   GetEnv("MAIL_DRIVER"), // "console" (default, not allowed in production) or "smtp"
+	"MAIL_FROM":     GetEnv("MAIL_FROM"),   // e.g. Dine <no-reply@dine.example>
+	"SMTP_HOST":     GetEnv("SMTP_HOST"),
+	"SMTP_PORT":     GetEnv("SMTP_PORT"), // Defaults to 587
+	"SMTP_USERNAME": GetEnv("SMTP_USERNAME"),
+	"SMTP_PASSWORD": GetEnv("SMTP_PASSWORD"),
+}
+
+var SMSVar = map[string]string{
+	"SMS_DRIVER": GetEnv("SMS_DRIVER"), // "console" (default, not allowed in production), "fake", which keeps messages in memory, or "disabled"
+}
@@ -0,0 +1,13 @@
+package env
+
+var StorageVar = map[string]string{
+	"STORAGE_DRIVER":     GetEnv("STORAGE_DRIVER"),     // "local" (default) or "s3"
+	"STORAGE_LOCAL_DIR":  GetEnv("STORAGE_LOCAL_DIR"),  // Directory of the local storage, defaults to "uploads"
+	"STORAGE_PUBLIC_URL": GetEnv("STORAGE_PUBLIC_URL"), // Base URL the stored files are served from
+	"S3_ENDPOINT":        GetEnv("S3_ENDPOINT"),        // e.g. https://s3.ap-south-1.amazonaws.com or http://minio:9000
+	"S3_REGION":          GetEnv("S3_REGION"),
+	"S3_BUCKET":          GetEnv("S3_BUCKET"),
+	"S3_ACCESS_KEY":      GetEnv("S3_ACCESS_KEY"),
+	"S3_SECRET_KEY":      GetEnv("S3_SECRET_KEY"),
+	"S3_PATH_STYLE":      GetEnv("S3_PATH_STYLE"), // "true" for MinIO and other servers without bucket subdomains
+}
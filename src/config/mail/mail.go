@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"dine-server/src/config/env"
+	"log"
+)
+
+// Sender delivers emails
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// ConsoleSender writes emails to the server log instead of sending them.
+// Used in development and until a mail server is configured.
+type ConsoleSender struct{}
+
+func (ConsoleSender) Send(to, subject, body string) error {
+	log.Printf("Email to %s: %s\n%s", to, subject, body)
+	return nil
+}
+
+// Client is the sender used by the application, picked with MAIL_DRIVER
+var Client Sender
+
+func init() {
+	switch env.MailVar["MAIL_DRIVER"] {
+	case "smtp":
+		port := env.MailVar["SMTP_PORT"]
+		if port == "" {
+			port = "587"
+		}
+		Client = SMTPSender{
+			Host:     env.MailVar["SMTP_HOST"],
+			Port:     port,
+			Username: env.MailVar["SMTP_USERNAME"],
+			Password: env.MailVar["SMTP_PASSWORD"],
+			From:     env.MailVar["MAIL_FROM"],
+		}
+	case "", "console":
+		// Emails carry verification links and reset tokens, which must not end up in production logs
+		if env.AppVar["ENVIRONMENT"] == "production" {
+			log.Fatalf("MAIL_DRIVER console writes emails to the log and cannot be used in production, use smtp")
+		}
+		Client = ConsoleSender{}
+	default:
+		log.Fatalf("Unknown MAIL_DRIVER %q, use console or smtp", env.MailVar["MAIL_DRIVER"])
+	}
+}
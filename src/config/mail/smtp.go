@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPSender sends plain text emails through an SMTP server.
+// The connection is upgraded with STARTTLS when the server offers it.
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string // PLAIN authentication is skipped without a username
+	Password string
+	From     string
+}
+
+func (s SMTPSender) Send(to, subject, body string) error {
+	// Headers must not be able to inject other headers
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errors.New("invalid email header")
+	}
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	message := "From: " + s.From + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(body, "\n", "\r\n")
+	return smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, from.Address, []string{to}, []byte(message))
+}
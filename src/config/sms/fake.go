@@ -0,0 +1,57 @@
+package sms
+
+import (
+	"sync"
+	"time"
+)
+
+// Message is a text message kept by the FakeSender
+type Message struct {
+	Phone  string
+	Body   string
+	SentAt time.Time
+}
+
+// FakeSender keeps messages in memory instead of sending them, so they can be read back
+// in development and automated checks. It never fails.
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (f *FakeSender) Send(phone, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = append(f.messages, Message{Phone: phone, Body: message, SentAt: time.Now()})
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first
+func (f *FakeSender) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.messages...)
+}
+
+// Last returns the latest message sent to a phone
+func (f *FakeSender) Last(phone string) (Message, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := len(f.messages) - 1; i >= 0; i-- {
+		if f.messages[i].Phone == phone {
+			return f.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset forgets the messages sent so far
+func (f *FakeSender) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = nil
+}
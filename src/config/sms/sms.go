@@ -0,0 +1,48 @@
+package sms
+
+import (
+	"dine-server/src/config/env"
+	"errors"
+	"log"
+)
+
+// Sender delivers text messages to a phone number. Providers implement it to be used as Client.
+type Sender interface {
+	Send(phone, message string) error
+}
+
+// ConsoleSender writes messages to the server log instead of sending them.
+// Used until an SMS provider is configured.
+type ConsoleSender struct{}
+
+func (ConsoleSender) Send(phone, message string) error {
+	log.Printf("SMS to %s: %s", phone, message)
+	return nil
+}
+
+// DisabledSender refuses every message, for deployments without an SMS provider
+type DisabledSender struct{}
+
+func (DisabledSender) Send(phone, message string) error {
+	return errors.New("no SMS provider is configured")
+}
+
+// Client is the sender used by the application, picked with SMS_DRIVER
+var Client Sender
+
+func init() {
+	switch env.SMSVar["SMS_DRIVER"] {
+	case "fake":
+		Client = &FakeSender{}
+	case "disabled":
+		Client = DisabledSender{}
+	case "", "console":
+		// Messages carry one-time codes, which must not end up in production logs
+		if env.AppVar["ENVIRONMENT"] == "production" {
+			log.Fatalf("SMS_DRIVER console writes messages to the log and cannot be used in production, use disabled until a provider is configured")
+		}
+		Client = ConsoleSender{}
+	default:
+		log.Fatalf("Unknown SMS_DRIVER %q, use console, fake or disabled", env.SMSVar["SMS_DRIVER"])
+	}
+}
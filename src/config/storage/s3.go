@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3 compatible storage, AWS S3 or a MinIO server
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool   // Address the bucket in the path instead of a subdomain, needed by MinIO
+	PublicURL string // Base URL the files are served from, the bucket URL when empty
+}
+
+// S3Storage keeps files in an S3 compatible bucket. The bucket must allow public reads of its objects.
+type S3Storage struct {
+	config S3Config
+	bucket *url.URL
+	client *http.Client
+}
+
+// NewS3Storage creates an S3 storage, the region defaults to us-east-1
+func NewS3Storage(config S3Config) *S3Storage {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{Scheme: "https", Host: "s3." + config.Region + ".amazonaws.com"}
+	}
+	bucket := *endpoint
+	if config.PathStyle {
+		bucket.Path = "/" + config.Bucket
+	} else {
+		bucket.Host = config.Bucket + "." + endpoint.Host
+	}
+	if config.PublicURL == "" {
+		config.PublicURL = bucket.String()
+	}
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	return &S3Storage{config: config, bucket: &bucket, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (s *S3Storage) Put(key string, data []byte, contentType string) error {
+	return s.do(http.MethodPut, key, data, contentType)
+}
+
+func (s *S3Storage) Delete(key string) error {
+	return s.do(http.MethodDelete, key, nil, "")
+}
+
+func (s *S3Storage) URL(key string) string {
+	return s.config.PublicURL + "/" + key
+}
+
+// do sends a signed request for an object of the bucket
+func (s *S3Storage) do(method, key string, data []byte, contentType string) error {
+	object := *s.bucket
+	object.Path = strings.TrimSuffix(object.Path, "/") + "/" + key
+
+	req, err := http.NewRequest(method, object.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, data, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Deleting a missing object is not an error
+	if resp.StatusCode >= 300 && !(method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("storage %s %s failed with %s: %s", method, key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds an AWS signature version 4 to a request.
+// Keys are made by the application from IDs and extensions, so their path needs no further escaping.
+func (s *S3Storage) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
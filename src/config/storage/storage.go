@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"dine-server/src/config/env"
+	"log"
+	"strings"
+)
+
+// Storage keeps uploaded files and tells where they are served from
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	Delete(key string) error
+	URL(key string) string
+}
+
+// Client is the storage used by the application, picked with STORAGE_DRIVER
+var Client Storage
+
+func init() {
+	switch env.StorageVar["STORAGE_DRIVER"] {
+	case "s3":
+		Client = NewS3Storage(S3Config{
+			Endpoint:  env.StorageVar["S3_ENDPOINT"],
+			Region:    env.StorageVar["S3_REGION"],
+			Bucket:    env.StorageVar["S3_BUCKET"],
+			AccessKey: env.StorageVar["S3_ACCESS_KEY"],
+			SecretKey: env.StorageVar["S3_SECRET_KEY"],
+			PathStyle: env.StorageVar["S3_PATH_STYLE"] == "true",
+			PublicURL: env.StorageVar["STORAGE_PUBLIC_URL"],
+		})
+	case "", "local":
+		dir := env.StorageVar["STORAGE_LOCAL_DIR"]
+		if dir == "" {
+			dir = "uploads"
+		}
+		Client = &LocalStorage{Dir: dir, BaseURL: localBaseURL()}
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q, use local or s3", env.StorageVar["STORAGE_DRIVER"])
+	}
+}
+
+// localBaseURL is where the server itself serves the local storage, unless STORAGE_PUBLIC_URL says otherwise
+func localBaseURL() string {
+	if url := env.StorageVar["STORAGE_PUBLIC_URL"]; url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	if env.AppVar["ENVIRONMENT"] == "development" {
+		return "http://" + strings.TrimSuffix(env.AppVar["SERVER_HOST"], "/") + LocalPath
+	}
+	return "https://" + strings.TrimSuffix(env.AppVar["SERVER_HOST"], "/") + LocalPath
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	docs "dine-server/docs"
+	"dine-server/src/api/v1/policy"
 	postgres "dine-server/src/config/database"
 	"dine-server/src/config/env"
+	"dine-server/src/config/storage"
 	"dine-server/src/routes"
 	"log"
 
@@ -48,6 +50,11 @@ func main() {
 	}))
 
 
+	// Uploaded images are served by the server itself when stored locally
+	if local, ok := storage.Client.(*storage.LocalStorage); ok {
+		r.Static(storage.LocalPath, local.Dir)
+	}
+
 	setupRoutes(r)
 
 	port := env.AppVar["PORT"]
@@ -85,4 +92,9 @@ func setupHealthCheckRoute(r *gin.Engine) {
 func setupRoutes(r *gin.Engine) {
 	setupHealthCheckRoute(r)
 	routes.V1Routes(r)
+
+	// Refuse to start when a route has no policy
+	if err := policy.Verify(r.Routes()); err != nil {
+		log.Fatalf("Failed to verify route policies: %v", err)
+	}
 }
@@ -0,0 +1,191 @@
+package models_inventory
+
+import (
+	models_menu "dine-server/src/models/menu"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Ingredient is a raw material kept in stock, counted in its unit
+type Ingredient struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_ingredient_name" json:"restaurant_id"`
+	Name              string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_ingredient_name" json:"name"`
+	Unit              Unit      `gorm:"type:varchar(10);check:unit IN ('g','kg','ml','l','pcs');not null" json:"unit"`
+	Quantity          float64   `gorm:"type:decimal(12,3);not null;default:0" json:"quantity"`      // Can go below zero when orders use more than was counted
+	CostPerUnit       float64   `gorm:"type:decimal(12,4);not null;default:0" json:"cost_per_unit"` // Weighted average of the purchases
+	LowStockThreshold float64   `gorm:"type:decimal(12,3);not null;default:0" json:"low_stock_threshold"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Unit is the unit an ingredient is counted in
+type Unit string
+
+const (
+	UnitGram       Unit = "g"
+	UnitKilogram   Unit = "kg"
+	UnitMillilitre Unit = "ml"
+	UnitLitre      Unit = "l"
+	UnitPiece      Unit = "pcs"
+)
+
+// RecipeLine is the quantity of an ingredient used by one portion of a menu item.
+// Lines without an option are used by every option, lines with one are added for that option only.
+type RecipeLine struct {
+	ID           uuid.UUID                   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID                   `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	MenuItemID   uuid.UUID                   `gorm:"type:uuid;not null;index" json:"menu_item_id"`
+	MenuItem     *models_menu.MenuItem       `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"-"`
+	ItemOptionID *uuid.UUID                  `gorm:"type:uuid;index" json:"item_option_id"`
+	ItemOption   *models_menu.MenuItemOption `gorm:"foreignKey:ItemOptionID;constraint:OnDelete:CASCADE;" json:"-"`
+	IngredientID uuid.UUID                   `gorm:"type:uuid;not null;index" json:"ingredient_id"`
+	Ingredient   *Ingredient                 `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE;" json:"ingredient,omitempty"`
+	Quantity     float64                     `gorm:"type:decimal(12,3);not null;check:quantity > 0" json:"quantity"` // In the unit of the ingredient
+	CreatedAt    time.Time                   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time                   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Supplier delivers ingredients to a restaurant
+type Supplier struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	Phone        string    `gorm:"type:varchar(20)" json:"phone"`
+	Email        string    `gorm:"type:varchar(100)" json:"email"`
+	Notes        string    `gorm:"type:text" json:"notes"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PurchaseReceipt records ingredients received, it adds them to the stock and updates their cost
+type PurchaseReceipt struct {
+	ID           uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID             `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	SupplierID   *uuid.UUID            `gorm:"type:uuid;index" json:"supplier_id"`
+	Supplier     *Supplier             `gorm:"foreignKey:SupplierID;constraint:OnDelete:SET NULL;" json:"supplier,omitempty"`
+	Reference    string                `gorm:"type:varchar(100)" json:"reference"` // Invoice or delivery note number
+	ReceivedAt   time.Time             `gorm:"not null;index" json:"received_at"`
+	Total        float64               `gorm:"type:decimal(10,2);not null" json:"total"`
+	Lines        []PurchaseReceiptLine `gorm:"foreignKey:ReceiptID;constraint:OnDelete:CASCADE;" json:"lines"`
+	CreatedBy    *uuid.UUID            `gorm:"type:uuid" json:"created_by"`
+	CreatedAt    time.Time             `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type PurchaseReceiptLine struct {
+	ID           uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ReceiptID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"receipt_id"`
+	IngredientID uuid.UUID   `gorm:"type:uuid;not null;index" json:"ingredient_id"`
+	Ingredient   *Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE;" json:"ingredient,omitempty"`
+	Quantity     float64     `gorm:"type:decimal(12,3);not null" json:"quantity"`
+	UnitCost     float64     `gorm:"type:decimal(12,4);not null" json:"unit_cost"`
+	Total        float64     `gorm:"type:decimal(10,2);not null" json:"total"`
+}
+
+// IngredientMovement is one change of an ingredient stock, reports are computed from them
+type IngredientMovement struct {
+	ID           uuid.UUID                `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID                `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	IngredientID uuid.UUID                `gorm:"type:uuid;not null;index" json:"ingredient_id"`
+	Ingredient   *Ingredient              `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE;" json:"-"`
+	Reason       IngredientMovementReason `gorm:"type:varchar(20);check:reason IN ('purchase','consumption','wastage','count');not null" json:"reason"`
+	Change       float64                  `gorm:"type:decimal(12,3);not null" json:"change"`
+	Quantity     float64                  `gorm:"type:decimal(12,3);not null" json:"quantity"`  // Quantity after the change
+	UnitCost     float64                  `gorm:"type:decimal(12,4);not null" json:"unit_cost"` // Cost per unit at the time of the change
+	OrderID      *uuid.UUID               `gorm:"type:uuid;index" json:"order_id"`
+	ReceiptID    *uuid.UUID               `gorm:"type:uuid;index" json:"receipt_id"`
+	Note         string                   `gorm:"type:varchar(255)" json:"note"`
+	CreatedBy    *uuid.UUID               `gorm:"type:uuid" json:"created_by"`
+	CreatedAt    time.Time                `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// IngredientMovementReason represents why an ingredient stock changed
+type IngredientMovementReason string
+
+const (
+	IngredientPurchase    IngredientMovementReason = "purchase"    // Received from a supplier
+	IngredientConsumption IngredientMovementReason = "consumption" // Used by a completed order, following the recipes
+	IngredientWastage     IngredientMovementReason = "wastage"     // Spoiled, dropped or returned
+	IngredientCount       IngredientMovementReason = "count"       // Difference found by a physical count
+)
+
+// ConsumptionReportLine compares what the recipes say was used with what left the stock
+type ConsumptionReportLine struct {
+	IngredientID uuid.UUID `json:"ingredient_id"`
+	Name         string    `json:"name"`
+	Unit         Unit      `json:"unit"`
+	Purchased    float64   `json:"purchased"`
+	Theoretical  float64   `json:"theoretical"` // Used by completed orders following the recipes
+	Wastage      float64   `json:"wastage"`
+	CountChange  float64   `json:"count_change"` // Found missing (negative) or extra (positive) by counts
+	Actual       float64   `json:"actual"`       // Theoretical + wastage - count change
+	Variance     float64   `json:"variance"`     // Actual - theoretical
+	VarianceCost float64   `json:"variance_cost"`
+}
+
+// MarginReportLine is the revenue and food cost of a menu item over a period
+type MarginReportLine struct {
+	MenuItemID      uuid.UUID `json:"menu_item_id"`
+	Name            string    `json:"name"`
+	Quantity        int       `json:"quantity"`
+	Revenue         float64   `json:"revenue"`
+	FoodCost        float64   `json:"food_cost"`
+	Margin          float64   `json:"margin"`
+	MarginPercent   float64   `json:"margin_percent"`
+	FoodCostPercent float64   `json:"food_cost_percent"`
+}
+
+type AddIngredientData struct {
+	Name              string  `json:"name" binding:"required,max=100"`
+	Unit              Unit    `json:"unit" binding:"required,oneof=g kg ml l pcs"`
+	Quantity          float64 `json:"quantity" binding:"min=0"`
+	CostPerUnit       float64 `json:"cost_per_unit" binding:"min=0"`
+	LowStockThreshold float64 `json:"low_stock_threshold" binding:"min=0"`
+}
+
+type UpdateIngredientData struct {
+	Name              *string  `json:"name" binding:"omitempty,max=100"`
+	CostPerUnit       *float64 `json:"cost_per_unit" binding:"omitempty,min=0"`
+	LowStockThreshold *float64 `json:"low_stock_threshold" binding:"omitempty,min=0"`
+}
+
+type CountIngredientData struct {
+	Quantity float64 `json:"quantity" binding:"min=0"` // Quantity found on the shelf
+	Note     string  `json:"note" binding:"max=255"`
+}
+
+type AddWastageData struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+	Reason   string  `json:"reason" binding:"required,max=255"`
+}
+
+type SetRecipeData struct {
+	Lines []RecipeLineData `json:"lines" binding:"dive"` // Replaces the whole recipe of the item
+}
+
+type RecipeLineData struct {
+	IngredientID uuid.UUID  `json:"ingredient_id" binding:"required"`
+	ItemOptionID *uuid.UUID `json:"item_option_id"`
+	Quantity     float64    `json:"quantity" binding:"required,gt=0"`
+}
+
+type AddSupplierData struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Phone string `json:"phone" binding:"max=20"`
+	Email string `json:"email" binding:"omitempty,email,max=100"`
+	Notes string `json:"notes"`
+}
+
+type AddPurchaseReceiptData struct {
+	SupplierID *uuid.UUID               `json:"supplier_id"`
+	Reference  string                   `json:"reference" binding:"max=100"`
+	ReceivedAt *time.Time               `json:"received_at"` // Now when empty
+	Lines      []AddPurchaseReceiptLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type AddPurchaseReceiptLine struct {
+	IngredientID uuid.UUID `json:"ingredient_id" binding:"required"`
+	Quantity     float64   `json:"quantity" binding:"required,gt=0"`
+	UnitCost     float64   `json:"unit_cost" binding:"min=0"`
+}
@@ -0,0 +1,105 @@
+package models_inventory
+
+import (
+	models_menu "dine-server/src/models/menu"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ItemStock is the number of portions left of a menu item, or of one of its options.
+// Items without stock are not tracked. An item is tracked either as a whole or per option, not both.
+type ItemStock struct {
+	ID                uuid.UUID                   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID      uuid.UUID                   `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	MenuItemID        uuid.UUID                   `gorm:"type:uuid;not null;index" json:"menu_item_id"`
+	MenuItem          *models_menu.MenuItem       `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"menu_item,omitempty"`
+	ItemOptionID      *uuid.UUID                  `gorm:"type:uuid;index" json:"item_option_id"` // nil when the stock is shared by every option
+	ItemOption        *models_menu.MenuItemOption `gorm:"foreignKey:ItemOptionID;constraint:OnDelete:CASCADE;" json:"item_option,omitempty"`
+	Quantity          int                         `gorm:"type:int;not null;default:0;check:quantity >= 0" json:"quantity"`
+	ParLevel          int                         `gorm:"type:int;not null;default:0;check:par_level >= 0" json:"par_level"`                     // Quantity at the start of each day, 0 means no daily reset
+	LowStockThreshold int                         `gorm:"type:int;not null;default:0;check:low_stock_threshold >= 0" json:"low_stock_threshold"` // An alert is raised once the quantity drops to it
+	ResetDate         string                      `gorm:"type:varchar(10)" json:"reset_date"`                                                    // "YYYY-MM-DD" of the last daily reset, in the restaurant's timezone
+	ResetAt           *time.Time                  `json:"reset_at"`
+	CreatedAt         time.Time                   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time                   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// StockMovement is one change of an item stock, orders are restocked from it when cancelled
+type StockMovement struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	StockID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"stock_id"`
+	Stock     *ItemStock     `gorm:"foreignKey:StockID;constraint:OnDelete:CASCADE;" json:"-"`
+	OrderID   *uuid.UUID     `gorm:"type:uuid;index" json:"order_id"`
+	Reason    MovementReason `gorm:"type:varchar(20);check:reason IN ('order','cancel','adjust','reset');not null" json:"reason"`
+	Change    int            `gorm:"type:int;not null" json:"change"`
+	Quantity  int            `gorm:"type:int;not null" json:"quantity"` // Quantity after the change
+	Note      string         `gorm:"type:varchar(255)" json:"note"`
+	CreatedBy *uuid.UUID     `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// MovementReason represents why a stock changed
+type MovementReason string
+
+const (
+	MovementOrder  MovementReason = "order"  // Taken by an order
+	MovementCancel MovementReason = "cancel" // Given back by a cancelled order
+	MovementAdjust MovementReason = "adjust" // Counted or restocked by staff
+	MovementReset  MovementReason = "reset"  // Daily reset to the par level
+)
+
+// StockAlert is raised when an item stock or an ingredient drops to its low stock threshold
+type StockAlert struct {
+	ID             uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	StockID        *uuid.UUID  `gorm:"type:uuid;index" json:"stock_id"`
+	Stock          *ItemStock  `gorm:"foreignKey:StockID;constraint:OnDelete:CASCADE;" json:"-"`
+	IngredientID   *uuid.UUID  `gorm:"type:uuid;index" json:"ingredient_id"`
+	Ingredient     *Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE;" json:"-"`
+	Name           string      `gorm:"type:varchar(255);not null" json:"name"`
+	Quantity       float64     `gorm:"type:decimal(12,3);not null" json:"quantity"` // Quantity when the alert was raised
+	Threshold      float64     `gorm:"type:decimal(12,3);not null" json:"threshold"`
+	Status         AlertStatus `gorm:"type:varchar(20);check:status IN ('open','acknowledged');default:'open';not null" json:"status"`
+	AcknowledgedAt *time.Time  `json:"acknowledged_at"`
+	AcknowledgedBy *uuid.UUID  `gorm:"type:uuid" json:"acknowledged_by"`
+	CreatedAt      time.Time   `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// AlertStatus represents the possible states of a stock alert
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "open"
+	AlertAcknowledged AlertStatus = "acknowledged"
+)
+
+// EightySixedItem is a menu item the kitchen ran out of, it cannot be ordered for the rest of the day
+type EightySixedItem struct {
+	ID           uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID             `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	MenuItemID   uuid.UUID             `gorm:"type:uuid;not null;uniqueIndex:idx_eighty_sixed_item_date" json:"menu_item_id"`
+	MenuItem     *models_menu.MenuItem `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"menu_item,omitempty"`
+	Date         string                `gorm:"type:varchar(10);not null;uniqueIndex:idx_eighty_sixed_item_date" json:"date"` // "YYYY-MM-DD" in the restaurant's timezone
+	Reason       string                `gorm:"type:varchar(255)" json:"reason"`
+	CreatedBy    *uuid.UUID            `gorm:"type:uuid" json:"created_by"`
+	CreatedAt    time.Time             `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type SetItemStockData struct {
+	MenuItemID        uuid.UUID  `json:"menu_item_id" binding:"required"`
+	ItemOptionID      *uuid.UUID `json:"item_option_id"` // Track one option instead of the whole item
+	Quantity          int        `json:"quantity" binding:"min=0"`
+	ParLevel          int        `json:"par_level" binding:"min=0"`
+	LowStockThreshold int        `json:"low_stock_threshold" binding:"min=0"`
+}
+
+type AdjustItemStockData struct {
+	Change int    `json:"change" binding:"required"` // Positive for deliveries, negative for losses
+	Note   string `json:"note" binding:"max=255"`
+}
+
+type EightySixItemData struct {
+	MenuItemID uuid.UUID `json:"menu_item_id" binding:"required"`
+	Reason     string    `json:"reason" binding:"max=255"`
+}
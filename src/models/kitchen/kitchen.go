@@ -0,0 +1,90 @@
+package models_kitchen
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Station is a preparation area of a restaurant kitchen (tandoor, bar, desserts...)
+type Station struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	IsActive     bool      `gorm:"type:boolean" json:"is_active"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// KitchenTicket (KOT) holds the part of an order a single station has to prepare
+type KitchenTicket struct {
+	ID           uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID           `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	OrderID      uuid.UUID           `gorm:"type:uuid;not null;index" json:"order_id"`
+	StationID    *uuid.UUID          `gorm:"type:uuid;index" json:"station_id"` // nil when the items have no station
+	StationName  string              `gorm:"type:varchar(100)" json:"station_name"`
+	Status       TicketStatus        `gorm:"type:varchar(20);check:status IN ('queued','cooking','done');default:'queued';not null" json:"status"`
+	Items        []KitchenTicketItem `gorm:"foreignKey:TicketID;constraint:OnDelete:CASCADE;" json:"items"`
+	StartedAt    *time.Time          `json:"started_at"`
+	CompletedAt  *time.Time          `json:"completed_at"`
+	CreatedAt    time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// KitchenTicketItem is a single order line printed on a ticket
+type KitchenTicketItem struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TicketID       uuid.UUID `gorm:"type:uuid;not null;index" json:"ticket_id"`
+	OrderItemID    uuid.UUID `gorm:"type:uuid;not null" json:"order_item_id"`
+	MenuItemID     uuid.UUID `gorm:"type:uuid;not null" json:"menu_item_id"`
+	Name           string    `gorm:"type:varchar(255)" json:"name"`
+	ItemOptionName string    `gorm:"type:varchar(255)" json:"item_option_name"`
+	Modifiers      []string  `gorm:"type:jsonb;serializer:json" json:"modifiers"`
+	Quantity       int       `gorm:"type:int;not null" json:"quantity"`
+}
+
+// TicketStatus represents the possible states of a kitchen ticket
+type TicketStatus string
+
+const (
+	TicketStatusQueued  TicketStatus = "queued"
+	TicketStatusCooking TicketStatus = "cooking"
+	TicketStatusDone    TicketStatus = "done"
+)
+
+// TicketTransitions lists the statuses a ticket can move to from each status.
+// Tickets only move forward, a done ticket is never reopened.
+var TicketTransitions = map[TicketStatus][]TicketStatus{
+	TicketStatusQueued:  {TicketStatusCooking, TicketStatusDone},
+	TicketStatusCooking: {TicketStatusDone},
+}
+
+// CanMoveTo reports whether a ticket in this status can move to the next one
+func (status TicketStatus) CanMoveTo(next TicketStatus) bool {
+	for _, allowed := range TicketTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type AddStationData struct {
+	Name     string `json:"name" binding:"required"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type UpdateStationData struct {
+	Name     string `json:"name"`
+	IsActive *bool  `json:"is_active"`
+}
+
+// AssignStationData maps menu categories and items to a station
+type AssignStationData struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+	ItemIDs     []uuid.UUID `json:"item_ids"`
+}
+
+type UpdateTicketStatusData struct {
+	Status TicketStatus `json:"status" binding:"required,oneof=cooking done"`
+}
@@ -0,0 +1,77 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Combo is a bundle of menu items sold at one price, e.g. "Burger + fries + drink".
+// Each slot lets the guest pick one of several items, some with an upcharge.
+type Combo struct {
+	ID          uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	MenuID      uuid.UUID   `gorm:"type:uuid;not null;index" json:"menu_id"`
+	CategoryID  *uuid.UUID  `gorm:"type:uuid;index" json:"category_id"` // Optional category the combo is listed under
+	Name        string      `gorm:"type:varchar(100);not null" json:"name"`
+	Description *string     `gorm:"type:text" json:"description"`
+	ImageURL    *string     `gorm:"type:varchar(255)" json:"image_url"`
+	Price       float64     `gorm:"type:decimal(10,2);not null" json:"price"`
+	IsAvailable bool        `gorm:"type:boolean" json:"is_available"`
+	Slots       []ComboSlot `gorm:"foreignKey:ComboID;constraint:OnDelete:CASCADE;" json:"slots"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ComboSlot is one choice of a combo, e.g. "Drink"
+type ComboSlot struct {
+	ID        uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ComboID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"combo_id"`
+	Name      string          `gorm:"type:varchar(100);not null" json:"name"`
+	Position  int             `gorm:"type:int;not null;default:0" json:"position"`
+	Items     []ComboSlotItem `gorm:"foreignKey:SlotID;constraint:OnDelete:CASCADE;" json:"items"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ComboSlotItem is a menu item that can fill a combo slot
+type ComboSlotItem struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SlotID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"slot_id"`
+	MenuItemID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"menu_item_id"`
+	MenuItem     *MenuItem  `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"menu_item,omitempty"`
+	ItemOptionID *uuid.UUID `gorm:"type:uuid" json:"item_option_id"` // Option served in the combo, the cheapest one when empty
+	Upcharge     float64    `gorm:"type:decimal(10,2);not null;default:0" json:"upcharge"`
+	IsDefault    bool       `gorm:"type:boolean;default:false" json:"is_default"`
+}
+
+type AddComboData struct {
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	ImageURL    string             `json:"image_url"`
+	CategoryID  *uuid.UUID         `json:"category_id"`
+	Price       float64            `json:"price" binding:"required,gt=0"`
+	IsAvailable *bool              `json:"is_available"`
+	Slots       []AddComboSlotData `json:"slots" binding:"required,min=1,dive"`
+}
+
+type UpdateComboData struct {
+	Name        string              `json:"name"`
+	Description *string             `json:"description"`
+	ImageURL    *string             `json:"image_url"`
+	CategoryID  *uuid.UUID          `json:"category_id"`
+	Price       *float64            `json:"price" binding:"omitempty,gt=0"`
+	IsAvailable *bool               `json:"is_available"`
+	Slots       *[]AddComboSlotData `json:"slots" binding:"omitempty,min=1,dive"` // Replaces every slot when set
+}
+
+type AddComboSlotData struct {
+	Name  string                 `json:"name" binding:"required"`
+	Items []AddComboSlotItemData `json:"items" binding:"required,min=1,dive"`
+}
+
+type AddComboSlotItemData struct {
+	MenuItemID   uuid.UUID  `json:"menu_item_id" binding:"required"`
+	ItemOptionID *uuid.UUID `json:"item_option_id"`
+	Upcharge     float64    `json:"upcharge" binding:"min=0"`
+	IsDefault    bool       `json:"is_default"`
+}
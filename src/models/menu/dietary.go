@@ -0,0 +1,49 @@
+package models_menu
+
+// Diets an item can be suitable for, on top of IsVegetarian
+const (
+	DietVegetarian = "vegetarian" // Only used to filter, follows IsVegetarian
+	DietVegan      = "vegan"
+	DietJain       = "jain"
+	DietGlutenFree = "gluten_free"
+	DietHalal      = "halal"
+)
+
+// Allergens an item can contain
+const (
+	AllergenGluten    = "gluten"
+	AllergenDairy     = "dairy"
+	AllergenEgg       = "egg"
+	AllergenNuts      = "nuts"
+	AllergenPeanuts   = "peanuts"
+	AllergenSoy       = "soy"
+	AllergenFish      = "fish"
+	AllergenShellfish = "shellfish"
+	AllergenSesame    = "sesame"
+	AllergenMustard   = "mustard"
+	AllergenCelery    = "celery"
+	AllergenSulphites = "sulphites"
+)
+
+// MenuDiets are the diets stored on items
+var MenuDiets = []string{DietVegan, DietJain, DietGlutenFree, DietHalal}
+
+// MenuAllergens are the allergens stored on items
+var MenuAllergens = []string{
+	AllergenGluten, AllergenDairy, AllergenEgg, AllergenNuts, AllergenPeanuts, AllergenSoy,
+	AllergenFish, AllergenShellfish, AllergenSesame, AllergenMustard, AllergenCelery, AllergenSulphites,
+}
+
+// MaxSpiceLevel is the hottest spice level, 0 is not spicy
+const MaxSpiceLevel = 3
+
+// MenuItemNutrition is the nutrition of one serving of an item
+type MenuItemNutrition struct {
+	Calories      *int     `json:"calories" binding:"omitempty,min=0"`
+	Protein       *float64 `json:"protein_g" binding:"omitempty,min=0"`
+	Carbohydrates *float64 `json:"carbohydrates_g" binding:"omitempty,min=0"`
+	Fat           *float64 `json:"fat_g" binding:"omitempty,min=0"`
+	Sugar         *float64 `json:"sugar_g" binding:"omitempty,min=0"`
+	Sodium        *float64 `json:"sodium_mg" binding:"omitempty,min=0"`
+	ServingSize   string   `json:"serving_size" binding:"max=50"` // e.g. "250 g" or "1 plate"
+}
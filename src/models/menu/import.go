@@ -0,0 +1,61 @@
+package models_menu
+
+// MenuImportRow is one line of an imported menu file: an option of an item in a category.
+// An item without options is a row with neither option nor price.
+type MenuImportRow struct {
+	Row                 int     `json:"row"` // Line of the file, or position of the option in a JSON file
+	Category            string  `json:"category"`
+	CategoryDescription string  `json:"category_description"`
+	Item                string  `json:"item"`
+	Description         string  `json:"description"`
+	ImageURL            string  `json:"image_url"`
+	IsVegetarian        *bool   `json:"is_vegetarian"`
+	IsAvailable         *bool   `json:"is_available"`
+	Option              string  `json:"option"`
+	Price               float64 `json:"price"`
+
+	Errors []MenuImportError `json:"-"` // Values of the file that could not be read
+}
+
+// MenuImportError explains why a row of an imported file was rejected
+type MenuImportError struct {
+	Row   int    `json:"row"`
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// MenuImportSummary counts what an import creates and updates
+type MenuImportSummary struct {
+	CategoriesCreated int `json:"categories_created"`
+	CategoriesUpdated int `json:"categories_updated"`
+	ItemsCreated      int `json:"items_created"`
+	ItemsUpdated      int `json:"items_updated"`
+	OptionsCreated    int `json:"options_created"`
+	OptionsUpdated    int `json:"options_updated"`
+}
+
+// MenuFile is the JSON layout of an imported or exported menu
+type MenuFile struct {
+	Menu       string             `json:"menu"`
+	Categories []MenuFileCategory `json:"categories"`
+}
+
+type MenuFileCategory struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Items       []MenuFileItem `json:"items"`
+}
+
+type MenuFileItem struct {
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	ImageURL     string           `json:"image_url"`
+	IsVegetarian *bool            `json:"is_vegetarian"`
+	IsAvailable  *bool            `json:"is_available"`
+	Options      []MenuFileOption `json:"options"`
+}
+
+type MenuFileOption struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
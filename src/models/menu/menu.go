@@ -10,39 +10,56 @@ import (
 type Menu struct {
 	ID           uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	RestaurantID uuid.UUID      `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	BrandID      *uuid.UUID     `gorm:"type:uuid;index" json:"brand_id"` // Set while the menu is shared with every outlet of the brand
 	Name         string         `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
 	Categories   []MenuCategory `gorm:"foreignKey:MenuID;constraint:OnDelete:CASCADE;" json:"categories"`
 	MenuItems    []MenuItem     `gorm:"foreignKey:MenuID;constraint:OnDelete:CASCADE;" json:"items"`
+	Schedules    []MenuSchedule `gorm:"foreignKey:MenuID;constraint:OnDelete:CASCADE;" json:"schedules"`
+	Combos       []Combo        `gorm:"foreignKey:MenuID;constraint:OnDelete:CASCADE;" json:"combos"`
+	IsActive     bool           `gorm:"-" json:"is_active"` // Whether the menu is served at the requested time
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type MenuCategory struct {
-	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	MenuID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"menu_id"`
-	Menu        Menu       `gorm:"foreignKey:MenuID" json:"-"`
-	Name        string     `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
-	ImageURL    *string    `gorm:"type:varchar(255)" json:"image_url"`
-	Description *string    `gorm:"type:text" json:"description"`
-	MenuItems   []MenuItem `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE;" json:"menu_items"`
-	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	MenuID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"menu_id"`
+	Menu         Menu           `gorm:"foreignKey:MenuID" json:"-"`
+	Name         string         `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
+	ImageURL     *string        `gorm:"type:varchar(255)" json:"image_url"`
+	ThumbnailURL *string        `gorm:"type:varchar(255)" json:"thumbnail_url"` // Set when the image is uploaded
+	Description  *string        `gorm:"type:text" json:"description"`
+	StationID    *uuid.UUID     `gorm:"type:uuid;index" json:"station_id"`
+	Position     int            `gorm:"type:int;not null;default:0" json:"position"` // Display order within the menu, lowest first
+	MenuItems    []MenuItem     `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE;" json:"menu_items"`
+	Schedules    []MenuSchedule `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE;" json:"schedules"`
+	IsActive     bool           `gorm:"-" json:"is_active"` // Whether the category is served at the requested time
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type MenuItem struct {
-	ID           uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	MenuID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"menu_id"`
-	Menu         Menu             `gorm:"foreignKey:MenuID" json:"-"`
-	CategoryID   uuid.UUID        `gorm:"type:uuid;not null;index" json:"category_id"`
-	Category     MenuCategory     `gorm:"foreignKey:CategoryID" json:"-"`
-	Name         string           `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
-	Description  *string          `gorm:"type:text" json:"description"`
-	ImageURL     *string          `gorm:"type:varchar(255)" json:"image_url"`
-	IsVegetarian bool             `gorm:"type:boolean;default:false" json:"is_vegetarian"`
-	IsAvailable  bool             `gorm:"type:boolean;default:true" json:"is_available"`
-	ItemOptions  []MenuItemOption `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"options"`
-	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	ID             uuid.UUID          `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	MenuID         uuid.UUID          `gorm:"type:uuid;not null;index" json:"menu_id"`
+	Menu           Menu               `gorm:"foreignKey:MenuID" json:"-"`
+	CategoryID     uuid.UUID          `gorm:"type:uuid;not null;index" json:"category_id"`
+	Category       MenuCategory       `gorm:"foreignKey:CategoryID" json:"-"`
+	Name           string             `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
+	Description    *string            `gorm:"type:text" json:"description"`
+	ImageURL       *string            `gorm:"type:varchar(255)" json:"image_url"`
+	ThumbnailURL   *string            `gorm:"type:varchar(255)" json:"thumbnail_url"` // Set when the image is uploaded
+	IsVegetarian   bool               `gorm:"type:boolean;default:false" json:"is_vegetarian"`
+	IsAvailable    bool               `gorm:"type:boolean" json:"is_available"`
+	Diets          []string           `gorm:"type:jsonb;serializer:json" json:"diets"`     // See MenuDiets
+	Allergens      []string           `gorm:"type:jsonb;serializer:json" json:"allergens"` // See MenuAllergens
+	SpiceLevel     int                `gorm:"type:int;default:0;check:spice_level BETWEEN 0 AND 3" json:"spice_level"`
+	Nutrition      *MenuItemNutrition `gorm:"type:jsonb;serializer:json" json:"nutrition"`
+	StationID      *uuid.UUID         `gorm:"type:uuid;index" json:"station_id"`
+	Position       int                `gorm:"type:int;not null;default:0" json:"position"` // Display order within the category, lowest first
+	ItemOptions    []MenuItemOption   `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"options"`
+	ModifierGroups []ModifierGroup    `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"modifier_groups"`
+	CreatedAt      time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type MenuItemOption struct {
@@ -51,17 +68,23 @@ type MenuItemOption struct {
 	MenuItem   MenuItem  `gorm:"foreignKey:MenuItemID" json:"-"`
 	Name       string    `gorm:"type:varchar(50);not null" json:"name" validate:"required,min=1,max=50"`
 	Price      float64   `gorm:"type:decimal(10,2);not null" json:"price" validate:"required,gt=0"`
+	Position   int       `gorm:"type:int;not null;default:0" json:"position"` // Display order within the item, lowest first
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Set on menu responses while a pricing rule changes the price
+	BasePrice     *float64   `gorm:"-" json:"base_price,omitempty"`
+	PricingRuleID *uuid.UUID `gorm:"-" json:"pricing_rule_id,omitempty"`
 }
 
 type AddMenuData struct {
 	Name string `json:"name" binding:"required"`
 }
 type AddMenuCategoryData struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	ImageURL    string `json:"image_url"`
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description"`
+	ImageURL    string     `json:"image_url"`
+	StationID   *uuid.UUID `json:"station_id"`
 }
 
 // MenuItemOption Table
@@ -71,7 +94,12 @@ type AddMenuItemData struct {
 	Description  string                  `json:"description"`
 	ImageURL     string                  `json:"image_url"`
 	IsVegetarian bool                    `json:"is_vegetarian"`
-	IsAvailable  bool                    `json:"is_available"`
+	IsAvailable  *bool                   `json:"is_available"`
+	Diets        []string                `json:"diets"`
+	Allergens    []string                `json:"allergens"`
+	SpiceLevel   int                     `json:"spice_level" binding:"min=0,max=3"`
+	Nutrition    *MenuItemNutrition      `json:"nutrition"`
+	StationID    *uuid.UUID              `json:"station_id"`
 	ItemOptions  []AddMenuItemOptionData `json:"options"`
 }
 
@@ -79,3 +107,7 @@ type AddMenuItemOptionData struct {
 	Name  string  `json:"name" binding:"required"`
 	Price float64 `json:"price" binding:"required"`
 }
+
+type ReorderMenuData struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"` // Every category, item or option of the parent, in display order
+}
@@ -0,0 +1,56 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ModifierGroup is a set of choices offered with a menu item, e.g. "Toppings" or "Spice level".
+// Guests pick between MinSelect and MaxSelect modifiers of the group; MaxSelect 0 means no limit.
+type ModifierGroup struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	MenuItemID uuid.UUID  `gorm:"type:uuid;not null;index" json:"menu_item_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	MinSelect  int        `gorm:"type:int;not null;default:0" json:"min_select"`
+	MaxSelect  int        `gorm:"type:int;not null;default:1" json:"max_select"`
+	Modifiers  []Modifier `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE;" json:"modifiers"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Modifier is one choice of a modifier group, with the price it adds to the item
+type Modifier struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	GroupID     uuid.UUID `gorm:"type:uuid;not null;index" json:"group_id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Price       float64   `gorm:"type:decimal(10,2);not null;default:0" json:"price"`
+	IsAvailable bool      `gorm:"type:boolean" json:"is_available"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AddModifierGroupData struct {
+	Name      string            `json:"name" binding:"required"`
+	MinSelect int               `json:"min_select" binding:"min=0"`
+	MaxSelect *int              `json:"max_select" binding:"omitempty,min=0"` // Defaults to 1
+	Modifiers []AddModifierData `json:"modifiers" binding:"dive"`
+}
+
+type UpdateModifierGroupData struct {
+	Name      string `json:"name"`
+	MinSelect *int   `json:"min_select" binding:"omitempty,min=0"`
+	MaxSelect *int   `json:"max_select" binding:"omitempty,min=0"`
+}
+
+type AddModifierData struct {
+	Name        string  `json:"name" binding:"required"`
+	Price       float64 `json:"price" binding:"min=0"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+type UpdateModifierData struct {
+	Name        string   `json:"name"`
+	Price       *float64 `json:"price" binding:"omitempty,min=0"`
+	IsAvailable *bool    `json:"is_available"`
+}
@@ -0,0 +1,29 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// MenuOverride changes a menu shared by a brand for one outlet: the availability of an item,
+// or the price of one of its options
+type MenuOverride struct {
+	ID               uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID     uuid.UUID       `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	MenuItemID       uuid.UUID       `gorm:"type:uuid;not null;index" json:"menu_item_id"`
+	MenuItem         MenuItem        `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"-"`
+	MenuItemOptionID *uuid.UUID      `gorm:"type:uuid" json:"menu_item_option_id"`
+	MenuItemOption   *MenuItemOption `gorm:"foreignKey:MenuItemOptionID;constraint:OnDelete:CASCADE;" json:"-"`
+	Price            *float64        `gorm:"type:decimal(10,2)" json:"price"`  // Set on option overrides
+	IsAvailable      *bool           `gorm:"type:boolean" json:"is_available"` // Set on item overrides
+	CreatedAt        time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type SetMenuOverrideData struct {
+	MenuItemID       uuid.UUID  `json:"menu_item_id" binding:"required"`
+	MenuItemOptionID *uuid.UUID `json:"menu_item_option_id"`            // Override the price of this option
+	Price            *float64   `json:"price" binding:"omitempty,gt=0"` // Requires menu_item_option_id
+	IsAvailable      *bool      `json:"is_available"`                   // Without menu_item_option_id
+}
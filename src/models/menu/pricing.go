@@ -0,0 +1,66 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// PricingRule changes the price of item options while its window is open, e.g. 20% off beverages 4-7pm on weekdays.
+// A rule on neither a category nor an item applies to the whole restaurant. Times are in the restaurant's timezone.
+// Combos keep their bundle price.
+type PricingRule struct {
+	ID           uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID       `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Name         string          `gorm:"type:varchar(100);not null" json:"name"`
+	CategoryID   *uuid.UUID      `gorm:"type:uuid;index" json:"category_id"`
+	Category     *MenuCategory   `gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE;" json:"-"`
+	MenuItemID   *uuid.UUID      `gorm:"type:uuid;index" json:"menu_item_id"`
+	MenuItem     *MenuItem       `gorm:"foreignKey:MenuItemID;constraint:OnDelete:CASCADE;" json:"-"`
+	Adjustment   PriceAdjustment `gorm:"type:varchar(20);check:adjustment IN ('percentage','fixed');not null" json:"adjustment"`
+	Value        float64         `gorm:"type:decimal(10,2);not null" json:"value"`     // Negative for a discount, e.g. -20 is 20% or 20 off
+	Days         []int           `gorm:"type:jsonb;serializer:json" json:"days"`       // 0 = Sunday, empty means every day
+	StartTime    string          `gorm:"type:varchar(5)" json:"start_time"`            // "HH:MM", empty means all day
+	EndTime      string          `gorm:"type:varchar(5)" json:"end_time"`              // "HH:MM", before start_time runs past midnight
+	StartDate    string          `gorm:"type:varchar(10)" json:"start_date,omitempty"` // "YYYY-MM-DD", inclusive
+	EndDate      string          `gorm:"type:varchar(10)" json:"end_date,omitempty"`   // "YYYY-MM-DD", inclusive
+	IsActive     bool            `gorm:"type:boolean" json:"is_active"`                // Paused rules are kept but never applied
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PriceAdjustment is how a pricing rule changes a price
+type PriceAdjustment string
+
+const (
+	PriceAdjustmentPercentage PriceAdjustment = "percentage" // Value is a percentage of the price
+	PriceAdjustmentFixed      PriceAdjustment = "fixed"      // Value is an amount added to the price
+)
+
+type AddPricingRuleData struct {
+	Name       string          `json:"name" binding:"required,max=100"`
+	CategoryID *uuid.UUID      `json:"category_id"`
+	MenuItemID *uuid.UUID      `json:"menu_item_id"`
+	Adjustment PriceAdjustment `json:"adjustment" binding:"required,oneof=percentage fixed"`
+	Value      float64         `json:"value" binding:"required"`
+	Days       []int           `json:"days" binding:"dive,min=0,max=6"`
+	StartTime  string          `json:"start_time" binding:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime    string          `json:"end_time" binding:"required_with=StartTime,omitempty,datetime=15:04"`
+	StartDate  string          `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string          `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	IsActive   *bool           `json:"is_active"`
+}
+
+type UpdatePricingRuleData struct {
+	Name       string          `json:"name" binding:"max=100"`
+	CategoryID *uuid.UUID      `json:"category_id"`  // Moves the rule to a category, the nil UUID clears it
+	MenuItemID *uuid.UUID      `json:"menu_item_id"` // Moves the rule to an item, the nil UUID clears it
+	Adjustment PriceAdjustment `json:"adjustment" binding:"omitempty,oneof=percentage fixed"`
+	Value      *float64        `json:"value"`
+	Days       *[]int          `json:"days" binding:"omitempty,dive,min=0,max=6"`
+	StartTime  *string         `json:"start_time"` // An empty value clears it
+	EndTime    *string         `json:"end_time"`
+	StartDate  *string         `json:"start_date"`
+	EndDate    *string         `json:"end_date"`
+	IsActive   *bool           `json:"is_active"`
+}
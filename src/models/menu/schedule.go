@@ -0,0 +1,34 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// MenuSchedule is a window in which a menu or a category is served, e.g. breakfast on weekdays.
+// Menus and categories without schedules are always served. Times are in the restaurant's timezone.
+type MenuSchedule struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	MenuID     *uuid.UUID `gorm:"type:uuid;index" json:"menu_id,omitempty"`
+	CategoryID *uuid.UUID `gorm:"type:uuid;index" json:"category_id,omitempty"`
+	Days       []int      `gorm:"type:jsonb;serializer:json" json:"days"`       // 0 = Sunday, empty means every day
+	StartTime  string     `gorm:"type:varchar(5)" json:"start_time"`            // "HH:MM", empty means all day
+	EndTime    string     `gorm:"type:varchar(5)" json:"end_time"`              // "HH:MM", before start_time runs past midnight
+	StartDate  string     `gorm:"type:varchar(10)" json:"start_date,omitempty"` // "YYYY-MM-DD", inclusive
+	EndDate    string     `gorm:"type:varchar(10)" json:"end_date,omitempty"`   // "YYYY-MM-DD", inclusive
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type MenuScheduleData struct {
+	Days      []int  `json:"days" binding:"dive,min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time" binding:"required_with=StartTime,omitempty,datetime=15:04"`
+	StartDate string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type SetMenuSchedulesData struct {
+	Schedules []MenuScheduleData `json:"schedules" binding:"dive"` // Replaces every schedule, an empty list means always served
+}
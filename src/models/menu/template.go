@@ -0,0 +1,44 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// MenuTemplate is a platform menu restaurants can start from, e.g. a starter menu for a South Indian café.
+// It keeps a copy of the menu it was made from, so later changes to that menu do not change the template.
+type MenuTemplate struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name        string     `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string     `gorm:"type:text" json:"description"`
+	Cuisine     string     `gorm:"type:varchar(50);index" json:"cuisine"`
+	Menu        *Menu      `gorm:"type:jsonb;serializer:json" json:"menu,omitempty"`
+	IsPublished bool       `gorm:"type:boolean;default:false" json:"is_published"` // Only published templates are shown to restaurants
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AddMenuTemplateData struct {
+	Name         string    `json:"name" binding:"required,max=100"`
+	Description  string    `json:"description"`
+	Cuisine      string    `json:"cuisine" binding:"max=50"`
+	RestaurantID uuid.UUID `json:"restaurant_id" binding:"required"` // Restaurant of the menu the template is made from
+	MenuID       uuid.UUID `json:"menu_id" binding:"required"`
+	IsPublished  bool      `json:"is_published"`
+}
+
+type UpdateMenuTemplateData struct {
+	Name        string  `json:"name" binding:"max=100"`
+	Description *string `json:"description"`
+	Cuisine     *string `json:"cuisine" binding:"omitempty,max=50"`
+	IsPublished *bool   `json:"is_published"`
+}
+
+// CloneMenuData copies a menu or a template into a restaurant
+type CloneMenuData struct {
+	RestaurantID    *uuid.UUID `json:"restaurant_id"`                      // Restaurant receiving the copy, the same restaurant when empty. Ignored for templates.
+	Name            string     `json:"name" binding:"max=100"`             // Name of the copy, the original name when empty
+	PriceAdjustment float64    `json:"price_adjustment" binding:"gt=-100"` // Percentage added to every price, -10 takes 10% off
+}
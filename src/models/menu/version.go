@@ -0,0 +1,62 @@
+package models_menu
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// MenuVersion is a snapshot of every menu of a restaurant.
+// Staff edit the menus directly, guests see the published version once one exists.
+type MenuVersion struct {
+	ID           uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_menu_version_number" json:"restaurant_id"`
+	Number       int               `gorm:"type:int;not null;uniqueIndex:idx_menu_version_number" json:"number"`
+	Status       MenuVersionStatus `gorm:"type:varchar(20);check:status IN ('draft','scheduled','published','archived');default:'draft';not null" json:"status"`
+	Note         string            `gorm:"type:varchar(255)" json:"note"`
+	Menus        []Menu            `gorm:"type:jsonb;serializer:json" json:"menus,omitempty"`
+	PublishAt    *time.Time        `gorm:"index" json:"publish_at"` // Set while the version is scheduled
+	PublishedAt  *time.Time        `json:"published_at"`
+	CreatedBy    *uuid.UUID        `gorm:"type:uuid" json:"created_by"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// MenuVersionStatus represents the possible states of a menu version
+type MenuVersionStatus string
+
+const (
+	MenuVersionDraft     MenuVersionStatus = "draft"     // Saved, not visible to guests
+	MenuVersionScheduled MenuVersionStatus = "scheduled" // Published automatically at PublishAt
+	MenuVersionPublished MenuVersionStatus = "published" // Visible to guests, one per restaurant
+	MenuVersionArchived  MenuVersionStatus = "archived"  // Previously published
+)
+
+// MenuChange is one difference between two menu versions
+type MenuChange struct {
+	Type   string            `json:"type"` // menu, category, item, option, modifier_group, modifier or combo
+	ID     uuid.UUID         `json:"id"`
+	Name   string            `json:"name"`
+	Change string            `json:"change"` // added, removed or changed
+	Fields []MenuFieldChange `json:"fields,omitempty"`
+}
+
+type MenuFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type CreateMenuVersionData struct {
+	Note      string     `json:"note" binding:"max=255"`
+	Publish   bool       `json:"publish"`    // Publish the new version right away
+	PublishAt *time.Time `json:"publish_at"` // Or schedule it
+}
+
+type PublishMenuVersionData struct {
+	PublishAt *time.Time `json:"publish_at"` // Publish now when empty
+}
+
+type RollbackMenuVersionData struct {
+	Note string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,54 @@
+package models_order
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// GuestVerification holds a one-time code sent to a guest's phone before they can order
+type GuestVerification struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID  `gorm:"type:uuid;not null;index:idx_guest_verification_phone" json:"restaurant_id"`
+	Phone        string     `gorm:"type:varchar(20);not null;index:idx_guest_verification_phone" json:"phone"`
+	CodeHash     string     `gorm:"type:varchar(64);not null" json:"-"`
+	Attempts     int        `gorm:"type:int;not null;default:0" json:"attempts"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	VerifiedAt   *time.Time `json:"verified_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// RestaurantOrderRules lets a restaurant automatically reject suspicious guest orders.
+// A zero limit disables the corresponding rule.
+type RestaurantOrderRules struct {
+	ID                    uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"restaurant_id"`
+	MaxOrderTotal         float64   `gorm:"type:decimal(10,2);default:0" json:"max_order_total"`
+	MaxItemQuantity       int       `gorm:"type:int;default:0" json:"max_item_quantity"`
+	MaxOrderItems         int       `gorm:"type:int;default:0" json:"max_order_items"`
+	MaxOpenOrdersPerPhone int       `gorm:"type:int;default:0" json:"max_open_orders_per_phone"`
+	OnlinePaymentAbove    float64   `gorm:"type:decimal(10,2);default:0" json:"online_payment_above"` // Onsite payment is refused above this total
+	BlockedPhones         []string  `gorm:"type:jsonb;serializer:json" json:"blocked_phones"`
+	CreatedAt             time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt             time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type SendGuestOTPData struct {
+	RestaurantID uuid.UUID `json:"restaurant_id" binding:"required"`
+	Phone        string    `json:"phone" binding:"required,e164"`
+}
+
+type VerifyGuestOTPData struct {
+	RestaurantID uuid.UUID `json:"restaurant_id" binding:"required"`
+	Phone        string    `json:"phone" binding:"required,e164"`
+	Code         string    `json:"code" binding:"required,len=6,numeric"`
+}
+
+type UpdateOrderRulesData struct {
+	MaxOrderTotal         *float64 `json:"max_order_total" binding:"omitempty,min=0"`
+	MaxItemQuantity       *int     `json:"max_item_quantity" binding:"omitempty,min=0"`
+	MaxOrderItems         *int     `json:"max_order_items" binding:"omitempty,min=0"`
+	MaxOpenOrdersPerPhone *int     `json:"max_open_orders_per_phone" binding:"omitempty,min=0"`
+	OnlinePaymentAbove    *float64 `json:"online_payment_above" binding:"omitempty,min=0"`
+	BlockedPhones         []string `json:"blocked_phones"`
+}
@@ -9,23 +9,27 @@ import (
 
 // Order represents the main order record
 type Order struct {
-	ID            uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	RestaurantID  uuid.UUID   `gorm:"type:uuid;not null" json:"restaurant_id"`
-	CustomerEmail string      `gorm:"type:varchar(255);" json:"customer_email"`
-	CustomerName  string      `gorm:"type:varchar(255);not null" json:"customer_name"`
-	CustomerPhone string      `gorm:"type:varchar(255);not null" json:"customer_phone"`
-	OrderItems    []OrderItem `gorm:"foreignKey:OrderID" json:"items"` // Adjusted relationship
-	PaymentType   string      `gorm:"type:varchar(20);check(payment_type in ('online', 'onsite'));not null" json:"payment_type"`
-	Status        OrderStatus `gorm:"type:varchar(20);not null" json:"status"`
-	OrderType     OrderType   `gorm:"type:varchar(20);not null" json:"order_type"`
-	SubTotal      float64     `gorm:"type:decimal(10,2);not null" json:"subtotal"`
-	Tax           float64     `gorm:"type:decimal(10,2);not null" json:"tax"`
-	ServiceFee    float64     `gorm:"type:decimal(10,2);not null" json:"service_fee"`
-	Total         float64     `gorm:"type:decimal(10,2);not null" json:"total"`
-	Notes         *string     `gorm:"type:text" json:"notes"` // Optional field
-	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
-	CompletedAt   *time.Time  `json:"completed_at"`
+	ID            uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID  uuid.UUID    `gorm:"type:uuid;not null" json:"restaurant_id"`
+	CustomerEmail string       `gorm:"type:varchar(255);" json:"customer_email"`
+	CustomerName  string       `gorm:"type:varchar(255);not null" json:"customer_name"`
+	CustomerPhone string       `gorm:"type:varchar(255);not null" json:"customer_phone"`
+	OrderItems    []OrderItem  `gorm:"foreignKey:OrderID" json:"items"` // Adjusted relationship
+	Combos        []OrderCombo `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE;" json:"combos"`
+	PaymentType   string       `gorm:"type:varchar(20);check(payment_type in ('online', 'onsite'));not null" json:"payment_type"`
+	Status        OrderStatus  `gorm:"type:varchar(20);not null" json:"status"`
+	OrderType     OrderType    `gorm:"type:varchar(20);not null" json:"order_type"`
+	TableID       *uuid.UUID   `gorm:"type:uuid;index" json:"table_id"`        // Set for DINEIN orders
+	SessionID     *uuid.UUID   `gorm:"type:uuid;index" json:"session_id"`      // Running tab of the table
+	MenuVersionID *uuid.UUID   `gorm:"type:uuid;index" json:"menu_version_id"` // Published menu version the order was priced against
+	SubTotal      float64      `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	Tax           float64      `gorm:"type:decimal(10,2);not null" json:"tax"`
+	ServiceFee    float64      `gorm:"type:decimal(10,2);not null" json:"service_fee"`
+	Total         float64      `gorm:"type:decimal(10,2);not null" json:"total"`
+	Notes         *string      `gorm:"type:text" json:"notes"` // Optional field
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	CompletedAt   *time.Time   `json:"completed_at"`
 }
 
 // OrderItem represents individual items within an order
@@ -41,6 +45,35 @@ type OrderItem struct {
 	ItemOptionID   uuid.UUID                  `gorm:"type:uuid" json:"item_option_id"`
 	ItemOption     models_menu.MenuItemOption `gorm:"foreignKey:ItemOptionID" json:"-"`
 	ItemOptionName string                     `gorm:"type:varchar(255)" json:"item_option_name"`
+	Modifiers      []OrderItemModifier        `gorm:"foreignKey:OrderItemID;constraint:OnDelete:CASCADE;" json:"modifiers"`
+	OrderComboID   *uuid.UUID                 `gorm:"type:uuid;index" json:"order_combo_id"`                     // Set for the items of a combo, priced with their share of the bundle
+	FoodCost       float64                    `gorm:"type:decimal(10,2);not null;default:0" json:"food_cost"`    // Cost of the ingredients used, set when the order is completed
+	PricingRuleID  *uuid.UUID                 `gorm:"type:uuid;index" json:"pricing_rule_id"`                    // Rule that changed the option price when ordered
+	PricingRule    string                     `gorm:"type:varchar(100)" json:"pricing_rule"`                     // Name of that rule, kept if it is deleted
+	PriceChange    float64                    `gorm:"type:decimal(10,2);not null;default:0" json:"price_change"` // Change of the option price per unit, negative for a discount
+}
+
+// OrderCombo is a snapshot of a combo ordered at its bundle price.
+// Its items are stored as order items so kitchen tickets and stock see them like any other item.
+type OrderCombo struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrderID   uuid.UUID `gorm:"type:uuid;not null;index" json:"order_id"`
+	ComboID   uuid.UUID `gorm:"type:uuid;not null" json:"combo_id"`
+	Name      string    `gorm:"type:varchar(100)" json:"name"`
+	Quantity  int       `gorm:"type:int;not null" json:"quantity"`
+	Price     float64   `gorm:"type:decimal(10,2);not null" json:"price"` // Bundle price with upcharges and modifiers
+	Subtotal  float64   `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OrderItemModifier is a snapshot of a modifier chosen for an order item, kept even if the menu changes
+type OrderItemModifier struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null;index" json:"order_item_id"`
+	ModifierID  uuid.UUID `gorm:"type:uuid;not null" json:"modifier_id"`
+	GroupName   string    `gorm:"type:varchar(100)" json:"group_name"`
+	Name        string    `gorm:"type:varchar(100)" json:"name"`
+	Price       float64   `gorm:"type:decimal(10,2);not null" json:"price"`
 }
 
 // OrderStatus represents the possible states of an order
@@ -55,6 +88,25 @@ const (
 	OrderStatusCancelled OrderStatus = "CANCELLED"
 )
 
+// OrderTransitions lists the statuses an order can move to from each status.
+// Orders only move forward, and a completed or cancelled order is closed.
+var OrderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusPreparing, OrderStatusReady, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPreparing, OrderStatusReady, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusPreparing: {OrderStatusReady, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusReady:     {OrderStatusCompleted, OrderStatusCancelled},
+}
+
+// CanMoveTo reports whether an order in this status can move to the next one
+func (status OrderStatus) CanMoveTo(next OrderStatus) bool {
+	for _, allowed := range OrderTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // OrderType represents the type of order
 type OrderType string
 
@@ -66,19 +118,35 @@ const (
 
 // Data structs for creating orders
 type CreateOrderItem struct {
-	MenuItemID     uuid.UUID  `json:"menu_item_id" binding:"required"`
-	Quantity       int        `json:"quantity" binding:"required,min=1"`
-	ItemOptionID   *uuid.UUID `json:"item_option_id"`
-	ItemOptionName *string    `json:"item_option_name"`
+	MenuItemID     uuid.UUID   `json:"menu_item_id" binding:"required"`
+	Quantity       int         `json:"quantity" binding:"required,min=1"`
+	ItemOptionID   *uuid.UUID  `json:"item_option_id"`
+	ItemOptionName *string     `json:"item_option_name"`
+	ModifierIDs    []uuid.UUID `json:"modifier_ids"` // Chosen modifiers of the item's modifier groups
 }
 
 type CreateOrder struct {
-	RestaurantID  uuid.UUID         `json:"restaurant_id" binding:"required"`
-	CustomerEmail string            `json:"customer_email" binding:"email"`
-	CustomerName  string            `json:"customer_name" binding:"required"`
-	CustomerPhone string            `json:"customer_phone" binding:"required"`
-	PaymentType   string            `json:"payment_type" binding:"required,oneof=online onsite"`
-	OrderType     string            `json:"order_type" binding:"required,oneof=DINEIN PICKUP DELIVERY"`
-	Notes         *string           `json:"notes"`
-	Items         []CreateOrderItem `json:"items" binding:"required,dive"`
+	RestaurantID  uuid.UUID          `json:"restaurant_id"` // Optional, taken from the table or guest token
+	CustomerEmail string             `json:"customer_email" binding:"email"`
+	CustomerName  string             `json:"customer_name" binding:"required"`
+	CustomerPhone string             `json:"customer_phone" binding:"required"`
+	PaymentType   string             `json:"payment_type" binding:"required,oneof=online onsite"`
+	OrderType     string             `json:"order_type" binding:"required,oneof=DINEIN PICKUP DELIVERY"`
+	TableToken    string             `json:"table_token"` // Token from the table QR code, required for DINEIN orders
+	GuestToken    string             `json:"guest_token"` // Token from phone verification, required when ordering without a table token
+	Notes         *string            `json:"notes"`
+	Items         []CreateOrderItem  `json:"items" binding:"dive"`
+	Combos        []CreateOrderCombo `json:"combos" binding:"dive"`
+}
+
+type CreateOrderCombo struct {
+	ComboID    uuid.UUID              `json:"combo_id" binding:"required"`
+	Quantity   int                    `json:"quantity" binding:"required,min=1"`
+	Selections []CreateComboSelection `json:"selections" binding:"dive"` // Slots left out use their default item
+}
+
+type CreateComboSelection struct {
+	SlotID      uuid.UUID   `json:"slot_id" binding:"required"`
+	MenuItemID  uuid.UUID   `json:"menu_item_id" binding:"required"`
+	ModifierIDs []uuid.UUID `json:"modifier_ids"`
 }
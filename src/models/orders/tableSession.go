@@ -0,0 +1,83 @@
+package models_order
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// TableSession is the running tab of a dine-in table, grouping every order placed at it
+type TableSession struct {
+	ID            uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID  uuid.UUID        `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	TableID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"table_id"`
+	Status        SessionStatus    `gorm:"type:varchar(20);check:status IN ('open','closed','settled');default:'open';not null" json:"status"`
+	CustomerName  string           `gorm:"type:varchar(255)" json:"customer_name"`
+	CustomerPhone string           `gorm:"type:varchar(255)" json:"customer_phone"`
+	Orders        []Order          `gorm:"foreignKey:SessionID" json:"orders"`
+	Payments      []SessionPayment `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE;" json:"payments"`
+	SplitMode     string           `gorm:"type:varchar(20)" json:"split_mode"`
+	SubTotal      float64          `gorm:"type:decimal(10,2);default:0" json:"subtotal"`
+	Tax           float64          `gorm:"type:decimal(10,2);default:0" json:"tax"`
+	ServiceFee    float64          `gorm:"type:decimal(10,2);default:0" json:"service_fee"`
+	Total         float64          `gorm:"type:decimal(10,2);default:0" json:"total"`
+	ClosedAt      *time.Time       `json:"closed_at"`
+	SettledAt     *time.Time       `json:"settled_at"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SessionPayment is one share of a split bill, paid online or at the counter
+type SessionPayment struct {
+	ID            uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	SessionID     uuid.UUID   `gorm:"type:uuid;not null;index" json:"session_id"`
+	Label         string      `gorm:"type:varchar(100)" json:"label"`
+	Amount        float64     `gorm:"type:decimal(10,2);not null" json:"amount"`
+	OrderItemIDs  []uuid.UUID `gorm:"type:jsonb;serializer:json" json:"order_item_ids,omitempty"` // Set when splitting by item
+	PaymentType   string      `gorm:"type:varchar(20)" json:"payment_type"`
+	Status        string      `gorm:"type:varchar(50);check:status IN ('successful','failed','pending');default:'pending';not null" json:"status"`
+	TransactionID string      `gorm:"type:varchar(255)" json:"transaction_id"`
+	PaymentLink   string      `gorm:"type:varchar(255)" json:"payment_link"`
+	PaidAt        *time.Time  `json:"paid_at"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SessionStatus represents the possible states of a table session
+type SessionStatus string
+
+const (
+	SessionStatusOpen    SessionStatus = "open"    // Accepting new orders
+	SessionStatusClosed  SessionStatus = "closed"  // Bill requested, waiting for payments
+	SessionStatusSettled SessionStatus = "settled" // Fully paid
+)
+
+// Ways a closed bill can be split
+const (
+	SplitModeEqual  = "equal"
+	SplitModeItem   = "item"
+	SplitModeCustom = "custom"
+)
+
+type OpenSessionData struct {
+	TableID       uuid.UUID `json:"table_id" binding:"required"`
+	CustomerName  string    `json:"customer_name"`
+	CustomerPhone string    `json:"customer_phone"`
+}
+
+type SplitBillData struct {
+	Mode    string           `json:"mode" binding:"required,oneof=equal item custom"`
+	Parts   int              `json:"parts" binding:"omitempty,min=1,max=50"` // equal
+	Items   []SplitItemGroup `json:"items" binding:"dive"`                   // item
+	Amounts []SplitAmount    `json:"amounts" binding:"dive"`                 // custom
+}
+
+type SplitItemGroup struct {
+	Label        string      `json:"label"`
+	OrderItemIDs []uuid.UUID `json:"order_item_ids" binding:"required,min=1"`
+}
+
+type SplitAmount struct {
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
@@ -13,7 +13,8 @@ type Plan struct {
 	Price                   float64                  `gorm:"type:decimal(10,2);not null" json:"price"`
 	IsActive                bool                     `gorm:"type:boolean;default:true" json:"is_active"`
 	TrialPeriod             bool                     `gorm:"type:boolean;default:true" json:"trial_period"`
-	PlanFeatureAssociations []PlanFeatureAssociation `gorm:"foreignKey:PlanID" json:"-"` // Corrected the field name
+	MaxOutlets              int                      `gorm:"type:int;not null;default:1" json:"max_outlets"` // Outlets a brand subscription on this plan covers
+	PlanFeatureAssociations []PlanFeatureAssociation `gorm:"foreignKey:PlanID" json:"-"`                     // Corrected the field name
 	CreatedAt               time.Time                `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt               time.Time                `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -43,6 +44,7 @@ type PlanResponse struct {
 	Price       float64       `json:"price"`
 	IsActive    bool          `json:"is_active"`
 	TrialPeriod bool          `json:"trial_period"`
+	MaxOutlets  int           `json:"max_outlets"`
 	Feature     []PlanFeature `json:"features"`
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
@@ -54,6 +56,7 @@ type AddPlanData struct {
 	Price       float64 `json:"price" binding:"required"`
 	IsActive    bool    `json:"is_active" binding:"default=false"`
 	TrialPeriod bool    `json:"trial_period" binding:"required,default=false"`
+	MaxOutlets  int     `json:"max_outlets" binding:"omitempty,min=1"` // Defaults to 1
 }
 
 type UpdatePlanData struct {
@@ -62,6 +65,7 @@ type UpdatePlanData struct {
 	Price       float64 `json:"price"`
 	IsActive    bool    `json:"is_active"`
 	TrialPeriod bool    `json:"trial_period"`
+	MaxOutlets  int     `json:"max_outlets" binding:"omitempty,min=1"`
 }
 
 type AddPlanFeatureData struct {
@@ -0,0 +1,58 @@
+package models_restaurant
+
+import (
+	models_subscription "dine-server/src/models/subscriptions"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Brand groups the outlets of a chain. Its menus can be shared with every outlet
+// and one brand subscription covers up to the plan's MaxOutlets outlets.
+type Brand struct {
+	ID             uuid.UUID                         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name           string                            `gorm:"type:varchar(100);not null" json:"name"`
+	Description    string                            `gorm:"type:varchar(500)" json:"description"`
+	LogoImageUrl   string                            `gorm:"type:varchar(255)" json:"logo_image_url"`
+	AdminID        uuid.UUID                         `gorm:"type:uuid;not null;index" json:"admin_id"`
+	SubscriptionID *uuid.UUID                        `gorm:"type:uuid" json:"subscription_id"`
+	Subscription   *models_subscription.Subscription `gorm:"foreignKey:SubscriptionID" json:"subscription,omitempty"`
+	Outlets        []Restaurant                      `gorm:"foreignKey:BrandID" json:"outlets,omitempty"`
+	CreatedAt      time.Time                         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time                         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AddBrandData struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description" binding:"max=500"`
+	LogoImageUrl string `json:"logo_image_url"`
+}
+
+type UpdateBrandData struct {
+	Name         string  `json:"name" binding:"max=100"`
+	Description  *string `json:"description" binding:"omitempty,max=500"`
+	LogoImageUrl *string `json:"logo_image_url"`
+}
+
+type AddBrandOutletData struct {
+	RestaurantID uuid.UUID `json:"restaurant_id" binding:"required"`
+}
+
+// BrandOutletSales is how one outlet sold over a report period
+type BrandOutletSales struct {
+	RestaurantID uuid.UUID `json:"restaurant_id"`
+	Name         string    `json:"name"`
+	Orders       int       `json:"orders"`
+	Revenue      float64   `json:"revenue"`
+	AverageOrder float64   `json:"average_order"`
+	FoodCost     float64   `json:"food_cost"`
+}
+
+// BrandItemSales is how one item sold across the outlets of a brand
+type BrandItemSales struct {
+	MenuItemID uuid.UUID `json:"menu_item_id"`
+	Name       string    `json:"name"`
+	Quantity   int       `json:"quantity"`
+	Revenue    float64   `json:"revenue"`
+	Outlets    int       `json:"outlets"` // Outlets that sold the item
+}
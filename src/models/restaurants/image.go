@@ -0,0 +1,35 @@
+package models_restaurant
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Image is a file uploaded for the restaurant, a category or an item, with its thumbnail.
+// It stays stored while its owner uses it or a menu version shows it, and is removed after.
+type Image struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID  `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	OwnerType    ImageOwner `gorm:"type:varchar(20);check:owner_type IN ('logo','banner','category','item');not null" json:"owner_type"`
+	OwnerID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
+	Key          string     `gorm:"type:varchar(255);not null" json:"-"`
+	ThumbnailKey string     `gorm:"type:varchar(255);not null" json:"-"`
+	URL          string     `gorm:"type:varchar(255);not null" json:"url"`
+	ThumbnailURL string     `gorm:"type:varchar(255);not null" json:"thumbnail_url"`
+	ContentType  string     `gorm:"type:varchar(50)" json:"content_type"`
+	Size         int        `gorm:"type:int" json:"size"` // Stored size in bytes, after scaling down
+	Width        int        `gorm:"type:int" json:"width"`
+	Height       int        `gorm:"type:int" json:"height"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ImageOwner is what an image is shown for
+type ImageOwner string
+
+const (
+	ImageLogo     ImageOwner = "logo"
+	ImageBanner   ImageOwner = "banner"
+	ImageCategory ImageOwner = "category"
+	ImageItem     ImageOwner = "item"
+)
@@ -0,0 +1,60 @@
+package models_restaurant
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// OpeningHour is one shift of a restaurant's weekly schedule. A day can have several shifts.
+// Times are "HH:MM" in the restaurant's timezone; a shift closing before it opens runs past midnight.
+type OpeningHour struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	DayOfWeek    int       `gorm:"type:int;not null;check:day_of_week BETWEEN 0 AND 6" json:"day_of_week"` // 0 = Sunday
+	OpensAt      string    `gorm:"type:varchar(5);not null" json:"opens_at"`
+	ClosesAt     string    `gorm:"type:varchar(5);not null" json:"closes_at"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// RestaurantClosure closes a restaurant for whole days, e.g. holidays
+type RestaurantClosure struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	StartDate    string    `gorm:"type:varchar(10);not null" json:"start_date"` // "YYYY-MM-DD", inclusive
+	EndDate      string    `gorm:"type:varchar(10);not null" json:"end_date"`   // "YYYY-MM-DD", inclusive
+	Reason       string    `gorm:"type:varchar(255)" json:"reason"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type OpeningHourData struct {
+	DayOfWeek *int   `json:"day_of_week" binding:"required,min=0,max=6"`
+	OpensAt   string `json:"opens_at" binding:"required,datetime=15:04"`
+	ClosesAt  string `json:"closes_at" binding:"required,datetime=15:04"`
+}
+
+type UpdateScheduleData struct {
+	Timezone string             `json:"timezone"`
+	Hours    *[]OpeningHourData `json:"hours" binding:"omitempty,dive"` // Replaces the whole week when set
+}
+
+type AddClosureData struct {
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"omitempty,datetime=2006-01-02"` // Defaults to start_date
+	Reason    string `json:"reason"`
+}
+
+type PauseOrdersData struct {
+	Paused *bool `json:"paused" binding:"required"`
+}
+
+type ScheduleResponse struct {
+	Timezone      string              `json:"timezone"`
+	OrdersPaused  bool                `json:"orders_paused"`
+	IsOpenNow     bool                `json:"is_open_now"`
+	NextOpeningAt *time.Time          `json:"next_opening_at"`
+	Hours         []OpeningHour       `json:"hours"`
+	Closures      []RestaurantClosure `json:"closures"`
+}
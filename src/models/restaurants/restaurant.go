@@ -47,6 +47,7 @@ type Restaurant struct {
 	Location       Location                          `gorm:"type:json" json:"location"`
 	ImageURL       string                            `gorm:"type:varchar(255)" json:"image_url"`
 	AdminID        uuid.UUID                         `gorm:"type:uuid;not null" json:"admin_id"`
+	BrandID        *uuid.UUID                        `gorm:"type:uuid;index" json:"brand_id"` // Set while the restaurant is an outlet of a brand
 	BannerImageUrl string                            `json:"banner_image_url"`
 	LogoImageUrl   string                            `json:"logo_image_url"`
 	Menu           []models_menu.Menu                `gorm:"foreignKey:RestaurantID" json:"menu"`
@@ -63,6 +64,9 @@ type Restaurant struct {
 	IsActive       bool                              `gorm:"type:boolean;default:true" json:"is_active"`
 	HasParking     bool                              `gorm:"type:boolean;default:false" json:"has_parking"`
 	HasPickup      bool                              `gorm:"type:boolean;default:false" json:"has_delivery"`
+	Timezone       string                            `gorm:"type:varchar(50);default:'Asia/Kolkata'" json:"timezone"`
+	OrdersPaused   bool                              `gorm:"type:boolean;default:false" json:"orders_paused"` // Manual switch to stop taking orders
+	Language       string                            `gorm:"type:varchar(10);default:'en'" json:"language"`   // Default language of the names and descriptions
 }
 
 type AddRestaurantData struct {
@@ -80,19 +84,24 @@ type AddRestaurantData struct {
 }
 
 type ResponseRestaurantData struct {
-	ID             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	PureVeg        bool      `json:"pure_veg"`
-	Description    string    `json:"description"`
-	Location       Location  `json:"location"`
-	BannerImageUrl string    `json:"banner_image_url"`
-	LogoImageUrl   string    `json:"logo_image_url"`
-	Phone          string    `json:"phone"`
-	Email          string    `json:"email"`
-	IsActive       bool      `json:"is_active"`
-	HasParking     bool      `json:"has_parking"`
-	HasPickup      bool      `json:"has_delivery"`
-	NumberOfTables int       `json:"number_of_tables"`
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	PureVeg        bool       `json:"pure_veg"`
+	Description    string     `json:"description"`
+	Location       Location   `json:"location"`
+	BannerImageUrl string     `json:"banner_image_url"`
+	LogoImageUrl   string     `json:"logo_image_url"`
+	Phone          string     `json:"phone"`
+	Email          string     `json:"email"`
+	IsActive       bool       `json:"is_active"`
+	HasParking     bool       `json:"has_parking"`
+	HasPickup      bool       `json:"has_delivery"`
+	NumberOfTables int        `json:"number_of_tables"`
+	Timezone       string     `json:"timezone"`
+	OrdersPaused   bool       `json:"orders_paused"`
+	IsOpenNow      bool       `json:"is_open_now"`
+	NextOpeningAt  *time.Time `json:"next_opening_at"`
+	Language       string     `json:"language"`
 }
 
 type UpdateRestaurantData struct {
@@ -108,4 +117,5 @@ type UpdateRestaurantData struct {
 	HasParking     bool     `json:"has_parking"`
 	HasPickup      bool     `json:"has_delivery"`
 	NumberOfTables int      `json:"number_of_tables"`
+	Language       string   `json:"language"` // Default language, e.g. "en"
 }
@@ -0,0 +1,127 @@
+package models_restaurant
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// StaffMember gives a user a role in a restaurant. The owner of the restaurant, its AdminID, has no membership.
+type StaffMember struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_staff_restaurant_user" json:"restaurant_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_staff_restaurant_user" json:"user_id"`
+	Role         StaffRole `gorm:"type:varchar(20);check:role IN ('manager','cashier','waiter','kitchen');not null" json:"role"`
+	InvitedBy    uuid.UUID `gorm:"type:uuid" json:"invited_by"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// StaffInvitation invites someone by email or phone to join the staff of a restaurant.
+// The token sent to them is only stored hashed.
+type StaffInvitation struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID  `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	Email        string     `gorm:"type:varchar(100)" json:"email"`
+	Phone        string     `gorm:"type:varchar(20)" json:"phone"`
+	Role         StaffRole  `gorm:"type:varchar(20);check:role IN ('manager','cashier','waiter','kitchen');not null" json:"role"`
+	TokenHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	InvitedBy    uuid.UUID  `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	AcceptedBy   *uuid.UUID `gorm:"type:uuid" json:"accepted_by"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type InviteStaffData struct {
+	Email string    `json:"email" binding:"required_without=Phone,omitempty,email,max=100"`
+	Phone string    `json:"phone" binding:"required_without=Email,omitempty,max=20"`
+	Role  StaffRole `json:"role" binding:"required,oneof=manager cashier waiter kitchen"`
+}
+
+type AcceptInvitationData struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type UpdateStaffMemberData struct {
+	Role StaffRole `json:"role" binding:"required,oneof=manager cashier waiter kitchen"`
+}
+
+// StaffMemberResponse is a member of the staff with the details of their user
+type StaffMemberResponse struct {
+	ID        *uuid.UUID `json:"id"` // Empty for the owner, who has no membership
+	UserID    uuid.UUID  `json:"user_id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	Role      StaffRole  `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// StaffRole is what a user does in a restaurant, it decides their permissions
+type StaffRole string
+
+const (
+	StaffRoleOwner   StaffRole = "owner"
+	StaffRoleManager StaffRole = "manager"
+	StaffRoleCashier StaffRole = "cashier"
+	StaffRoleWaiter  StaffRole = "waiter"
+	StaffRoleKitchen StaffRole = "kitchen"
+)
+
+// Permission is an action a user may be allowed to take, named resource.action like menu.item.update.
+// Platform admins hold every permission, the platform.* permissions are theirs only.
+type Permission string
+
+// The permissions checked in handlers, every route declares its own in the policy of the API
+const (
+	PermissionMenuView   Permission = "menu.view" // Drafts, versions and what guests do not see
+	PermissionMenuCreate Permission = "menu.create"
+)
+
+// RolePermissions are the permissions granted to each staff role.
+// A grant ending in .* covers every permission under it, menu.* covers menu.item.update.
+var RolePermissions = map[StaffRole][]Permission{
+	StaffRoleOwner: {
+		"restaurant.*", "staff.*", "menu.*", "table.*", "order.*", "session.*", "payment.*", "kitchen.*", "inventory.*", "report.*",
+	},
+	StaffRoleManager: {
+		"restaurant.view", "restaurant.update", "restaurant.schedule.*", "restaurant.order_rules.*",
+		"staff.*", "menu.*", "table.*", "order.*", "session.*", "payment.*", "kitchen.*", "inventory.*", "report.*",
+	},
+	StaffRoleCashier: {
+		"restaurant.view", "menu.view", "table.view", "table.status.update", "order.*", "session.*", "payment.*",
+	},
+	StaffRoleWaiter: {
+		"restaurant.view", "menu.view", "table.view", "table.status.update", "order.*", "session.open", "session.view", "kitchen.view",
+	},
+	StaffRoleKitchen: {
+		"restaurant.view", "menu.view", "kitchen.view", "kitchen.ticket.*", "inventory.view", "inventory.86.*",
+	},
+}
+
+// Can reports whether the role holds a permission
+func (role StaffRole) Can(permission Permission) bool {
+	for _, grant := range RolePermissions[role] {
+		if grant == permission {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(string(grant), "*"); ok && strings.HasPrefix(string(permission), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Manages reports whether the role may invite, change or remove staff with the other role.
+// Managers only manage the roles below them, owners manage everyone.
+func (role StaffRole) Manages(other StaffRole) bool {
+	switch role {
+	case StaffRoleOwner:
+		return other != StaffRoleOwner
+	case StaffRoleManager:
+		return other == StaffRoleCashier || other == StaffRoleWaiter || other == StaffRoleKitchen
+	}
+	return false
+}
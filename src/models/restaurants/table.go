@@ -0,0 +1,53 @@
+package models_restaurant
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// RestaurantTable represents a dine-in table of a restaurant
+type RestaurantTable struct {
+	ID           uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_restaurant_table_number" json:"restaurant_id"`
+	Number       string      `gorm:"type:varchar(20);not null;uniqueIndex:idx_restaurant_table_number" json:"number"`
+	Capacity     int         `gorm:"type:int;not null;default:2" json:"capacity"`
+	Area         string      `gorm:"type:varchar(50)" json:"area"` // e.g. "Indoor", "Rooftop"
+	Status       TableStatus `gorm:"type:varchar(20);check:status IN ('free','occupied','reserved','inactive');default:'free';not null" json:"status"`
+	TokenVersion int         `gorm:"type:int;not null;default:1" json:"-"` // bumped to invalidate printed QR codes
+	CreatedAt    time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableStatus represents the possible states of a table
+type TableStatus string
+
+const (
+	TableStatusFree     TableStatus = "free"
+	TableStatusOccupied TableStatus = "occupied"
+	TableStatusReserved TableStatus = "reserved"
+	TableStatusInactive TableStatus = "inactive"
+)
+
+type AddTableData struct {
+	Number   string `json:"number" binding:"required"`
+	Capacity int    `json:"capacity" binding:"omitempty,min=1"`
+	Area     string `json:"area"`
+}
+
+type UpdateTableData struct {
+	Number   string `json:"number"`
+	Capacity int    `json:"capacity" binding:"omitempty,min=1"`
+	Area     string `json:"area"`
+}
+
+type UpdateTableStatusData struct {
+	Status TableStatus `json:"status" binding:"required,oneof=free occupied reserved inactive"`
+}
+
+type TableQRResponse struct {
+	TableID uuid.UUID `json:"table_id"`
+	Number  string    `json:"number"`
+	Token   string    `json:"token"`
+	MenuURL string    `json:"menu_url"`
+}
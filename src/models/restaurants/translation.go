@@ -0,0 +1,76 @@
+package models_restaurant
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Translation is the text of a field of the restaurant, a category or an item in another language.
+// The text in the default language of the restaurant stays on the record itself.
+type Translation struct {
+	ID           uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RestaurantID uuid.UUID         `gorm:"type:uuid;not null;index" json:"restaurant_id"`
+	EntityType   TranslationEntity `gorm:"type:varchar(20);check:entity_type IN ('restaurant','category','item');not null" json:"entity_type"`
+	EntityID     uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_translation_field" json:"entity_id"`
+	Field        string            `gorm:"type:varchar(20);check:field IN ('name','description');not null;uniqueIndex:idx_translation_field" json:"field"`
+	Locale       string            `gorm:"type:varchar(10);not null;index;uniqueIndex:idx_translation_field" json:"locale"` // e.g. "fr" or "pt-br"
+	Value        string            `gorm:"type:text;not null" json:"value"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TranslationEntity is the kind of record a translation belongs to
+type TranslationEntity string
+
+const (
+	TranslationRestaurant TranslationEntity = "restaurant"
+	TranslationCategory   TranslationEntity = "category"
+	TranslationItem       TranslationEntity = "item"
+)
+
+// TranslatedFields are the fields that can be translated
+var TranslatedFields = []string{"name", "description"}
+
+// TranslationRow is one line of a translation file: the text to translate and its translation
+type TranslationRow struct {
+	Row        int               `json:"-"`
+	EntityType TranslationEntity `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Field      string            `json:"field"`
+	Source     string            `json:"source"` // Text in the default language, ignored on import
+	Value      string            `json:"value"`
+}
+
+// TranslationError is a problem found in a row of a translation file
+type TranslationError struct {
+	Row   int    `json:"row"`
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// TranslationFile is the JSON layout of a translation file
+type TranslationFile struct {
+	Locale       string           `json:"locale"`
+	Translations []TranslationRow `json:"translations"`
+}
+
+type SetTranslationsData struct {
+	Translations []TranslationData `json:"translations" binding:"required,min=1,dive"`
+}
+
+type TranslationData struct {
+	EntityType TranslationEntity `json:"entity_type" binding:"required,oneof=restaurant category item"`
+	EntityID   uuid.UUID         `json:"entity_id" binding:"required"`
+	Field      string            `json:"field" binding:"required,oneof=name description"`
+	Value      string            `json:"value"` // Removes the translation when empty
+}
+
+// TranslationSummary counts what setting or importing translations changes
+type TranslationSummary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"` // Rows left empty in a file
+}
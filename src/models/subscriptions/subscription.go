@@ -14,7 +14,8 @@ type Subscription struct {
 	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	UserID uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
 
-	RestaurantID       uuid.UUID                  `gorm:"type:uuid;not null;ForeignKey:RestaurantID" json:"restaurant_id"`
+	RestaurantID       *uuid.UUID                 `gorm:"type:uuid;ForeignKey:RestaurantID" json:"restaurant_id"`
+	BrandID            *uuid.UUID                 `gorm:"type:uuid;index" json:"brand_id"` // Set for brand subscriptions, which cover several outlets
 	PlanID             uuid.UUID                  `gorm:"type:uuid;not null" json:"plan_id"`
 	Plan               models_plan.Plan           `gorm:"foreignKey:PlanID;" json:"plan"`
 	StartDate          time.Time                  `gorm:"type:date;not null" json:"start_date"`
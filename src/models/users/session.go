@@ -0,0 +1,34 @@
+package models_user
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// UserSession is a device a user signed in on. Its refresh token is rotated on every refresh and only the hash
+// of the latest one is stored, so an older token coming back means it was copied and the whole session is revoked.
+type UserSession struct {
+	ID            uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID        uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshHash   string               `gorm:"type:varchar(64);not null" json:"-"`
+	Device        string               `gorm:"type:varchar(100)" json:"device"`
+	IP            string               `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent     string               `gorm:"type:varchar(255)" json:"user_agent"`
+	LastUsedAt    time.Time            `gorm:"not null" json:"last_used_at"`
+	ExpiresAt     time.Time            `gorm:"not null" json:"expires_at"`
+	RevokedAt     *time.Time           `json:"revoked_at,omitempty"`
+	RevokedReason SessionRevokedReason `gorm:"type:varchar(20)" json:"revoked_reason,omitempty"`
+	CreatedAt     time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	Current       bool                 `gorm:"-" json:"current"` // The session of the request listing the sessions
+}
+
+// SessionRevokedReason is why a session was ended before it expired
+type SessionRevokedReason string
+
+const (
+	SessionLogout   SessionRevokedReason = "logout"   // The user logged out on the device
+	SessionRevoked  SessionRevokedReason = "revoked"  // The user ended the session from another device
+	SessionReused   SessionRevokedReason = "reused"   // A rotated refresh token was used again
+	SessionPassword SessionRevokedReason = "password" // The password was changed or reset
+)
@@ -10,19 +10,20 @@ import (
 )
 
 type User struct {
-	ID            uuid.UUID                      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name          string                         `gorm:"type:varchar(100);not null" json:"name"`
-	Email         string                         `gorm:"type:varchar(100);not null;uniqueIndex:idx_email_phone" json:"email"`
-	Phone         string                         `gorm:"type:varchar(20);not null;uniqueIndex:idx_email_phone" json:"phone"`
-	VerifiedEmail bool                           `gorm:"type:boolean;default:false" json:"verified_email"`
-	VerifiedPhone bool                           `gorm:"type:boolean;default:false" json:"verified_phone"`
-	Password      string                         `gorm:"type:varchar(255);not null" json:"-"`
-	Role          string                         `gorm:"type:varchar(50);not null;default:'restaurant_admin';check:role IN ('admin', 'restaurant_admin')" json:"role"`
-	SignupSource  string                         `gorm:"type:varchar(50);not null;default:'website';check:signup_source IN ('website', 'google', 'facebook', 'apple')" json:"signup_source"`
-	ProfileImage  string                         `gorm:"type:varchar(255)" json:"profile_image"`
-	Restaurants   []models_restaurant.Restaurant `gorm:"foreignKey:AdminID;references:ID" json:"restaurants"`
-	CreatedAt     time.Time                      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time                      `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uuid.UUID                      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name              string                         `gorm:"type:varchar(100);not null" json:"name"`
+	Email             string                         `gorm:"type:varchar(100);not null;uniqueIndex:idx_email_phone" json:"email"`
+	Phone             string                         `gorm:"type:varchar(20);not null;uniqueIndex:idx_email_phone" json:"phone"`
+	VerifiedEmail     bool                           `gorm:"type:boolean;default:false" json:"verified_email"`
+	VerifiedPhone     bool                           `gorm:"type:boolean;default:false" json:"verified_phone"`
+	Password          string                         `gorm:"type:varchar(255);not null" json:"-"`
+	Role              string                         `gorm:"type:varchar(50);not null;default:'restaurant_admin';check:role IN ('admin', 'restaurant_admin')" json:"role"`
+	SignupSource      string                         `gorm:"type:varchar(50);not null;default:'website';check:signup_source IN ('website', 'google', 'facebook', 'apple')" json:"signup_source"`
+	ProfileImage      string                         `gorm:"type:varchar(255)" json:"profile_image"`
+	PasswordChangedAt *time.Time                     `json:"-"`
+	Restaurants       []models_restaurant.Restaurant `gorm:"foreignKey:AdminID;references:ID" json:"restaurants"`
+	CreatedAt         time.Time                      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time                      `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type UpdateUserDataByAdmin struct {
@@ -31,11 +32,11 @@ type UpdateUserDataByAdmin struct {
 	Phone string `json:"phone"`
 }
 
+// UpdateUserDataByUser changes the profile of the user, the password is changed with ChangePasswordData
 type UpdateUserDataByUser struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	Password string `json:"password"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
 }
 
 type UserResponse struct {
@@ -48,7 +49,8 @@ type UserResponse struct {
 }
 
 type UserJwt struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Role string `json:"role"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	SessionID string `json:"session_id"`
 }
@@ -0,0 +1,37 @@
+package models_user
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// UserVerification is a code sent to prove a user owns their email or phone.
+// Emails get a long token in a link, phones a short OTP. Only the hash of the code is stored.
+type UserVerification struct {
+	ID         uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID     uuid.UUID           `gorm:"type:uuid;not null;index:idx_user_verification_channel" json:"user_id"`
+	Channel    VerificationChannel `gorm:"type:varchar(10);not null;check:channel IN ('email','phone');index:idx_user_verification_channel" json:"channel"`
+	Target     string              `gorm:"type:varchar(100);not null" json:"target"` // The email or phone the code was sent to
+	CodeHash   string              `gorm:"type:varchar(64);not null;index" json:"-"`
+	Attempts   int                 `gorm:"type:int;not null;default:0" json:"attempts"`
+	ExpiresAt  time.Time           `gorm:"not null" json:"expires_at"`
+	VerifiedAt *time.Time          `json:"verified_at"`
+	CreatedAt  time.Time           `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// VerificationChannel is what a verification proves the user owns
+type VerificationChannel string
+
+const (
+	VerificationEmail VerificationChannel = "email"
+	VerificationPhone VerificationChannel = "phone"
+)
+
+type VerifyEmailData struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type VerifyPhoneData struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"dine-server/src/api/v1/policy"
 	routes_v1 "dine-server/src/routes/v1"
 
 	"github.com/gin-gonic/gin"
@@ -8,15 +9,30 @@ import (
 
 // V1Routes sets up all version 1 routes
 func V1Routes(r *gin.Engine) {
-	v1 := r.Group("/api/v1")
+	// Every route is authorized by its rule in the policy, declared before the routes so it applies to all groups
+	v1 := r.Group("/api/v1", policy.Enforce)
 	routes_v1.SetupAuthRoutes(v1.Group("/auth"))
 	routes_v1.SetupUserRoutes(v1.Group("/users"))
 	routes_v1.SetupSubscriptionRoutes(v1.Group("/subscriptions"))
 	routes_v1.SetupRestaurantRoutes(v1.Group("/restaurants"))
+	routes_v1.SetupBrandRoutes(v1.Group("/brands"))
+	routes_v1.SetupInvitationRoutes(v1.Group("/invitations"))
 	routes_v1.SetupPlanRoutes(v1.Group("/plans"))
 	routes_v1.SetupPaymentRoutes(v1.Group("/payments"))
 	routes_v1.SetupOrderRoutes(v1.Group("/orders"))
 	routes_v1.SetupMenuRoutes(v1.Group("/:restaurant_id/menus"))
+	routes_v1.SetupMenuTemplateRoutes(v1.Group("/menu-templates"))
+	routes_v1.SetupMenuOverrideRoutes(v1.Group("/:restaurant_id/menu-overrides"))
+	routes_v1.SetupMenuVersionRoutes(v1.Group("/:restaurant_id/menu-versions"))
+	routes_v1.SetupKitchenRoutes(v1.Group("/:restaurant_id/kitchen"))
+	routes_v1.SetupInventoryRoutes(v1.Group("/:restaurant_id/inventory"))
+	routes_v1.SetupTableRoutes(v1.Group("/:restaurant_id/tables"))
+	routes_v1.SetupTableSessionRoutes(v1.Group("/:restaurant_id/sessions"))
+	routes_v1.SetupOrderRuleRoutes(v1.Group("/:restaurant_id/order-rules"))
+	routes_v1.SetupScheduleRoutes(v1.Group("/:restaurant_id/schedule"))
+	routes_v1.SetupTranslationRoutes(v1.Group("/:restaurant_id/translations"))
+	routes_v1.SetupPricingRuleRoutes(v1.Group("/:restaurant_id/pricing-rules"))
+	routes_v1.SetupStaffRoutes(v1.Group("/:restaurant_id/staff"))
 	routes_v1.SetupPromoCodeRoutes(v1.Group("/promo-code"))
 	routes_v1.SetupWorkflowRoutes(v1.Group("/workflow"))
 }
@@ -1,7 +1,10 @@
 package routes_v1
 
 import (
+	"dine-server/src/api/v1/middleware"
 	services "dine-server/src/api/v1/services/users"
+	"dine-server/src/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,4 +18,20 @@ func SetupAuthRoutes(authGroup *gin.RouterGroup) {
 	authGroup.GET("/google", services.GoogleLogin)
 	authGroup.GET("/google/callback", services.GoogleCallback)
 
+	// Devices the user is signed in on
+	authGroup.GET("/sessions", services.GetSessions)
+	authGroup.DELETE("/sessions", services.RevokeOtherSessions)
+	authGroup.DELETE("/sessions/:id", services.RevokeSession)
+
+	// Email and phone verification, limited per IP
+	authGroup.POST("/verify/email/send", middleware.RateLimitByIP(utils.NewRateLimiter(10, time.Hour)), services.SendEmailVerification)     // Email a verification link
+	authGroup.POST("/verify/email", middleware.RateLimitByIP(utils.NewRateLimiter(20, 10*time.Minute)), services.VerifyEmail)               // Verify the email with the token of the link
+	authGroup.POST("/verify/phone/send", middleware.RateLimitByIP(utils.NewRateLimiter(5, 10*time.Minute)), services.SendPhoneVerification) // Text a verification code
+	authGroup.POST("/verify/phone", middleware.RateLimitByIP(utils.NewRateLimiter(20, 10*time.Minute)), services.VerifyPhone)               // Verify the phone with the code
+
+	// Password reset and change, limited per IP
+	authGroup.POST("/password/forgot", middleware.RateLimitByIP(utils.NewRateLimiter(10, time.Hour)), services.ForgotPassword)      // Email a reset link
+	authGroup.POST("/password/reset", middleware.RateLimitByIP(utils.NewRateLimiter(20, 10*time.Minute)), services.ResetPassword)   // Set a new password with the token of the link
+	authGroup.POST("/password/change", middleware.RateLimitByIP(utils.NewRateLimiter(10, 10*time.Minute)), services.ChangePassword) // Change the password with the current one
+
 }
@@ -0,0 +1,20 @@
+package routes_v1
+
+import (
+	services_brand "dine-server/src/api/v1/services/brands"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBrandRoutes(brandGroup *gin.RouterGroup) {
+	brandGroup.POST("/", services_brand.CreateBrand)                                         // Create a brand
+	brandGroup.GET("/", services_brand.GetBrands)                                            // Get the brands of the user, admins get all of them
+	brandGroup.GET("/:brand_id", services_brand.GetBrandByID)                                // Get a brand with its outlets and shared menus
+	brandGroup.PUT("/:brand_id", services_brand.UpdateBrand)                                 // Update a brand
+	brandGroup.DELETE("/:brand_id", services_brand.DeleteBrand)                              // Delete a brand, its outlets become independent
+	brandGroup.POST("/:brand_id/outlets", services_brand.AddBrandOutlet)                     // Add a restaurant to the brand
+	brandGroup.DELETE("/:brand_id/outlets/:restaurant_id", services_brand.RemoveBrandOutlet) // Remove an outlet from the brand
+	brandGroup.PUT("/:brand_id/menus/:menu_id", services_brand.ShareBrandMenu)               // Share an outlet menu with every outlet
+	brandGroup.DELETE("/:brand_id/menus/:menu_id", services_brand.UnshareBrandMenu)          // Stop sharing a menu
+	brandGroup.GET("/:brand_id/reports/sales", services_brand.GetBrandSalesReport)           // Compare the sales of the outlets
+}
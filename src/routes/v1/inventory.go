@@ -0,0 +1,76 @@
+package routes_v1
+
+import (
+	services_inventory "dine-server/src/api/v1/services/inventory"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupInventoryRoutes(inventoryGroup *gin.RouterGroup) {
+	// Routes for Item Stock
+	stockGroup := inventoryGroup.Group("/stock")
+	{
+		stockGroup.GET("/", services_inventory.GetItemStocks)                    // Get tracked stocks, supports ?low=true
+		stockGroup.PUT("/", services_inventory.SetItemStock)                     // Track an item or option, or update its count and levels
+		stockGroup.POST("/:stock_id/adjust", services_inventory.AdjustItemStock) // Add or remove portions
+		stockGroup.DELETE("/:stock_id", services_inventory.DeleteItemStock)      // Stop tracking an item or option
+	}
+
+	// Routes for Low Stock Alerts
+	alertsGroup := inventoryGroup.Group("/alerts")
+	{
+		alertsGroup.GET("/", services_inventory.GetStockAlerts)                              // Get alerts, supports ?status=
+		alertsGroup.POST("/:alert_id/acknowledge", services_inventory.AcknowledgeStockAlert) // Acknowledge an alert
+	}
+
+	// Routes for items 86'd for the rest of the day
+	eightySixGroup := inventoryGroup.Group("/86")
+	{
+		eightySixGroup.GET("/", services_inventory.GetEightySixedItems)               // Get the items 86'd today
+		eightySixGroup.POST("/", services_inventory.EightySixItem)                    // 86 an item
+		eightySixGroup.DELETE("/:item_id", services_inventory.RestoreEightySixedItem) // Bring back an item 86'd today
+	}
+
+	// Routes for Ingredients
+	ingredientsGroup := inventoryGroup.Group("/ingredients")
+	{
+		ingredientsGroup.GET("/", services_inventory.GetIngredients)                                 // Get ingredients, supports ?low=true
+		ingredientsGroup.POST("/", services_inventory.CreateIngredient)                              // Create an ingredient
+		ingredientsGroup.PUT("/:ingredient_id", services_inventory.UpdateIngredient)                 // Update an ingredient
+		ingredientsGroup.DELETE("/:ingredient_id", services_inventory.DeleteIngredient)              // Delete an ingredient
+		ingredientsGroup.POST("/:ingredient_id/count", services_inventory.CountIngredient)           // Record a physical count
+		ingredientsGroup.POST("/:ingredient_id/wastage", services_inventory.AddWastage)              // Record wastage
+		ingredientsGroup.GET("/:ingredient_id/movements", services_inventory.GetIngredientMovements) // Get the latest stock changes
+	}
+
+	// Routes for Recipes
+	recipesGroup := inventoryGroup.Group("/recipes")
+	{
+		recipesGroup.GET("/items/:item_id", services_inventory.GetRecipe) // Get the recipe and food cost of an item
+		recipesGroup.PUT("/items/:item_id", services_inventory.SetRecipe) // Replace the recipe of an item
+	}
+
+	// Routes for Suppliers
+	suppliersGroup := inventoryGroup.Group("/suppliers")
+	{
+		suppliersGroup.GET("/", services_inventory.GetSuppliers)                  // Get suppliers
+		suppliersGroup.POST("/", services_inventory.CreateSupplier)               // Create a supplier
+		suppliersGroup.PUT("/:supplier_id", services_inventory.UpdateSupplier)    // Update a supplier
+		suppliersGroup.DELETE("/:supplier_id", services_inventory.DeleteSupplier) // Delete a supplier
+	}
+
+	// Routes for Purchase Receipts
+	receiptsGroup := inventoryGroup.Group("/receipts")
+	{
+		receiptsGroup.GET("/", services_inventory.GetPurchaseReceipts)           // Get receipts, supports ?supplier_id=
+		receiptsGroup.POST("/", services_inventory.CreatePurchaseReceipt)        // Receive ingredients from a supplier
+		receiptsGroup.GET("/:receipt_id", services_inventory.GetPurchaseReceipt) // Get a receipt with its lines
+	}
+
+	// Routes for Food Cost Reports
+	reportsGroup := inventoryGroup.Group("/reports")
+	{
+		reportsGroup.GET("/consumption", services_inventory.GetConsumptionReport) // Theoretical vs actual ingredient use
+		reportsGroup.GET("/margins", services_inventory.GetMarginReport)          // Revenue, food cost and margin per item
+	}
+}
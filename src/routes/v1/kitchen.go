@@ -0,0 +1,27 @@
+package routes_v1
+
+import (
+	middleware "dine-server/src/api/v1/middleware"
+	services_kitchen "dine-server/src/api/v1/services/kitchen"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupKitchenRoutes(kitchenGroup *gin.RouterGroup) {
+	// Routes for Stations
+	stationsGroup := kitchenGroup.Group("/stations")
+	{
+		stationsGroup.POST("/", services_kitchen.CreateStation)                                                 // Create a station
+		stationsGroup.GET("/", services_kitchen.GetStations)                                                    // Get all stations of the restaurant
+		stationsGroup.PUT("/:station_id", services_kitchen.UpdateStation)                                       // Update a station by ID
+		stationsGroup.DELETE("/:station_id", middleware.InvalidateMenuTree, services_kitchen.DeleteStation)     // Delete a station by ID
+		stationsGroup.PUT("/:station_id/assign", middleware.InvalidateMenuTree, services_kitchen.AssignStation) // Map categories and items to a station
+	}
+
+	// Routes for Kitchen Order Tickets
+	ticketsGroup := kitchenGroup.Group("/tickets")
+	{
+		ticketsGroup.GET("/", services_kitchen.GetTickets)                          // Get tickets, supports ?station_id= and ?status=
+		ticketsGroup.PUT("/:ticket_id/status", services_kitchen.UpdateTicketStatus) // Update the status of a ticket
+	}
+}
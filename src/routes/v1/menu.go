@@ -2,37 +2,74 @@ package routes_v1
 
 import (
 	middleware "dine-server/src/api/v1/middleware"
+	services_images "dine-server/src/api/v1/services/images"
 	services_menu "dine-server/src/api/v1/services/menus"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupMenuRoutes(menuGroup *gin.RouterGroup) {
+	// Any successful write drops the cached menu tree of the restaurant
+	menuGroup.Use(middleware.InvalidateMenuTree)
+
 	// Routes for Menus
-	menuGroup.POST("/", middleware.Authenticate, services_menu.CreateMenu) // Create a menu
-	menuGroup.GET("/", services_menu.GetMenus)                             // Get all menus, supports ?restaurant_id=
-	menuGroup.GET("/:menu_id", services_menu.GetMenuByID)                  // Get a specific menu by ID
-	menuGroup.PUT("/:menu_id", services_menu.UpdateMenu)                   // Update a menu by ID
-	menuGroup.DELETE("/:menu_id", services_menu.DeleteMenu)                // Delete a menu by ID
+	menuGroup.GET("/tree", services_menu.GetMenuTree)                                   // Get menus, categories, items and options in one call, supports ?at= and ETag
+	menuGroup.POST("/", services_menu.CreateMenu)                                       // Create a menu
+	menuGroup.GET("/", services_menu.GetMenus)                                          // Get all menus, guests only get the menus served now, supports ?at=
+	menuGroup.GET("/:menu_id", services_menu.GetMenuByID)                               // Get a specific menu by ID, supports ?at=
+	menuGroup.PUT("/:menu_id", services_menu.UpdateMenu)                                // Update a menu by ID
+	menuGroup.DELETE("/:menu_id", services_menu.DeleteMenu)                             // Delete a menu by ID
+	menuGroup.PUT("/:menu_id/schedules", services_menu.SetMenuSchedules)                // Set when a menu is served
+	menuGroup.POST("/:menu_id/import", services_menu.ImportMenu)                        // Import categories, items and options from CSV, XLSX or JSON, supports ?dry_run=true
+	menuGroup.GET("/:menu_id/export", services_menu.ExportMenu)                         // Export a menu as CSV, XLSX or JSON with ?format=
+	menuGroup.POST("/:menu_id/clone", services_menu.CloneMenu)                          // Copy a menu into this or another restaurant, optionally adjusting prices
+	menuGroup.POST("/from-template/:template_id", services_menu.CreateMenuFromTemplate) // Create a menu from a published template
+
+	// Nested Routes: Combos under a Menu
+	combosGroup := menuGroup.Group("/:menu_id/combos")
+	{
+		combosGroup.POST("/", services_menu.CreateCombo)            // Create a combo with its slots
+		combosGroup.GET("/", services_menu.GetCombos)               // Get all combos of a menu
+		combosGroup.GET("/:combo_id", services_menu.GetComboByID)   // Get a specific combo by ID
+		combosGroup.PUT("/:combo_id", services_menu.UpdateCombo)    // Update a combo, replacing its slots when given
+		combosGroup.DELETE("/:combo_id", services_menu.DeleteCombo) // Delete a combo
+	}
 
 	// Nested Routes: Categories under a Menu
 	categoriesGroup := menuGroup.Group("/:menu_id/categories")
 	{
-		categoriesGroup.POST("/", middleware.Authenticate, services_menu.CreateMenuCategory) // Create a category for a specific menu
-		categoriesGroup.GET("/", services_menu.GetMenuCategories)                            // Get all categories for a specific menu
-		categoriesGroup.GET("/:category_id", services_menu.GetMenuCategoryByID)              // Get a specific category by ID
-		categoriesGroup.PUT("/:category_id", services_menu.UpdateMenuCategory)               // Update a category by ID
-		categoriesGroup.DELETE("/:category_id", services_menu.DeleteMenuCategory)            // Delete a category by ID
+		categoriesGroup.POST("/", services_menu.CreateMenuCategory)                        // Create a category for a specific menu
+		categoriesGroup.PUT("/order", services_menu.ReorderMenuCategories)                 // Set the display order of the categories
+		categoriesGroup.GET("/", services_menu.GetMenuCategories)                          // Get all categories for a specific menu
+		categoriesGroup.GET("/:category_id", services_menu.GetMenuCategoryByID)            // Get a specific category by ID
+		categoriesGroup.PUT("/:category_id", services_menu.UpdateMenuCategory)             // Update a category by ID
+		categoriesGroup.DELETE("/:category_id", services_menu.DeleteMenuCategory)          // Delete a category by ID
+		categoriesGroup.PUT("/:category_id/schedules", services_menu.SetCategorySchedules) // Set when a category is served
+		categoriesGroup.POST("/:category_id/image", services_images.UploadCategoryImage)   // Upload the category image as multipart "image"
+		categoriesGroup.DELETE("/:category_id/image", services_images.DeleteCategoryImage) // Remove the category image
 	}
 
 	// Nested Routes: Items under a Category
 	itemsGroup := menuGroup.Group("/:menu_id/categories/:category_id/items")
 	{
-		itemsGroup.POST("/", middleware.Authenticate, services_menu.CreateMenuItem) // Create a menu item in a specific category
-		itemsGroup.GET("/", services_menu.GetMenuItems)                             // Get all items for a specific category
-		itemsGroup.GET("/:item_id", services_menu.GetMenuItemByID)                  // Get a specific item by ID
-		itemsGroup.PUT("/:item_id", services_menu.UpdateMenuItem)                   // Update a menu item by ID
-		itemsGroup.DELETE("/:item_id", services_menu.DeleteMenuItem)                // Delete a menu item by ID
+		itemsGroup.POST("/", services_menu.CreateMenuItem)                              // Create a menu item in a specific category
+		itemsGroup.POST("/bulk", services_menu.CreateMultipleMenuItems)                 // Create several menu items in a specific category
+		itemsGroup.PUT("/order", services_menu.ReorderMenuItems)                        // Set the display order of the items of a category
+		itemsGroup.GET("/", services_menu.GetMenuItems)                                 // Get all items for a specific category
+		itemsGroup.GET("/:item_id", services_menu.GetMenuItemByID)                      // Get a specific item by ID
+		itemsGroup.PUT("/:item_id", services_menu.UpdateMenuItem)                       // Update a menu item by ID
+		itemsGroup.DELETE("/:item_id", services_menu.DeleteMenuItem)                    // Delete a menu item by ID
+		itemsGroup.PUT("/:item_id/options/order", services_menu.ReorderMenuItemOptions) // Set the display order of the options of an item
+		itemsGroup.POST("/:item_id/image", services_images.UploadMenuItemImage)         // Upload the item image as multipart "image"
+		itemsGroup.DELETE("/:item_id/image", services_images.DeleteMenuItemImage)       // Remove the item image
+
+		itemsGroup.POST("/:item_id/modifier-groups", services_menu.CreateModifierGroup)                               // Add a modifier group to an item
+		itemsGroup.GET("/:item_id/modifier-groups", services_menu.GetModifierGroups)                                  // Get the modifier groups of an item
+		itemsGroup.PUT("/:item_id/modifier-groups/:group_id", services_menu.UpdateModifierGroup)                      // Update a modifier group
+		itemsGroup.DELETE("/:item_id/modifier-groups/:group_id", services_menu.DeleteModifierGroup)                   // Delete a modifier group
+		itemsGroup.POST("/:item_id/modifier-groups/:group_id/modifiers", services_menu.CreateModifier)                // Add a modifier to a group
+		itemsGroup.PUT("/:item_id/modifier-groups/:group_id/modifiers/:modifier_id", services_menu.UpdateModifier)    // Update a modifier
+		itemsGroup.DELETE("/:item_id/modifier-groups/:group_id/modifiers/:modifier_id", services_menu.DeleteModifier) // Delete a modifier
 	}
 
 }
@@ -0,0 +1,17 @@
+package routes_v1
+
+import (
+	middleware "dine-server/src/api/v1/middleware"
+	services_menu "dine-server/src/api/v1/services/menus"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMenuOverrideRoutes(overrideGroup *gin.RouterGroup) {
+	// Overrides are baked into the cached menu tree of the outlet
+	overrideGroup.Use(middleware.InvalidateMenuTree)
+
+	overrideGroup.GET("/", services_menu.GetMenuOverrides)                  // Get the overrides of the outlet
+	overrideGroup.PUT("/", services_menu.SetMenuOverride)                   // Set the availability of an item or the price of an option
+	overrideGroup.DELETE("/:override_id", services_menu.DeleteMenuOverride) // Follow the brand menu again
+}
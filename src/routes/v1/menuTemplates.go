@@ -0,0 +1,15 @@
+package routes_v1
+
+import (
+	services_menu "dine-server/src/api/v1/services/menus"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMenuTemplateRoutes(templateGroup *gin.RouterGroup) {
+	templateGroup.GET("/", services_menu.GetMenuTemplates)                  // Get the published templates, admins get all of them
+	templateGroup.GET("/:template_id", services_menu.GetMenuTemplate)       // Get a template with its menu
+	templateGroup.POST("/", services_menu.CreateMenuTemplate)               // Make a template from a restaurant menu
+	templateGroup.PUT("/:template_id", services_menu.UpdateMenuTemplate)    // Update or publish a template
+	templateGroup.DELETE("/:template_id", services_menu.DeleteMenuTemplate) // Delete a template
+}
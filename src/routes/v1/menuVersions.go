@@ -0,0 +1,21 @@
+package routes_v1
+
+import (
+	middleware "dine-server/src/api/v1/middleware"
+	services_menu "dine-server/src/api/v1/services/menus"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMenuVersionRoutes(versionGroup *gin.RouterGroup) {
+	// Publishing changes what guests see, so the cached menu tree is dropped
+	versionGroup.Use(middleware.InvalidateMenuTree)
+
+	versionGroup.POST("/", services_menu.CreateMenuVersion)                           // Save the current menus as a version, optionally publishing or scheduling it
+	versionGroup.GET("/", services_menu.GetMenuVersions)                              // List menu versions
+	versionGroup.GET("/diff", services_menu.DiffMenuVersions)                         // Compare two versions, the draft or the published version
+	versionGroup.GET("/:version_id", services_menu.GetMenuVersion)                    // Get a version with its menus
+	versionGroup.POST("/:version_id/publish", services_menu.PublishMenuVersion)       // Publish a version now or at a scheduled time
+	versionGroup.DELETE("/:version_id/schedule", services_menu.UnscheduleMenuVersion) // Cancel a scheduled publication
+	versionGroup.POST("/:version_id/rollback", services_menu.RollbackMenuVersion)     // Publish a previous version again
+}
@@ -3,6 +3,8 @@ package routes_v1
 import (
 	"dine-server/src/api/v1/middleware"
 	services_orders "dine-server/src/api/v1/services/orders"
+	"dine-server/src/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,7 +18,11 @@ func SetupOrderRoutes(orderGroup *gin.RouterGroup) {
 
 func restaurantOrderRoutes(orderRestaurantGroup *gin.RouterGroup) {
 
-	orderRestaurantGroup.POST("/", services_orders.CreateOrder)
+	// Open guest routes, limited per IP
+	orderRestaurantGroup.POST("/otp/send", middleware.RateLimitByIP(utils.NewRateLimiter(5, 10*time.Minute)), services_orders.SendGuestOTP)
+	orderRestaurantGroup.POST("/otp/verify", middleware.RateLimitByIP(utils.NewRateLimiter(10, 10*time.Minute)), services_orders.VerifyGuestOTP)
+	orderRestaurantGroup.POST("/", middleware.RateLimitByIP(utils.NewRateLimiter(20, 10*time.Minute)), services_orders.CreateOrder)
+
 	orderRestaurantGroup.GET("/", services_orders.ListOrders)
 	orderRestaurantGroup.GET("/:id", services_orders.GetOrder)
 	orderRestaurantGroup.PUT("/:id/status", services_orders.UpdateOrderStatus)
@@ -26,8 +32,13 @@ func restaurantOrderRoutes(orderRestaurantGroup *gin.RouterGroup) {
 
 func dineOrderRoutes(orderDineGroup *gin.RouterGroup) {
 
-	orderDineGroup.GET("/all", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_orders.GetDineOrders)
-	orderDineGroup.GET("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_orders.GetDineOrderByID)
-	orderDineGroup.GET("/", middleware.Authenticate, middleware.RoleMiddleware([]string{"restaurant_admin"}), services_orders.GetDineOrderByUsers)
+	orderDineGroup.GET("/all", services_orders.GetDineOrders)
+	orderDineGroup.GET("/:id", services_orders.GetDineOrderByID)
+	orderDineGroup.GET("/", services_orders.GetDineOrderByUsers)
+
+}
 
+func SetupOrderRuleRoutes(orderRuleGroup *gin.RouterGroup) {
+	orderRuleGroup.GET("/", services_orders.GetOrderRules)    // Get the auto-reject rules of a restaurant
+	orderRuleGroup.PUT("/", services_orders.UpdateOrderRules) // Update the auto-reject rules of a restaurant
 }
@@ -1,7 +1,6 @@
 package routes_v1
 
 import (
-	middleware "dine-server/src/api/v1/middleware"
 	services_plan "dine-server/src/api/v1/services/plans"
 
 	"github.com/gin-gonic/gin"
@@ -14,16 +13,16 @@ func SetupPlanRoutes(PlanGroup *gin.RouterGroup) {
 	PlanGroup.GET("/:id", services_plan.GetPlanByID)
 
 	//Only accessible by admin
-	PlanGroup.POST("/", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.CreatePlan)
-	PlanGroup.GET("/all", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.GetAllPlans)
-	PlanGroup.PUT("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.UpdatePlan)
-	PlanGroup.DELETE("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.DeletePlan)
-	PlanGroup.PUT("/add-feature", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.AddPlanFeature)
-	PlanGroup.PUT("/remove-feature", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.RemovePlanFeature)
+	PlanGroup.POST("/", services_plan.CreatePlan)
+	PlanGroup.GET("/all", services_plan.GetAllPlans)
+	PlanGroup.PUT("/:id", services_plan.UpdatePlan)
+	PlanGroup.DELETE("/:id", services_plan.DeletePlan)
+	PlanGroup.PUT("/add-feature", services_plan.AddPlanFeature)
+	PlanGroup.PUT("/remove-feature", services_plan.RemovePlanFeature)
 	// Plan Features
-	PlanGroup.POST("/feature", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.CreatePlanFeatures)
-	PlanGroup.GET("/feature", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.GetAllPlanFeatures)
-	PlanGroup.PUT("/feature/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.UpdatePlanFeatures)
-	PlanGroup.DELETE("/feature/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services_plan.DeletePlanFeatures)
+	PlanGroup.POST("/feature", services_plan.CreatePlanFeatures)
+	PlanGroup.GET("/feature", services_plan.GetAllPlanFeatures)
+	PlanGroup.PUT("/feature/:id", services_plan.UpdatePlanFeatures)
+	PlanGroup.DELETE("/feature/:id", services_plan.DeletePlanFeatures)
 
 }
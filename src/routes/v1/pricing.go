@@ -0,0 +1,14 @@
+package routes_v1
+
+import (
+	services_menu "dine-server/src/api/v1/services/menus"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPricingRuleRoutes(pricingGroup *gin.RouterGroup) {
+	pricingGroup.GET("/", services_menu.GetPricingRules)              // Get pricing rules, supports ?active=true and ?at=
+	pricingGroup.POST("/", services_menu.CreatePricingRule)           // Create a happy hour, surcharge or other pricing rule
+	pricingGroup.PUT("/:rule_id", services_menu.UpdatePricingRule)    // Update or pause a pricing rule
+	pricingGroup.DELETE("/:rule_id", services_menu.DeletePricingRule) // Delete a pricing rule
+}
@@ -2,6 +2,7 @@ package routes_v1
 
 import (
 	middleware "dine-server/src/api/v1/middleware"
+	services_images "dine-server/src/api/v1/services/images"
 	services "dine-server/src/api/v1/services/restaurants"
 
 	"github.com/gin-gonic/gin"
@@ -9,14 +10,17 @@ import (
 
 func SetupRestaurantRoutes(RestaurantRoutes *gin.RouterGroup) {
 	RestaurantRoutes.GET("/get-all", services.GetAllRestaurants)
-	RestaurantRoutes.GET("/", middleware.Authenticate, services.GetRestaurants)                                                                         // Get all Restaurants
-	RestaurantRoutes.GET("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin", "restaurant_admin"}), services.GetRestaurantByID) // Get a Restaurant by ID
+	RestaurantRoutes.GET("/", services.GetRestaurants)       // Get all Restaurants
+	RestaurantRoutes.GET("/:id", services.GetRestaurantByID) // Get a Restaurant by ID
 
-	RestaurantRoutes.POST("/", middleware.Authenticate, services.CreateRestaurant) // Create a Restaurant
+	RestaurantRoutes.POST("/", middleware.RequireVerifiedUser, services.CreateRestaurant) // Create a Restaurant, verification may be required
 
-	RestaurantRoutes.PUT("/:id", middleware.Authenticate, services.UpdateRestaurant) // Update a Restaurant by ID
+	RestaurantRoutes.PUT("/:id", services.UpdateRestaurant) // Update a Restaurant by ID
 
-	RestaurantRoutes.DELETE("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin", "restaurant_admin"}), services.DeleteRestaurant) // Delete a Restaurant by ID
+	RestaurantRoutes.DELETE("/:id", services.DeleteRestaurant) // Delete a Restaurant by ID
+
+	RestaurantRoutes.POST("/:id/images/:kind", services_images.UploadRestaurantImage)   // Upload the logo or banner as multipart "image"
+	RestaurantRoutes.DELETE("/:id/images/:kind", services_images.DeleteRestaurantImage) // Remove the logo or banner
 
 	RestaurantRoutes.POST("/bank-account", services.ConnectRestaurantBankAccount)
 
@@ -0,0 +1,16 @@
+package routes_v1
+
+import (
+	services "dine-server/src/api/v1/services/restaurants"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupScheduleRoutes(scheduleGroup *gin.RouterGroup) {
+	scheduleGroup.GET("/", services.GetSchedule) // Get opening hours, closures and whether the restaurant is open now
+
+	scheduleGroup.PUT("/", services.UpdateSchedule)                       // Update the timezone and weekly opening hours
+	scheduleGroup.PUT("/pause", services.PauseOrders)                     // Pause or resume taking orders
+	scheduleGroup.POST("/closures", services.AddClosure)                  // Close the restaurant for a holiday
+	scheduleGroup.DELETE("/closures/:closure_id", services.DeleteClosure) // Remove a closure
+}
@@ -0,0 +1,20 @@
+package routes_v1
+
+import (
+	services_orders "dine-server/src/api/v1/services/orders"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTableSessionRoutes(sessionGroup *gin.RouterGroup) {
+	// Open routes used by guests paying their share and by Razorpay
+	sessionGroup.POST("/:session_id/payments/:payment_id/online", services_orders.PaySessionPaymentOnline) // Get a payment link for a bill share
+	sessionGroup.GET("/payments/callback", services_orders.SessionPaymentCallback)                         // Razorpay callback of a bill share
+
+	sessionGroup.POST("/", services_orders.OpenTableSession)                                               // Open a tab on a table
+	sessionGroup.GET("/", services_orders.ListTableSessions)                                               // List sessions, supports ?status= and ?table_id=
+	sessionGroup.GET("/:session_id", services_orders.GetTableSession)                                      // Get a session with its orders and payments
+	sessionGroup.POST("/:session_id/close", services_orders.CloseTableSession)                             // Close the tab into a single bill
+	sessionGroup.POST("/:session_id/split", services_orders.SplitTableSessionBill)                         // Split the bill equally, by item or by amount
+	sessionGroup.POST("/:session_id/payments/:payment_id/onsite", services_orders.PaySessionPaymentOnsite) // Record a share paid at the counter
+}
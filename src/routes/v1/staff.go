@@ -0,0 +1,20 @@
+package routes_v1
+
+import (
+	services_staff "dine-server/src/api/v1/services/staff"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupStaffRoutes(staffGroup *gin.RouterGroup) {
+	staffGroup.GET("/", services_staff.GetStaff)                                           // Get the owner and staff of the restaurant
+	staffGroup.PUT("/:member_id", services_staff.UpdateStaffMember)                        // Change the role of a member
+	staffGroup.DELETE("/:member_id", services_staff.RemoveStaffMember)                     // Remove a member from the staff
+	staffGroup.POST("/invitations", services_staff.InviteStaff)                            // Invite someone by email or phone
+	staffGroup.GET("/invitations", services_staff.GetStaffInvitations)                     // Get the pending invitations
+	staffGroup.DELETE("/invitations/:invitation_id", services_staff.RevokeStaffInvitation) // Revoke a pending invitation
+}
+
+func SetupInvitationRoutes(invitationGroup *gin.RouterGroup) {
+	invitationGroup.POST("/accept", services_staff.AcceptStaffInvitation) // Join the staff of a restaurant
+}
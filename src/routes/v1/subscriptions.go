@@ -1,7 +1,6 @@
 package routes_v1
 
 import (
-	"dine-server/src/api/v1/middleware"
 	services "dine-server/src/api/v1/services/subscriptions"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +8,8 @@ import (
 
 func SetupSubscriptionRoutes(subscriptionRoutes *gin.RouterGroup) {
 
-	// subscriptionRoutes.POST("/:payment_id", middleware.Authenticate, services.CreateSubscription)                                       // Create a subscription
-	subscriptionRoutes.GET("/", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services.GetAllSubscriptions)    // Get all subscriptions
-	subscriptionRoutes.GET("/:id", middleware.Authenticate, middleware.RoleMiddleware([]string{"admin"}), services.GetSubscriptionByID) // Get a subscription by ID
+	// subscriptionRoutes.POST("/:payment_id", services.CreateSubscription)                                       // Create a subscription
+	subscriptionRoutes.GET("/", services.GetAllSubscriptions)    // Get all subscriptions
+	subscriptionRoutes.GET("/:id", services.GetSubscriptionByID) // Get a subscription by ID
 
 }
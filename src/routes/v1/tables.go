@@ -0,0 +1,22 @@
+package routes_v1
+
+import (
+	services "dine-server/src/api/v1/services/restaurants"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTableRoutes(tableGroup *gin.RouterGroup) {
+	// Open route used by the QR code on the table
+	tableGroup.GET("/scan", services.ScanTable) // Resolve a table token, supports ?token=
+
+	tableGroup.POST("/", services.CreateTable)                      // Create a table
+	tableGroup.GET("/", services.GetTables)                         // Get all tables, supports ?status= and ?area=
+	tableGroup.GET("/:table_id", services.GetTableByID)             // Get a specific table by ID
+	tableGroup.PUT("/:table_id", services.UpdateTable)              // Update a table by ID
+	tableGroup.PUT("/:table_id/status", services.UpdateTableStatus) // Update the status of a table
+	tableGroup.DELETE("/:table_id", services.DeleteTable)           // Delete a table by ID
+
+	tableGroup.GET("/:table_id/qr", services.GetTableQR)            // Get the QR token of a table, supports ?format=png
+	tableGroup.POST("/:table_id/qr/rotate", services.RotateTableQR) // Invalidate the printed QR code of a table
+}
@@ -0,0 +1,15 @@
+package routes_v1
+
+import (
+	services_menu "dine-server/src/api/v1/services/menus"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTranslationRoutes(translationGroup *gin.RouterGroup) {
+	translationGroup.GET("/", services_menu.GetTranslations)                   // Get the languages, supports ?locale= for their translations
+	translationGroup.PUT("/:locale", services_menu.SetTranslations)            // Add, change or remove translations
+	translationGroup.DELETE("/:locale", services_menu.DeleteTranslations)      // Remove a language
+	translationGroup.GET("/:locale/export", services_menu.ExportTranslations)  // Export the texts to translate, supports ?format=
+	translationGroup.POST("/:locale/import", services_menu.ImportTranslations) // Import a translation file, supports ?dry_run=true
+}
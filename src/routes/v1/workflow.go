@@ -9,7 +9,7 @@ import (
 
 func SetupWorkflowRoutes(workflowGroup *gin.RouterGroup) {
 
-	workflowGroup.POST("/plan/order-payment", middleware.Authenticate, middleware.RoleMiddleware([]string{"restaurant_admin"}), workflow.PlanOrderPayment)
-	workflowGroup.GET("/plan/payment-subscription", middleware.Authenticate, middleware.RoleMiddleware([]string{"restaurant_admin"}), workflow.VerifyPaymentAndSubscription)
+	workflowGroup.POST("/plan/order-payment", middleware.RequireVerifiedUser, workflow.PlanOrderPayment)
+	workflowGroup.GET("/plan/payment-subscription", workflow.VerifyPaymentAndSubscription)
 
 }
@@ -2,13 +2,16 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"dine-server/src/config/env"
 	models_user "dine-server/src/models/users"
 
+	"github.com/gofrs/uuid"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -42,6 +45,12 @@ func GenerateToken(user models_user.UserJwt, jwtType string) (string, error) {
 		return "", errors.New("invalid token age configuration: " + err.Error())
 	}
 
+	// Random token ID, so two tokens issued in the same second still differ.
+	tokenID, err := uuid.NewV4()
+	if err != nil {
+		return "", errors.New("failed to generate token ID: " + err.Error())
+	}
+
 	// Current time.
 	now := time.Now().UTC()
 
@@ -49,6 +58,8 @@ func GenerateToken(user models_user.UserJwt, jwtType string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    user.Role,
+		"sid":     user.SessionID,           // Session the token belongs to, revoking it revokes the token.
+		"jti":     tokenID.String(),         // Token ID.
 		"iat":     now.Unix(),               // Issued At time.
 		"exp":     now.Add(tokenAge).Unix(), // Expiration time.
 	})
@@ -62,12 +73,14 @@ func GenerateToken(user models_user.UserJwt, jwtType string) (string, error) {
 	return signedToken, nil
 }
 
-// ValidateAndExtractToken validates a JWT token and checks expiration.
-func ValidateAndExtractToken(tokenString string, jwtType string) (string, string, error) {
+// ValidateToken validates a JWT token and checks expiration. Whether its session is still active is checked by the caller.
+func ValidateToken(tokenString string, jwtType string) (models_user.UserJwt, error) {
+	var user models_user.UserJwt
+
 	// Fetch the secret key for the provided JWT type.
 	secretKey, err := fetchEnvVar(jwtType + "_TOKEN_SECRET")
 	if err != nil {
-		return "", "", err
+		return user, err
 	}
 
 	// Parse the token and validate its signature.
@@ -83,33 +96,33 @@ func ValidateAndExtractToken(tokenString string, jwtType string) (string, string
 
 	// Check parsing errors.
 	if err != nil {
-		return "", "", errors.New("failed to parse token: " + err.Error())
+		return user, errors.New("failed to parse token: " + err.Error())
 	}
 
 	// Validate token claims.
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return "", "", errors.New("invalid token claims")
+		return user, errors.New("invalid token claims")
 	}
 
 	// Check expiration explicitly.
 	exp, ok := claims["exp"].(float64)
 	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
-		return "", "", errors.New("token is expired")
+		return user, errors.New("token is expired")
 	}
 
-	// Extract user ID and role.
-	userID, ok := claims["user_id"].(string)
-	if !ok {
-		return "", "", errors.New("user_id claim is missing or invalid")
+	// Extract user ID, role and session.
+	if user.ID, ok = claims["user_id"].(string); !ok {
+		return user, errors.New("user_id claim is missing or invalid")
 	}
-
-	role, ok := claims["role"].(string)
-	if !ok {
-		return "", "", errors.New("role claim is missing or invalid")
+	if user.Role, ok = claims["role"].(string); !ok {
+		return user, errors.New("role claim is missing or invalid")
+	}
+	if user.SessionID, ok = claims["sid"].(string); !ok || user.SessionID == "" {
+		return user, errors.New("sid claim is missing or invalid")
 	}
 
-	return userID, role, nil
+	return user, nil
 }
 
 func GenerateState() string {
@@ -118,6 +131,21 @@ func GenerateState() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
+// GenerateSecretToken returns a random token sent to a user in a link, such as a staff invitation
+func GenerateSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashSecretToken hashes a secret token so only its hash is stored
+func HashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // HashPassword generates a bcrypt hash of the password
 func HashPassword(password string) (string, error) {
 	// bcrypt generates a salt and hashes the password with it
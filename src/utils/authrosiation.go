@@ -3,28 +3,58 @@ package utils
 import (
 	postgres "dine-server/src/config/database"
 	models_restaurant "dine-server/src/models/restaurants"
+	"errors"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
 )
 
-func IsAuthorised(c *gin.Context, restaurantID string) (bool, error) {
+// HasPermission reports whether the user of the request holds a permission in a restaurant.
+// Platform admins hold every permission, owners and staff the permissions of their role.
+func HasPermission(c *gin.Context, restaurantID string, permission models_restaurant.Permission) (bool, error) {
 	// Check if the role is admin
 	role, _ := c.Get("role")
 	if role == "admin" {
 		return true, nil
 	}
 
-	// If role is not admin, check if user has access to the restaurant
 	userID, exists := c.Get("userID")
 	if !exists {
 		return false, fmt.Errorf("unauthorized: user ID not found")
 	}
 
+	staffRole, err := StaffRole(restaurantID, fmt.Sprint(userID))
+	if err != nil {
+		return false, err
+	}
+	return staffRole.Can(permission), nil
+}
+
+// StaffRole returns the role of a user in a restaurant, empty when the user is not part of its staff
+func StaffRole(restaurantID, userID string) (models_restaurant.StaffRole, error) {
+	if _, err := uuid.FromString(restaurantID); err != nil {
+		return "", nil
+	}
+
 	var restaurant models_restaurant.Restaurant
-	if err := postgres.DB.Where("id = ? AND restaurant_admin_id = ?", restaurantID, userID).First(&restaurant).Error; err != nil {
-		return false, fmt.Errorf("forbidden: you are not allowed to access this restaurant")
+	if err := postgres.DB.Select("id", "admin_id").First(&restaurant, "id = ?", restaurantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if restaurant.AdminID.String() == userID {
+		return models_restaurant.StaffRoleOwner, nil
 	}
 
-	return false, nil
+	var member models_restaurant.StaffMember
+	if err := postgres.DB.Where("restaurant_id = ? AND user_id = ?", restaurantID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return member.Role, nil
 }
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	models_menu "dine-server/src/models/menu"
+	"fmt"
+	"strings"
+)
+
+// NormalizeMenuItemDietary cleans the diets and allergens of an item and checks them against the taxonomy
+// and against each other, e.g. a vegan item cannot contain dairy
+func NormalizeMenuItemDietary(item *models_menu.MenuItem) error {
+	diets, err := normalizeTags(item.Diets, models_menu.MenuDiets, "diet")
+	if err != nil {
+		return err
+	}
+	allergens, err := normalizeTags(item.Allergens, models_menu.MenuAllergens, "allergen")
+	if err != nil {
+		return err
+	}
+	item.Diets, item.Allergens = diets, allergens
+
+	if item.SpiceLevel < 0 || item.SpiceLevel > models_menu.MaxSpiceLevel {
+		return fmt.Errorf("spice_level must be between 0 and %d", models_menu.MaxSpiceLevel)
+	}
+	if nutrition := item.Nutrition; nutrition != nil {
+		if nutrition.Calories != nil && *nutrition.Calories < 0 {
+			return fmt.Errorf("calories cannot be negative")
+		}
+		for _, value := range []*float64{nutrition.Protein, nutrition.Carbohydrates, nutrition.Fat, nutrition.Sugar, nutrition.Sodium} {
+			if value != nil && *value < 0 {
+				return fmt.Errorf("nutrition values cannot be negative")
+			}
+		}
+		if len(nutrition.ServingSize) > 50 {
+			return fmt.Errorf("serving_size must be at most 50 characters")
+		}
+	}
+
+	contains := func(tags []string, tag string) bool {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Vegetarian follows the green mark, so eggs, fish and shellfish make an item non-veg
+	if item.IsVegetarian {
+		for _, allergen := range []string{models_menu.AllergenEgg, models_menu.AllergenFish, models_menu.AllergenShellfish} {
+			if contains(allergens, allergen) {
+				return fmt.Errorf("a vegetarian item cannot contain %s", allergen)
+			}
+		}
+	}
+
+	excluded := map[string][]string{
+		models_menu.DietVegan:      {models_menu.AllergenDairy, models_menu.AllergenEgg},
+		models_menu.DietJain:       {models_menu.AllergenEgg},
+		models_menu.DietGlutenFree: {models_menu.AllergenGluten},
+	}
+	for _, diet := range diets {
+		if (diet == models_menu.DietVegan || diet == models_menu.DietJain) && !item.IsVegetarian {
+			return fmt.Errorf("a %s item must be vegetarian", diet)
+		}
+		for _, allergen := range excluded[diet] {
+			if contains(allergens, allergen) {
+				return fmt.Errorf("a %s item cannot contain %s", diet, allergen)
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeTags lowercases and deduplicates tags, rejecting the ones not in allowed
+func normalizeTags(tags []string, allowed []string, kind string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+		known := false
+		for _, a := range allowed {
+			if a == tag {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown %s %q, expected one of %s", kind, tag, strings.Join(allowed, ", "))
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// MenuItemFilter keeps the items suitable for every diet and free of every excluded allergen
+type MenuItemFilter struct {
+	Diets            []string
+	ExcludeAllergens []string
+}
+
+// ParseMenuItemFilter reads the diet= and exclude_allergens= queries of menu endpoints.
+// Each value is a comma separated list, vegetarian is accepted as a diet.
+func ParseMenuItemFilter(diets, excludeAllergens []string) (MenuItemFilter, error) {
+	split := func(values []string) []string {
+		var tags []string
+		for _, value := range values {
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		return tags
+	}
+
+	var filter MenuItemFilter
+	var err error
+	if filter.Diets, err = normalizeTags(split(diets), append([]string{models_menu.DietVegetarian}, models_menu.MenuDiets...), "diet"); err != nil {
+		return MenuItemFilter{}, err
+	}
+	if filter.ExcludeAllergens, err = normalizeTags(split(excludeAllergens), models_menu.MenuAllergens, "allergen"); err != nil {
+		return MenuItemFilter{}, err
+	}
+	return filter, nil
+}
+
+// IsEmpty reports whether the filter keeps every item
+func (filter MenuItemFilter) IsEmpty() bool {
+	return len(filter.Diets) == 0 && len(filter.ExcludeAllergens) == 0
+}
+
+// Matches reports whether an item passes the filter
+func (filter MenuItemFilter) Matches(item models_menu.MenuItem) bool {
+	for _, diet := range filter.Diets {
+		if diet == models_menu.DietVegetarian {
+			if !item.IsVegetarian {
+				return false
+			}
+			continue
+		}
+		suitable := false
+		for _, d := range item.Diets {
+			if d == diet {
+				suitable = true
+				break
+			}
+		}
+		if !suitable {
+			return false
+		}
+	}
+	for _, excluded := range filter.ExcludeAllergens {
+		for _, allergen := range item.Allergens {
+			if allergen == excluded {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NonVegItems returns the names of the items of the menus that are not vegetarian
+func NonVegItems(menus []models_menu.Menu) []string {
+	names := []string{}
+	for _, menu := range menus {
+		for _, category := range menu.Categories {
+			for _, item := range category.MenuItems {
+				if !item.IsVegetarian {
+					names = append(names, item.Name)
+				}
+			}
+		}
+	}
+	return names
+}
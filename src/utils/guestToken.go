@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// GenerateOTP returns a random numeric one-time code of the given length
+func GenerateOTP(length int) (string, error) {
+	code := ""
+	for i := 0; i < length; i++ {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code += digit.String()
+	}
+	return code, nil
+}
+
+// HashOTP hashes a one-time code so it is never stored in clear text
+func HashOTP(code string) (string, error) {
+	secretKey, err := fetchEnvVar("GUEST_TOKEN_SECRET")
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// signGuestPayload returns the HMAC signature of a guest token payload
+func signGuestPayload(payload string) (string, error) {
+	secretKey, err := fetchEnvVar("GUEST_TOKEN_SECRET")
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// GenerateGuestToken creates the token handed to a guest after verifying their phone.
+// It lets the guest place orders at one restaurant until it expires.
+func GenerateGuestToken(restaurantID uuid.UUID, phone string, age time.Duration) (string, error) {
+	payload := fmt.Sprintf("%s:%s:%d", restaurantID, phone, time.Now().Add(age).Unix())
+
+	signature, err := signGuestPayload(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature, nil
+}
+
+// ParseGuestToken validates a guest token and returns the restaurant and phone it was issued for.
+func ParseGuestToken(token string) (uuid.UUID, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return uuid.Nil, "", errors.New("malformed guest token")
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return uuid.Nil, "", errors.New("malformed guest token")
+	}
+	payload := string(payloadBytes)
+
+	signature, err := signGuestPayload(payload)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(parts[1])) {
+		return uuid.Nil, "", errors.New("invalid guest token signature")
+	}
+
+	fields := strings.Split(payload, ":")
+	if len(fields) != 3 {
+		return uuid.Nil, "", errors.New("malformed guest token")
+	}
+
+	restaurantID, err := uuid.FromString(fields[0])
+	if err != nil {
+		return uuid.Nil, "", errors.New("malformed guest token")
+	}
+	expiresAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return uuid.Nil, "", errors.New("malformed guest token")
+	}
+	if time.Now().Unix() > expiresAt {
+		return uuid.Nil, "", errors.New("guest token has expired")
+	}
+
+	return restaurantID, fields[1], nil
+}
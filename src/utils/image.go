@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Registers the WebP decoder
+)
+
+const (
+	MaxImageSize      = 5 << 20 // Largest upload accepted, in bytes
+	MaxImagePixels    = 40_000_000
+	MaxImageDimension = 1600 // Larger images are scaled down to this width or height
+	ThumbnailSize     = 320  // Width or height of thumbnails
+)
+
+// ErrUnsupportedImage is returned for uploads that are not JPEG, PNG or WebP images
+var ErrUnsupportedImage = errors.New("unsupported image type, use JPEG, PNG or WebP")
+
+// ImageExtensions are the file extensions of the accepted image types
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ProcessedImage is an uploaded image ready to be stored, with its thumbnail
+type ProcessedImage struct {
+	ContentType          string
+	Data                 []byte
+	Width                int
+	Height               int
+	ThumbnailContentType string
+	Thumbnail            []byte
+}
+
+// ProcessImage checks an uploaded image, scales it down when it is too large and makes its thumbnail.
+// The type is taken from the content, not from the name or headers sent by the client.
+func ProcessImage(data []byte) (ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := ImageExtensions[contentType]; !ok {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	// Check the size before decoding so a small file cannot claim a huge image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("invalid image: %s", err.Error())
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return ProcessedImage{}, fmt.Errorf("image is too large, at most %d pixels", MaxImagePixels)
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("invalid image: %s", err.Error())
+	}
+
+	processed := ProcessedImage{ContentType: contentType, Data: data, Width: config.Width, Height: config.Height}
+
+	if config.Width > MaxImageDimension || config.Height > MaxImageDimension {
+		resized := scaleImage(source, MaxImageDimension)
+		processed.ContentType, processed.Data, err = encodeImage(resized, contentType)
+		if err != nil {
+			return ProcessedImage{}, err
+		}
+		processed.Width, processed.Height = resized.Bounds().Dx(), resized.Bounds().Dy()
+	}
+
+	processed.ThumbnailContentType, processed.Thumbnail, err = encodeImage(scaleImage(source, ThumbnailSize), contentType)
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+	return processed, nil
+}
+
+// scaleImage fits an image within size x size, keeping its proportions. Smaller images are returned as they are.
+func scaleImage(source image.Image, size int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return source
+	}
+	if width >= height {
+		width, height = size, max(1, height*size/width)
+	} else {
+		width, height = max(1, width*size/height), size
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, bounds, draw.Src, nil)
+	return scaled
+}
+
+// encodeImage writes PNG images as PNG to keep their transparency, and everything else as JPEG
+func encodeImage(img image.Image, contentType string) (string, []byte, error) {
+	var buffer bytes.Buffer
+	if contentType == "image/png" {
+		if err := png.Encode(&buffer, img); err != nil {
+			return "", nil, err
+		}
+		return "image/png", buffer.Bytes(), nil
+	}
+
+	// JPEG has no transparency, transparent parts of WebP images become white
+	flattened := image.NewRGBA(img.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), img, img.Bounds().Min, draw.Over)
+	if err := jpeg.Encode(&buffer, flattened, &jpeg.Options{Quality: 85}); err != nil {
+		return "", nil, err
+	}
+	return "image/jpeg", buffer.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is the language of restaurants that did not pick one
+const DefaultLanguage = "en"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// NormalizeLocale lowercases a language tag like "pt_BR" into "pt-br", it returns "" when the tag is invalid
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if !localePattern.MatchString(locale) {
+		return ""
+	}
+	return locale
+}
+
+// NegotiateLanguage picks the language to answer in from the ?lang= value, then the Accept-Language header.
+// A requested "fr-ca" is served "fr" when only that is available, and the other way round.
+// The fallback is returned when none of the requested languages is available.
+func NegotiateLanguage(lang, acceptLanguage string, available []string, fallback string) string {
+	requested := acceptedLanguages(acceptLanguage)
+	if locale := NormalizeLocale(lang); locale != "" {
+		requested = append([]string{locale}, requested...)
+	}
+
+	for _, locale := range requested {
+		for _, candidate := range available {
+			if candidate == locale {
+				return candidate
+			}
+		}
+		base := strings.SplitN(locale, "-", 2)[0]
+		for _, candidate := range available {
+			if strings.SplitN(candidate, "-", 2)[0] == base {
+				return candidate
+			}
+		}
+	}
+	return fallback
+}
+
+// acceptedLanguages lists the languages of an Accept-Language header, most preferred first
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			languages = append(languages, weighted{locale, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].q > languages[j].q })
+
+	locales := make([]string, 0, len(languages))
+	for _, language := range languages {
+		locales = append(locales, language.locale)
+	}
+	return locales
+}
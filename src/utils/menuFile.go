@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"bytes"
+	models_menu "dine-server/src/models/menu"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats supported for menu import and export
+const (
+	MenuFormatCSV  = "csv"
+	MenuFormatXLSX = "xlsx"
+	MenuFormatJSON = "json"
+)
+
+// MenuFileColumns are the columns of CSV and XLSX menu files, in export order
+var MenuFileColumns = []string{"category", "category_description", "item", "description", "image_url", "is_vegetarian", "is_available", "option", "price"}
+
+// MenuFileFormat picks the format from an explicit value, a file name or a content type
+func MenuFileFormat(format, fileName, contentType string) (string, error) {
+	if format == "" && fileName != "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	}
+	if format == "" {
+		switch {
+		case strings.Contains(contentType, "csv"):
+			format = MenuFormatCSV
+		case strings.Contains(contentType, "spreadsheetml"):
+			format = MenuFormatXLSX
+		case strings.Contains(contentType, "json"):
+			format = MenuFormatJSON
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case MenuFormatCSV, MenuFormatXLSX, MenuFormatJSON:
+		return strings.ToLower(format), nil
+	}
+	return "", fmt.Errorf("unsupported format %q, use csv, xlsx or json", format)
+}
+
+// ParseMenuFile reads the rows of a menu file
+func ParseMenuFile(format string, r io.Reader) ([]models_menu.MenuImportRow, error) {
+	switch format {
+	case MenuFormatCSV, MenuFormatXLSX:
+		records, err := readRecords(format, r)
+		if err != nil {
+			return nil, err
+		}
+		return menuRowsFromRecords(records)
+	case MenuFormatJSON:
+		var menuFile models_menu.MenuFile
+		if err := json.NewDecoder(r).Decode(&menuFile); err != nil {
+			return nil, fmt.Errorf("invalid json file: %s", err.Error())
+		}
+		return menuRowsFromFile(menuFile), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// WriteMenuFile writes a menu in the given format
+func WriteMenuFile(format string, w io.Writer, menuFile models_menu.MenuFile) error {
+	switch format {
+	case MenuFormatCSV, MenuFormatXLSX:
+		return writeRecords(format, w, menuRecords(menuFile))
+	case MenuFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(menuFile)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// readRecords reads the lines of a CSV file or of the first sheet of an XLSX file
+func readRecords(format string, r io.Reader) ([][]string, error) {
+	if format == MenuFormatXLSX {
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xlsx file: %s", err.Error())
+		}
+		defer file.Close()
+		records, err := file.GetRows(file.GetSheetName(0))
+		if err != nil {
+			return nil, fmt.Errorf("invalid xlsx file: %s", err.Error())
+		}
+		return records, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv file: %s", err.Error())
+	}
+	return records, nil
+}
+
+// writeRecords writes lines as a CSV file or as the first sheet of an XLSX file
+func writeRecords(format string, w io.Writer, records [][]string) error {
+	if format == MenuFormatXLSX {
+		file := excelize.NewFile()
+		defer file.Close()
+		sheet := file.GetSheetName(0)
+		for i, record := range records {
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return err
+			}
+			values := make([]interface{}, len(record))
+			for j, value := range record {
+				values[j] = value
+			}
+			if err := file.SetSheetRow(sheet, cell, &values); err != nil {
+				return err
+			}
+		}
+		return file.Write(w)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// recordColumns maps the header line of a CSV or XLSX file to column indexes
+func recordColumns(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return columns
+}
+
+// MenuFileContentType returns the content type of a menu file format
+func MenuFileContentType(format string) string {
+	switch format {
+	case MenuFormatCSV:
+		return "text/csv"
+	case MenuFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/json"
+}
+
+// menuRowsFromRecords maps CSV or XLSX records to rows using the header line
+func menuRowsFromRecords(records [][]string) ([]models_menu.MenuImportRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	columns := recordColumns(records[0])
+	for _, required := range []string{"category", "item", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var rows []models_menu.MenuImportRow
+	for i, record := range records[1:] {
+		value := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		// Skip blank lines
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := models_menu.MenuImportRow{
+			Row:                 i + 2,
+			Category:            value("category"),
+			CategoryDescription: value("category_description"),
+			Item:                value("item"),
+			Description:         value("description"),
+			ImageURL:            value("image_url"),
+			Option:              value("option"),
+		}
+		for _, flag := range []struct {
+			column string
+			target **bool
+		}{{"is_vegetarian", &row.IsVegetarian}, {"is_available", &row.IsAvailable}} {
+			parsed, ok := parseMenuBool(value(flag.column))
+			if !ok {
+				row.Errors = append(row.Errors, models_menu.MenuImportError{Row: row.Row, Field: flag.column, Error: "must be true or false"})
+			}
+			*flag.target = parsed
+		}
+		if price := value("price"); price != "" {
+			parsed, err := strconv.ParseFloat(price, 64)
+			if err != nil {
+				row.Errors = append(row.Errors, models_menu.MenuImportError{Row: row.Row, Field: "price", Error: "must be a number"})
+			}
+			row.Price = parsed
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// menuRowsFromFile flattens a JSON menu file into rows, numbered by option
+func menuRowsFromFile(menuFile models_menu.MenuFile) []models_menu.MenuImportRow {
+	var rows []models_menu.MenuImportRow
+	for _, category := range menuFile.Categories {
+		for _, item := range category.Items {
+			row := models_menu.MenuImportRow{
+				Category:            category.Name,
+				CategoryDescription: category.Description,
+				Item:                item.Name,
+				Description:         item.Description,
+				ImageURL:            item.ImageURL,
+				IsVegetarian:        item.IsVegetarian,
+				IsAvailable:         item.IsAvailable,
+			}
+			if len(item.Options) == 0 {
+				row.Row = len(rows) + 1
+				rows = append(rows, row)
+				continue
+			}
+			for _, option := range item.Options {
+				row.Row = len(rows) + 1
+				row.Option = option.Name
+				row.Price = option.Price
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// menuRecords flattens a menu into CSV or XLSX records with a header line
+func menuRecords(menuFile models_menu.MenuFile) [][]string {
+	records := [][]string{MenuFileColumns}
+	formatBool := func(value *bool) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatBool(*value)
+	}
+
+	for _, category := range menuFile.Categories {
+		for _, item := range category.Items {
+			record := []string{category.Name, category.Description, item.Name, item.Description, item.ImageURL, formatBool(item.IsVegetarian), formatBool(item.IsAvailable)}
+			if len(item.Options) == 0 {
+				records = append(records, append(record, "", ""))
+				continue
+			}
+			for _, option := range item.Options {
+				records = append(records, append(append([]string{}, record...), option.Name, strconv.FormatFloat(option.Price, 'f', 2, 64)))
+			}
+		}
+	}
+	return records
+}
+
+// parseMenuBool reads yes/no style values. Empty values are nil, unknown values are not ok.
+func parseMenuBool(value string) (*bool, bool) {
+	var parsed bool
+	switch strings.ToLower(value) {
+	case "":
+		return nil, true
+	case "true", "yes", "y", "1", "veg":
+		parsed = true
+	case "false", "no", "n", "0", "non-veg":
+		parsed = false
+	default:
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// MenuFileBuffer returns a menu file as bytes, for responses that need the length up front
+func MenuFileBuffer(format string, menuFile models_menu.MenuFile) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := WriteMenuFile(format, &buffer, menuFile); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
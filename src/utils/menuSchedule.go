@@ -0,0 +1,77 @@
+package utils
+
+import (
+	models_menu "dine-server/src/models/menu"
+	"errors"
+	"time"
+)
+
+// MenuServedAt reports whether a menu or category with the given schedules is served at t.
+// t must be in the restaurant's timezone. Without schedules it is always served.
+func MenuServedAt(schedules []models_menu.MenuSchedule, t time.Time) bool {
+	if len(schedules) == 0 {
+		return true
+	}
+	for _, schedule := range schedules {
+		if menuScheduleMatches(schedule, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func menuScheduleMatches(schedule models_menu.MenuSchedule, t time.Time) bool {
+	date := t.Format("2006-01-02")
+	if schedule.StartDate != "" && date < schedule.StartDate {
+		return false
+	}
+	if schedule.EndDate != "" && date > schedule.EndDate {
+		return false
+	}
+
+	servedOn := func(day time.Weekday) bool {
+		if len(schedule.Days) == 0 {
+			return true
+		}
+		for _, d := range schedule.Days {
+			if d == int(day) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if schedule.StartTime == "" || schedule.EndTime == "" {
+		return servedOn(t.Weekday())
+	}
+
+	clock := t.Format("15:04")
+	if schedule.StartTime <= schedule.EndTime {
+		return servedOn(t.Weekday()) && clock >= schedule.StartTime && clock < schedule.EndTime
+	}
+
+	// The window runs past midnight, days refer to the day it starts
+	return (servedOn(t.Weekday()) && clock >= schedule.StartTime) ||
+		(servedOn(t.AddDate(0, 0, -1).Weekday()) && clock < schedule.EndTime)
+}
+
+// ParseMenuTime reads the at= override of menu endpoints in the given timezone.
+// It accepts RFC 3339 timestamps and local "YYYY-MM-DDTHH:MM" or "HH:MM" (today) values.
+func ParseMenuTime(value string, location *time.Location) (time.Time, error) {
+	now := time.Now().In(location)
+	if value == "" {
+		return now, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(location), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", value, location); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", value, location); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, location), nil
+	}
+
+	return now, errors.New("invalid at, expected RFC 3339, YYYY-MM-DDTHH:MM or HH:MM")
+}
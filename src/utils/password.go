@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"unicode"
+)
+
+// ValidatePasswordStrength checks a new password: 8 to 72 characters, bcrypt ignoring anything longer,
+// with a lowercase letter, an uppercase letter and a digit
+func ValidatePasswordStrength(password string) error {
+	if len(password) < 8 {
+		return errors.New("password must be at least 8 characters long")
+	}
+	if len(password) > 72 {
+		return errors.New("password must be at most 72 characters long")
+	}
+
+	var lower, upper, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !lower || !upper || !digit {
+		return errors.New("password must contain a lowercase letter, an uppercase letter and a digit")
+	}
+	return nil
+}
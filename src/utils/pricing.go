@@ -0,0 +1,64 @@
+package utils
+
+import (
+	models_menu "dine-server/src/models/menu"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// PricingRuleActiveAt reports whether a pricing rule applies at t, t must be in the restaurant's timezone
+func PricingRuleActiveAt(rule models_menu.PricingRule, t time.Time) bool {
+	if !rule.IsActive {
+		return false
+	}
+	return menuScheduleMatches(models_menu.MenuSchedule{
+		Days:      rule.Days,
+		StartTime: rule.StartTime,
+		EndTime:   rule.EndTime,
+		StartDate: rule.StartDate,
+		EndDate:   rule.EndDate,
+	}, t)
+}
+
+// PricingRuleFor returns the rule pricing an item at t, nil when none applies.
+// Rules on the item win over rules on its category, which win over rules on the whole restaurant.
+// Between rules of the same scope the first one given wins.
+func PricingRuleFor(rules []models_menu.PricingRule, itemID, categoryID uuid.UUID, t time.Time) *models_menu.PricingRule {
+	var best *models_menu.PricingRule
+	bestScope := 0
+	for i := range rules {
+		rule := &rules[i]
+
+		scope := 0
+		switch {
+		case rule.MenuItemID != nil:
+			if *rule.MenuItemID == itemID {
+				scope = 3
+			}
+		case rule.CategoryID != nil:
+			if *rule.CategoryID == categoryID {
+				scope = 2
+			}
+		default:
+			scope = 1
+		}
+
+		if scope > bestScope && PricingRuleActiveAt(*rule, t) {
+			best, bestScope = rule, scope
+		}
+	}
+	return best
+}
+
+// AdjustPrice applies a pricing rule to a price, a discount never takes it below zero
+func AdjustPrice(price float64, rule models_menu.PricingRule) float64 {
+	adjusted := price + rule.Value
+	if rule.Adjustment == models_menu.PriceAdjustmentPercentage {
+		adjusted = price * (1 + rule.Value/100)
+	}
+	if adjusted < 0 {
+		return 0
+	}
+	return RoundAmount(adjusted)
+}
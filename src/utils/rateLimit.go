@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter counts hits per key in fixed time windows.
+// State is kept in memory, so limits apply per server instance.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*rateWindow
+	swept   time.Time
+}
+
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewRateLimiter allows limit hits per key within each window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*rateWindow),
+		swept:   time.Now(),
+	}
+}
+
+// Allow records a hit for the key. When the limit is reached it returns false
+// and the time left until the key may try again.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop expired windows from time to time so the map does not grow forever
+	if now.Sub(l.swept) > l.window {
+		for k, w := range l.windows {
+			if now.After(w.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.swept = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.After(w.resetAt) {
+		l.windows[key] = &rateWindow{count: 1, resetAt: now.Add(l.window)}
+		return true, 0
+	}
+
+	if w.count >= l.limit {
+		return false, w.resetAt.Sub(now)
+	}
+
+	w.count++
+	return true, 0
+}
@@ -17,6 +17,7 @@ func RestaurantResponse(restaurant []models_restaurant.Restaurant) []models_rest
 			LogoImageUrl:   r.LogoImageUrl,
 			Phone:          r.Phone,
 			Email:          r.Email,
+			Language:       r.Language,
 		})
 	}
 	return res_restaurants
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	models_restaurant "dine-server/src/models/restaurants"
+	"sort"
+	"time"
+)
+
+// DefaultTimezone is used for restaurants without a valid timezone
+const DefaultTimezone = "Asia/Kolkata"
+
+// How far ahead NextOpeningAt looks for an opening shift
+const scheduleLookaheadDays = 60
+
+// OpeningSchedule holds everything needed to tell whether a restaurant takes orders
+type OpeningSchedule struct {
+	Location *time.Location
+	Paused   bool
+	Hours    []models_restaurant.OpeningHour
+	Closures []models_restaurant.RestaurantClosure
+}
+
+// RestaurantLocation returns the timezone of a restaurant
+func RestaurantLocation(restaurant models_restaurant.Restaurant) *time.Location {
+	location, err := time.LoadLocation(restaurant.Timezone)
+	if err != nil || restaurant.Timezone == "" {
+		location, _ = time.LoadLocation(DefaultTimezone)
+	}
+	return location
+}
+
+// NewOpeningSchedule builds the schedule of a restaurant in its own timezone
+func NewOpeningSchedule(restaurant models_restaurant.Restaurant, hours []models_restaurant.OpeningHour, closures []models_restaurant.RestaurantClosure) OpeningSchedule {
+	return OpeningSchedule{
+		Location: RestaurantLocation(restaurant),
+		Paused:   restaurant.OrdersPaused,
+		Hours:    hours,
+		Closures: closures,
+	}
+}
+
+// closedOn reports whether a closure covers the given day
+func (s OpeningSchedule) closedOn(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	for _, closure := range s.Closures {
+		if date >= closure.StartDate && date <= closure.EndDate {
+			return true
+		}
+	}
+	return false
+}
+
+// shifts returns the opening and closing times of the shifts starting on the given day
+func (s OpeningSchedule) shifts(day time.Time) [][2]time.Time {
+	var shifts [][2]time.Time
+	for _, hour := range s.Hours {
+		if hour.DayOfWeek != int(day.Weekday()) {
+			continue
+		}
+		opens, err := time.Parse("15:04", hour.OpensAt)
+		if err != nil {
+			continue
+		}
+		closes, err := time.Parse("15:04", hour.ClosesAt)
+		if err != nil {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), opens.Hour(), opens.Minute(), 0, 0, s.Location)
+		end := time.Date(day.Year(), day.Month(), day.Day(), closes.Hour(), closes.Minute(), 0, 0, s.Location)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		shifts = append(shifts, [2]time.Time{start, end})
+	}
+
+	sort.Slice(shifts, func(i, j int) bool { return shifts[i][0].Before(shifts[j][0]) })
+	return shifts
+}
+
+// IsOpenAt reports whether the restaurant is within a shift at the given time.
+// A restaurant without opening hours is always open. Closures apply to shifts starting on the closed day.
+func (s OpeningSchedule) IsOpenAt(t time.Time) bool {
+	if len(s.Hours) == 0 {
+		return !s.closedOn(t.In(s.Location))
+	}
+
+	local := t.In(s.Location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.Location)
+
+	// Shifts of the previous day can run past midnight
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if s.closedOn(day) {
+			continue
+		}
+		for _, shift := range s.shifts(day) {
+			if !local.Before(shift[0]) && local.Before(shift[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AcceptsOrdersAt reports whether orders can be placed at the given time
+func (s OpeningSchedule) AcceptsOrdersAt(t time.Time) bool {
+	return !s.Paused && s.IsOpenAt(t)
+}
+
+// NextOpeningAt returns the start of the next shift after the given time,
+// or nil when the restaurant is open or has no shift in the coming weeks.
+func (s OpeningSchedule) NextOpeningAt(t time.Time) *time.Time {
+	if s.IsOpenAt(t) {
+		return nil
+	}
+
+	local := t.In(s.Location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.Location)
+
+	for i := 0; i <= scheduleLookaheadDays; i++ {
+		day := today.AddDate(0, 0, i)
+		if s.closedOn(day) {
+			continue
+		}
+
+		// Without opening hours the restaurant opens again when the closure ends
+		if len(s.Hours) == 0 {
+			return &day
+		}
+
+		for _, shift := range s.shifts(day) {
+			if shift[0].After(local) {
+				return &shift[0]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"dine-server/src/config/env"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// signTablePayload returns the HMAC signature of a table token payload
+func signTablePayload(payload string) (string, error) {
+	secretKey, err := fetchEnvVar("TABLE_TOKEN_SECRET")
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// GenerateTableToken creates the signed token printed in a table's QR code.
+// The version lets a restaurant invalidate old QR codes of a table.
+func GenerateTableToken(restaurantID, tableID uuid.UUID, version int) (string, error) {
+	payload := fmt.Sprintf("%s:%s:%d", restaurantID, tableID, version)
+
+	signature, err := signTablePayload(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature, nil
+}
+
+// ParseTableToken validates a table token and returns the restaurant, table and version it was issued for.
+func ParseTableToken(token string) (uuid.UUID, uuid.UUID, int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+	payload := string(payloadBytes)
+
+	signature, err := signTablePayload(payload)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, 0, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(parts[1])) {
+		return uuid.Nil, uuid.Nil, 0, errors.New("invalid table token signature")
+	}
+
+	fields := strings.Split(payload, ":")
+	if len(fields) != 3 {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+
+	restaurantID, err := uuid.FromString(fields[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+	tableID, err := uuid.FromString(fields[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+	version, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, 0, errors.New("malformed table token")
+	}
+
+	return restaurantID, tableID, version, nil
+}
+
+// ClientURL builds an absolute URL on the customer facing frontend
+func ClientURL(path string) string {
+	if env.AppVar["ENVIRONMENT"] == "development" {
+		return "http://localhost:3000" + path
+	}
+	return "https://" + strings.TrimSuffix(env.AppVar["CLIENT_HOST"], "/") + path
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bytes"
+	models_restaurant "dine-server/src/models/restaurants"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TranslationFileColumns are the columns of CSV and XLSX translation files, in export order
+var TranslationFileColumns = []string{"entity_type", "entity_id", "field", "source", "value"}
+
+// ParseTranslationFile reads the rows of a translation file in one of the menu file formats.
+// The locale is only given by JSON files, it is empty for the others.
+func ParseTranslationFile(format string, r io.Reader) (string, []models_restaurant.TranslationRow, error) {
+	switch format {
+	case MenuFormatCSV, MenuFormatXLSX:
+		records, err := readRecords(format, r)
+		if err != nil {
+			return "", nil, err
+		}
+		rows, err := translationRowsFromRecords(records)
+		return "", rows, err
+	case MenuFormatJSON:
+		var file models_restaurant.TranslationFile
+		if err := json.NewDecoder(r).Decode(&file); err != nil {
+			return "", nil, fmt.Errorf("invalid json file: %s", err.Error())
+		}
+		for i := range file.Translations {
+			file.Translations[i].Row = i + 1
+		}
+		return file.Locale, file.Translations, nil
+	}
+	return "", nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// TranslationFileBuffer returns a translation file as bytes in one of the menu file formats
+func TranslationFileBuffer(format string, file models_restaurant.TranslationFile) ([]byte, error) {
+	var buffer bytes.Buffer
+	switch format {
+	case MenuFormatCSV, MenuFormatXLSX:
+		records := [][]string{TranslationFileColumns}
+		for _, row := range file.Translations {
+			records = append(records, []string{string(row.EntityType), row.EntityID, row.Field, row.Source, row.Value})
+		}
+		if err := writeRecords(format, &buffer, records); err != nil {
+			return nil, err
+		}
+	case MenuFormatJSON:
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return buffer.Bytes(), nil
+}
+
+// translationRowsFromRecords maps CSV or XLSX records to rows using the header line
+func translationRowsFromRecords(records [][]string) ([]models_restaurant.TranslationRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	columns := recordColumns(records[0])
+	for _, required := range []string{"entity_type", "entity_id", "field", "value"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var rows []models_restaurant.TranslationRow
+	for i, record := range records[1:] {
+		value := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		// Skip blank lines
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		rows = append(rows, models_restaurant.TranslationRow{
+			Row:        i + 2,
+			EntityType: models_restaurant.TranslationEntity(strings.ToLower(value("entity_type"))),
+			EntityID:   value("entity_id"),
+			Field:      strings.ToLower(value("field")),
+			Source:     value("source"),
+			Value:      value("value"),
+		})
+	}
+	return rows, nil
+}